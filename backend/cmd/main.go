@@ -1,22 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"net/http" 
 	"log"
-	"path/filepath"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
-	"strconv"
+	_ "time/tzdata" // 容器映像可能沒有時區資料
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/sessions"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"backend/internal/handlers"
 	"backend/internal/middleware"
 	"backend/internal/repository"
 	"backend/internal/services"
 	"backend/pkg/configs"
+	"backend/pkg/health"
+	"backend/pkg/i18n"
+	"backend/pkg/lifecycle"
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
+	"backend/pkg/migrations"
+	"backend/pkg/openapi"
+	"backend/pkg/ratelimit"
+	"backend/pkg/tracing"
 	"backend/internal/auth"
 	"backend/pkg/utils"
 )
@@ -24,95 +41,373 @@ import (
 func main() {
 	// 設定日誌
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	// 載入設定 (設定檔路徑可由 -config 參數或 CONFIG_FILE 環境變數指定)
+	configPath := flag.String("config", utils.GetEnv("CONFIG_FILE", ""), "設定檔路徑 (.yaml/.yml/.toml)")
+	flag.Parse()
+
+	// 子命令: migrate
+	if flag.Arg(0) == "migrate" {
+		runMigrate(*configPath, flag.Args()[1:])
+		return
+	}
+
 	log.Println("啟動服務...")
+	cfg, err := configs.Load(*configPath)
+	if err != nil {
+		log.Fatalf("載入設定失敗: %v", err)
+	}
+
+	// 設定結構化日誌 (JSON 輸出、請求 ID、敏感資料遮蔽)
+	if err := logging.Setup(os.Stdout, cfg.Logging.Format, cfg.Logging.Level, cfg.Logging.Components); err != nil {
+		log.Fatalf("日誌設定失敗: %v", err)
+	}
+
+	// 缺少翻譯時會改用預設語言，這裡只提出警告
+	for lang, keys := range i18n.Missing() {
+		slog.Warn("訊息目錄缺少翻譯", "language", lang, "keys", keys)
+	}
+
+	// 設定 OpenTelemetry 追蹤
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Enabled:     cfg.Tracing.Enabled,
+		ServiceName: cfg.Tracing.ServiceName,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("追蹤設定失敗: %v", err)
+	}
 
 	// 初始化資料庫連接
 	log.Println("連接資料庫...")
-	dbConfig := configs.DefaultDBConfig()
-	db, err := configs.SetupDB(dbConfig)
+	db, err := configs.SetupDB(&cfg.Database)
 	if err != nil {
 		log.Fatalf("資料庫初始化失敗: %v", err)
 	}
 
-	// 確保資料庫連接在程式結束時關閉
+	// 關閉流程依註冊的相反順序執行：HTTP 服務 -> 背景工作 -> 資料庫 -> 追蹤
+	shutdown := lifecycle.NewShutdown()
+	shutdown.Register("tracing", shutdownTracing)
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		log.Fatalf("獲取資料庫連接失敗: %v", err)
 	}
-	defer sqlDB.Close()
+	shutdown.Register("database", func(ctx context.Context) error {
+		return sqlDB.Close()
+	})
+
+	// 確認資料庫結構版本與程式一致
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatalf("載入遷移檔案失敗: %v", err)
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatalf("資料庫結構檢查失敗: %v", err)
+	}
 
 	// 初始化儲存庫
 	userRepo := repository.NewUserRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	staffRepo := repository.NewStaffRepository(db)
+	appointmentRepo := repository.NewAppointmentRepository(db)
+	visitRepo := repository.NewVisitRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	serviceRepo := repository.NewServiceRepository(db)
+	commissionRepo := repository.NewCommissionRepository(db)
+	prepaidRepo := repository.NewPrepaidRepository(db)
+	loyaltyRepo := repository.NewLoyaltyRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	customerRecordRepo := repository.NewCustomerRecordRepository(db)
+	sheetRepo, err := repository.NewSheetRepository(context.Background(), cfg.Sheets)
+	if err != nil {
+		log.Fatalf("試算表設定失敗: %v", err)
+	}
+
+	// 客戶資料讀取快取，避免每次搜尋都重新下載整份試算表
+	var customerSource repository.CustomerSource = sheetRepo
+	var sheetCache *repository.SheetCache
+	if cfg.Sheets.CacheTTL > 0 {
+		sheetCache = repository.NewSheetCache(sheetRepo,
+			time.Duration(cfg.Sheets.CacheTTL)*time.Second,
+			time.Duration(cfg.Sheets.RevisionCheckInterval)*time.Second,
+		)
+		customerSource = sheetCache
+	}
+
+	// 設定就緒檢查項目
+	healthRegistry := health.NewRegistry(3 * time.Second)
+	healthRegistry.Register("database", sqlDB.PingContext)
+	healthRegistry.Register("customer_sheet", health.Cached(30*time.Second, sheetRepo.Ping))
 
 	// 設定 Google Auth
-	clientSecretPath := filepath.Join("pkg", "configs", "client_secret.json")
-	googleAuth, err := auth.NewGoogleAuth(clientSecretPath)
+	googleAuth, err := auth.NewGoogleAuth(cfg.Google.ClientSecretPath)
 	if err != nil {
 		log.Fatalf("Google Auth 設定失敗: %v", err)
 	}
 
+	// 背景工作群組
+	workers := lifecycle.NewWorkerGroup()
+	shutdown.Register("background workers", workers.Shutdown)
+
 	// 設定服務層
-	authService := service.NewAuthService(googleAuth, userRepo)
+	auditService := service.NewAuditService(auditRepo)
+	authService := service.NewAuthService(googleAuth, userRepo, workers, auditService, cfg.Admin.Emails)
+	staffService := service.NewStaffService(staffRepo)
+	loyaltyService := service.NewLoyaltyService(loyaltyRepo, visitRepo, cfg.Loyalty, cfg.Business.Location())
+	visitService := service.NewVisitService(visitRepo, serviceRepo, staffRepo, prepaidRepo, inventoryRepo, loyaltyService, cfg.Business.Location())
+	appointmentService := service.NewAppointmentService(appointmentRepo, staffRepo, scheduleRepo, serviceRepo, visitRepo, visitService, cfg.Appointments, cfg.Business.Location())
+	scheduleService := service.NewScheduleService(scheduleRepo, staffRepo)
+	catalogService := service.NewCatalogService(serviceRepo)
+	commissionService := service.NewCommissionService(commissionRepo, visitRepo, staffRepo, serviceRepo, cfg.Business.Location())
+	availabilityService := service.NewAvailabilityService(scheduleRepo, staffRepo, serviceRepo, appointmentRepo, cfg.Business.Location(), cfg.Business.SlotInterval)
+	inventoryService := service.NewInventoryService(inventoryRepo, cfg.Inventory)
+	customerRecordService := service.NewCustomerRecordService(customerRecordRepo)
+	prepaidService := service.NewPrepaidService(prepaidRepo, serviceRepo, cfg.Prepaid.PackageValidityMonths, cfg.Prepaid.WalletValidityMonths, cfg.Business.Location())
+
+	// 定期將到期的套票與儲值金歸零
+	workers.Every("prepaid-expiry", time.Duration(cfg.Prepaid.ExpiryCheckInterval)*time.Second, prepaidService.ExpireDue)
+
+	// 定期將超過有效月數的點數記為失效
+	workers.Every("loyalty-expiry", time.Duration(cfg.Loyalty.ExpiryCheckInterval)*time.Second, loyaltyService.ExpireDue)
+
+	// 定期發送通知佇列中的通知
+	notifiers := newNotifiers(cfg.Notify)
+	outboxService := service.NewOutboxService(outboxRepo, notifiers, cfg.Outbox)
+	workers.Every("outbox", time.Duration(cfg.Outbox.PollInterval)*time.Second, outboxService.DispatchDue)
+
+	// 低庫存通知 (未設定管道時仍可由管理員查看低庫存商品)
+	if cfg.Inventory.LowStockChannel != "" {
+		workers.Every("low-stock", time.Duration(cfg.Inventory.LowStockCheckInterval)*time.Second, inventoryService.AlertLowStock)
+	}
+
+	// 生日與回流提醒 (未啟用時仍可由管理員預覽名單)
+	reminderChannel := cfg.Reminders.Channel
+	if _, ok := notifiers[reminderChannel]; !ok {
+		reminderChannel = ""
+	}
+	reminderService := service.NewReminderService(customerSource, visitRepo, reminderRepo, reminderChannel, cfg.Reminders, cfg.Business.Location())
+	if cfg.Reminders.Enabled {
+		workers.Every("reminders", time.Duration(cfg.Reminders.CheckInterval)*time.Second, reminderService.RunScheduled)
+	}
+
+	// 設定監控指標
+	metrics.RegisterDBStats(sqlDB, cfg.Database.DBName)
+	metrics.RegisterActiveSessions(func() float64 {
+		count, err := authService.CountActiveSessions(context.Background())
+		if err != nil {
+			log.Printf("計算有效會話失敗: %v", err)
+			return 0
+		}
+		return float64(count)
+	})
 
-	// 設定 session 存儲
-	// 注意: 在生產環境中，應使用加密的金鑰，並考慮使用 Redis 等外部存儲
-	sessionKey := []byte("verySecureSessionKey123456") // 生產環境應使用環境變數或密鑰管理服務
-	sessionStore := sessions.NewCookieStore(sessionKey)
-	sessionStore.Options = &sessions.Options{
-		Path:     "/",
-		MaxAge:   3600 * 7 * 30, // 預設 1 小時
-		HttpOnly: true,
-		Secure:   false, // 生產環境設為 true
-		SameSite: http.SameSiteLaxMode,
+	// 設定 session 存儲 (支援多組金鑰輪替)
+	sessionStore, err := auth.NewSessionStore(cfg.Session)
+	if err != nil {
+		log.Fatalf("會話存儲設定失敗: %v", err)
+	}
+
+	// 設定限流儲存 (多實例部署時使用 redis 共用限制)
+	var limiter ratelimit.Limiter = ratelimit.NewMemoryLimiter(10 * time.Minute)
+	if cfg.RateLimit.Backend == "redis" {
+		redisLimiter, err := ratelimit.NewRedisLimiter(cfg.RateLimit.RedisURL, "ratelimit:")
+		if err != nil {
+			log.Fatalf("限流設定失敗: %v", err)
+		}
+		shutdown.Register("rate limit store", redisLimiter.Close)
+		healthRegistry.Register("rate_limit_store", redisLimiter.Ping)
+		limiter = redisLimiter
+	}
+
+	// 載入 API 規格
+	apiSpec, err := openapi.Load(context.Background())
+	if err != nil {
+		log.Fatalf("API 規格載入失敗: %v", err)
+	}
+	apiSpecJSON, err := openapi.JSON(apiSpec)
+	if err != nil {
+		log.Fatalf("API 規格載入失敗: %v", err)
 	}
 
 	// 設定中間件
-	authMiddleware := middleware.NewAuthMiddleware(sessionKey, authService)
+	authMiddleware := middleware.NewAuthMiddleware(sessionStore, authService)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(limiter, cfg.RateLimit)
+	openAPIValidator, err := middleware.NewOpenAPIValidator(apiSpec, cfg.OpenAPI.ValidateRequests, cfg.OpenAPI.ValidateResponses)
+	if err != nil {
+		log.Fatalf("API 規格驗證設定失敗: %v", err)
+	}
 
 	// 設定處理器
-	authHandler := handlers.NewAuthHandler(authService, sessionStore)
+	authHandler := handlers.NewAuthHandler(authService, auditService, sessionStore, cfg.Session)
+	sheetHandler := handlers.NewSheetHandler(customerSource, auditService, loyaltyService, customerRecordService)
+	auditHandler := handlers.NewAuditHandler(auditService, cfg.Business.Location())
+	healthHandler := handlers.NewHealthHandler(healthRegistry)
+	staffHandler := handlers.NewStaffHandler(staffService)
+	appointmentHandler := handlers.NewAppointmentHandler(appointmentService, auditService, cfg.Business.Location())
+	visitHandler := handlers.NewVisitHandler(visitService, auditService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	availabilityHandler := handlers.NewAvailabilityHandler(availabilityService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	commissionHandler := handlers.NewCommissionHandler(commissionService, auditService)
+	prepaidHandler := handlers.NewPrepaidHandler(prepaidService, auditService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyService, auditService)
+	reminderHandler := handlers.NewReminderHandler(reminderService)
+	outboxHandler := handlers.NewOutboxHandler(outboxService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	customerRecordHandler := handlers.NewCustomerRecordHandler(customerRecordService, auditService)
+	openAPIHandler := handlers.NewOpenAPIHandler(apiSpecJSON)
 
-	// 創建 Gin 引擎
-	r := gin.Default()
+	// 創建 Gin 引擎 (以結構化請求日誌取代 gin 預設日誌)
+	r := gin.New()
+	r.Use(middleware.Recovery())
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Language())
+	r.Use(middleware.RequestLogger())
 	
 	// 設定可信代理
-	r.SetTrustedProxies([]string{"127.0.0.1", "172.16.0.0/12", "192.168.0.0/16"})
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("設定可信代理失敗: %v", err)
+	}
+
+	// 記錄請求次數與延遲
+	r.Use(metrics.GinMiddleware())
 
 	// 設定 CORS
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{ "http://localhost:4200", 
-        "http://frontend:4200",
-        utils.GetEnv("FRONTEND_URL", "http://localhost:4200")}, // 前端網址
+		AllowOrigins:     cfg.Server.CORSOrigins, // 前端網址
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Content-Language", middleware.RequestIDHeader},
 		AllowCredentials: true, // 允許攜帶憑證
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// 公開路由
-	r.POST("/api/login/google", authHandler.HandleGoogleSignIn)
-	r.GET("/api/logout", authHandler.HandleLogout)
-	r.GET("/api/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
-	// 受保護路由
-	api := r.Group("/api")
-	api.Use(authMiddleware.AuthRequired())
-	{
-		api.GET("/profile", authHandler.HandleGetProfile)
-		api.GET("/sheets", handlers.SearchCustomerHandler)
-		
-		// 可以添加更多受保護的路由
+	// 統一錯誤回應，須在日誌與指標中間件之後，才能記錄到實際狀態碼
+	r.Use(middleware.ErrorHandler())
+
+	// 限流 (預檢請求已由 CORS 處理，不計入)
+	if cfg.RateLimit.Enabled {
+		r.Use(rateLimitMiddleware.PerIP())
 	}
 
+	// 依 API 規格驗證請求 (與回應)
+	r.Use(openAPIValidator.Middleware())
+
+	// 健康檢查 (供 docker / 負載平衡器使用)
+	r.GET("/healthz", healthHandler.HandleLiveness)
+	r.GET("/readyz", healthHandler.HandleReadiness)
+
+	// API 規格與 Swagger UI
+	r.GET("/api/openapi.json", openAPIHandler.HandleSpec)
+	r.GET("/api/docs", openAPIHandler.HandleDocs)
+
+	// API 路由 (/api/v1 與遷移期間的舊路由)
+	routes := &apiRoutes{
+		auth:         authHandler,
+		sheet:        sheetHandler,
+		audit:        auditHandler,
+		health:       healthHandler,
+		staff:        staffHandler,
+		appointment:  appointmentHandler,
+		visit:        visitHandler,
+		schedule:     scheduleHandler,
+		availability: availabilityHandler,
+		catalog:      catalogHandler,
+		commission:   commissionHandler,
+		prepaid:      prepaidHandler,
+		loyalty:      loyaltyHandler,
+		reminder:     reminderHandler,
+		outbox:       outboxHandler,
+		inventory:    inventoryHandler,
+		customer:     customerRecordHandler,
+		protected:    []gin.HandlerFunc{authMiddleware.AuthRequired()},
+		admin:        []gin.HandlerFunc{authMiddleware.AdminRequired()},
+	}
+	if cfg.RateLimit.Enabled {
+		routes.protected = append(routes.protected, rateLimitMiddleware.PerUser())
+	}
+	if sheetCache != nil {
+		routes.sheetCache = handlers.NewSheetCacheHandler(sheetCache)
+	}
+	mountAPI(r, routes, cfg.API)
+
 	// 啟動服務器
-	port := utils.GetEnv("PORT", "8080")
-	portInt, _ := strconv.Atoi(port)
-	log.Printf("服務器啟動於 :%d", portInt)
-	if err := r.Run(fmt.Sprintf(":%s", port)); err != nil {
-		log.Fatalf("服務器啟動失敗: %v", err)
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	shutdown.Register("http server", srv.Shutdown)
+
+	// 監聽 SIGINT / SIGTERM (docker stop 會送出 SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 2)
+	go func() {
+		log.Printf("服務器啟動於 %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// Prometheus 監控指標使用獨立的監聽位址，不經由 API 埠對外公開
+	if cfg.Server.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", promhttp.Handler())
+		metricsSrv := &http.Server{
+			Addr:              cfg.Server.MetricsAddr,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		shutdown.Register("metrics server", metricsSrv.Shutdown)
+		if isLoopback(metricsSrv.Addr) {
+			slog.Warn("監控指標只監聽本機位址，容器外的 Prometheus 無法抓取；在 docker 中請設定 METRICS_ADDR=:9090", "addr", metricsSrv.Addr)
+		}
+		go func() {
+			log.Printf("監控指標服務啟動於 %s", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+	}
+
+	var startErr error
+	select {
+	case startErr = <-serverErr:
+		log.Printf("服務器啟動失敗: %v", startErr)
+	case <-ctx.Done():
+		log.Println("收到關閉信號，開始關閉服務...")
+	}
+	stop()
+
+	// 在逾時前完成進行中的請求與背景工作，再關閉資料庫
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := shutdown.Run(shutdownCtx); err != nil {
+		log.Fatalf("服務關閉未完全成功: %v", err)
+	}
+	if startErr != nil {
+		os.Exit(1)
+	}
+	log.Println("服務已關閉")
+}
+
+// isLoopback 判斷監聽位址是否只綁定本機
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
 	}
-}
\ No newline at end of file
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"backend/pkg/configs"
+	"backend/pkg/migrations"
+)
+
+const migrateUsage = `用法: backend [-config 檔案] migrate <指令>
+
+指令:
+  up              套用所有尚未套用的遷移
+  down [N]        回復最近 N 個遷移 (預設 1)
+  status          顯示各遷移版本的套用狀態
+  create <名稱>   在 pkg/migrations/sql 建立新的 up/down 遷移檔案`
+
+// runMigrate 執行 migrate 子命令
+func runMigrate(configPath string, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// create 只需要寫入檔案，不需要資料庫連接
+	if args[0] == "create" {
+		if len(args) < 2 {
+			log.Fatal("請提供遷移名稱，例如: migrate create add_customer_notes")
+		}
+		upPath, downPath, err := migrations.Create(migrations.DefaultDir, args[1])
+		if err != nil {
+			log.Fatalf("建立遷移檔案失敗: %v", err)
+		}
+		log.Printf("已建立 %s", upPath)
+		log.Printf("已建立 %s", downPath)
+		return
+	}
+
+	dbConfig, err := configs.LoadDatabase(configPath)
+	if err != nil {
+		log.Fatalf("載入設定失敗: %v", err)
+	}
+
+	db, err := configs.SetupDB(dbConfig)
+	if err != nil {
+		log.Fatalf("資料庫初始化失敗: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("獲取資料庫連接失敗: %v", err)
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatalf("載入遷移檔案失敗: %v", err)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("已套用 %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("遷移失敗: %v", err)
+		}
+		if len(applied) == 0 {
+			log.Println("資料庫已是最新版本")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatalf("回復數量必須為正整數: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			log.Printf("已回復 %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("回復失敗: %v", err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("查詢遷移狀態失敗: %v", err)
+		}
+		current, err := migrator.CurrentVersion(ctx)
+		if err != nil {
+			log.Fatalf("查詢結構版本失敗: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+		fmt.Printf("\n目前版本: %d，最新版本: %d\n", current, migrator.LatestVersion())
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/notify"
+)
+
+// newNotifiers 依設定建立可用的通知管道，鍵為管道名稱；log 管道永遠可用
+func newNotifiers(cfg configs.NotifyConfig) map[string]notify.Notifier {
+	client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+
+	notifiers := map[string]notify.Notifier{
+		notify.ChannelLog: notify.NewLogNotifier(logging.Component("notify")),
+	}
+	if cfg.FilePath != "" {
+		notifiers[notify.ChannelFile] = notify.NewFileNotifier(cfg.FilePath)
+	}
+	if cfg.Email.Host != "" {
+		notifiers[notify.ChannelEmail] = notify.NewEmailNotifier(cfg.Email.Host, cfg.Email.Port, cfg.Email.Username, cfg.Email.Password, cfg.Email.From)
+	}
+	if cfg.SMS.Endpoint != "" {
+		notifiers[notify.ChannelSMS] = notify.NewSMSNotifier(cfg.SMS.Endpoint, cfg.SMS.Username, cfg.SMS.Password, client)
+	}
+	if cfg.LINE.ChannelAccessToken != "" {
+		notifiers[notify.ChannelLINE] = notify.NewLINENotifier(cfg.LINE.ChannelAccessToken, client)
+	}
+	return notifiers
+}
@@ -0,0 +1,328 @@
+// openapi-ts 依 pkg/openapi/openapi.yaml 產生前端使用的 TypeScript 型別與 Angular 用戶端
+//
+// 使用方式 (於 backend 目錄)：
+//
+//	go run ./cmd/openapi-ts [-o ../frontend/src/app/api/api-client.ts]
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"backend/pkg/openapi"
+)
+
+func main() {
+	output := flag.String("o", filepath.Join("..", "frontend", "src", "app", "api", "api-client.ts"), "輸出檔案路徑")
+	flag.Parse()
+
+	doc, err := openapi.Load(context.Background())
+	if err != nil {
+		log.Fatalf("載入 API 規格失敗: %v", err)
+	}
+
+	var buf bytes.Buffer
+	g := &generator{doc: doc, buf: &buf}
+	if err := g.generate(); err != nil {
+		log.Fatalf("產生用戶端失敗: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*output), 0o755); err != nil {
+		log.Fatalf("建立輸出目錄失敗: %v", err)
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("寫入 %s 失敗: %v", *output, err)
+	}
+	fmt.Printf("已產生 %s\n", *output)
+}
+
+// pathParamPattern 路徑中的參數，例如 /api/customers/{id}
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// generator 輸出 TypeScript 原始碼
+type generator struct {
+	doc *openapi3.T
+	buf *bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(g.buf, format, args...)
+}
+
+func (g *generator) generate() error {
+	g.printf(`// 此檔案由 backend/cmd/openapi-ts 依 backend/pkg/openapi/openapi.yaml 產生，請勿手動修改
+// 重新產生：cd backend && go run ./cmd/openapi-ts
+
+import { Injectable, InjectionToken, inject } from '@angular/core';
+import { HttpClient, HttpParams } from '@angular/common/http';
+import { Observable } from 'rxjs';
+
+/** API 伺服器位址，預設為同源 (由 nginx 轉送 /api) */
+export const API_BASE_URL = new InjectionToken<string>('API_BASE_URL', {
+  providedIn: 'root',
+  factory: () => '',
+});
+
+`)
+
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.schema(name, g.doc.Components.Schemas[name].Value)
+	}
+
+	g.printf("@Injectable({ providedIn: 'root' })\nexport class ApiClient {\n")
+	g.printf("  private http = inject(HttpClient);\n  private baseUrl = inject(API_BASE_URL);\n")
+
+	// 以路徑字母順序輸出，讓產生結果穩定
+	paths := make([]string, 0, g.doc.Paths.Len())
+	for path := range g.doc.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := g.doc.Paths.Value(path)
+		methods := make([]string, 0, len(item.Operations()))
+		for method := range item.Operations() {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			if err := g.operation(path, method, item.Operations()[method], item.Parameters); err != nil {
+				return err
+			}
+		}
+	}
+	g.printf("}\n")
+
+	g.printf(`
+/** 將查詢參數轉為 HttpParams，略過未設定的值 */
+function toParams(query: object): HttpParams {
+  let params = new HttpParams();
+  for (const [key, value] of Object.entries(query)) {
+    if (value !== undefined && value !== null) {
+      params = params.set(key, String(value));
+    }
+  }
+  return params;
+}
+`)
+	return nil
+}
+
+// schema 輸出一個具名型別
+func (g *generator) schema(name string, schema *openapi3.Schema) {
+	g.comment("", schema.Description)
+	if schema.Type.Is(openapi3.TypeObject) && len(schema.Properties) > 0 {
+		g.printf("export interface %s %s\n\n", name, g.objectBody(schema, ""))
+		return
+	}
+	g.printf("export type %s = %s;\n\n", name, g.typeOf(&openapi3.SchemaRef{Value: schema}, ""))
+}
+
+// objectBody 輸出物件型別的內容
+func (g *generator) objectBody(schema *openapi3.Schema, indent string) string {
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop.Value != nil && prop.Value.Description != "" && prop.Ref == "" {
+			fmt.Fprintf(&b, "%s  /** %s */\n", indent, oneLine(prop.Value.Description))
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, name, optional, g.typeOf(prop, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// typeOf 將 schema 轉為 TypeScript 型別
+func (g *generator) typeOf(ref *openapi3.SchemaRef, indent string) string {
+	if ref == nil || ref.Value == nil {
+		return "unknown"
+	}
+	if ref.Ref != "" {
+		return ref.Ref[strings.LastIndex(ref.Ref, "/")+1:]
+	}
+
+	schema := ref.Value
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			values = append(values, fmt.Sprintf("'%v'", v))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	var result string
+	switch {
+	case schema.Type.Is(openapi3.TypeString):
+		result = "string"
+	case schema.Type.Is(openapi3.TypeInteger), schema.Type.Is(openapi3.TypeNumber):
+		result = "number"
+	case schema.Type.Is(openapi3.TypeBoolean):
+		result = "boolean"
+	case schema.Type.Is(openapi3.TypeArray):
+		item := g.typeOf(schema.Items, indent)
+		if strings.Contains(item, " | ") {
+			item = "(" + item + ")"
+		}
+		result = item + "[]"
+	case schema.Type.Is(openapi3.TypeObject):
+		switch {
+		case len(schema.Properties) > 0:
+			result = g.objectBody(schema, indent)
+		case schema.AdditionalProperties.Schema != nil:
+			result = "Record<string, " + g.typeOf(schema.AdditionalProperties.Schema, indent) + ">"
+		default:
+			result = "Record<string, unknown>"
+		}
+	default:
+		result = "unknown"
+	}
+
+	if schema.Nullable {
+		result += " | null"
+	}
+	return result
+}
+
+// operation 輸出一個 API 方法，只產生回應為 JSON 的操作
+func (g *generator) operation(path, method string, op *openapi3.Operation, shared openapi3.Parameters) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("%s %s 缺少 operationId", method, path)
+	}
+
+	responseType := g.successType(op)
+	if responseType == "" {
+		return nil
+	}
+
+	var args []string
+	urlPath := path
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		args = append(args, match[1]+": string")
+		urlPath = strings.ReplaceAll(urlPath, match[0], "${encodeURIComponent("+match[1]+")}")
+	}
+
+	var query []*openapi3.Parameter
+	queryRequired := false
+	for _, ref := range append(shared, op.Parameters...) {
+		if p := ref.Value; p != nil && p.In == openapi3.ParameterInQuery {
+			query = append(query, p)
+			queryRequired = queryRequired || p.Required
+		}
+	}
+
+	hasBody := false
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		if media := op.RequestBody.Value.Content.Get("application/json"); media != nil {
+			args = append(args, "body: "+g.typeOf(media.Schema, "  "))
+			hasBody = true
+		}
+	}
+
+	if len(query) > 0 {
+		var b strings.Builder
+		b.WriteString("{ ")
+		for i, p := range query {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			optional := "?"
+			if p.Required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "%s%s: %s", p.Name, optional, g.typeOf(p.Schema, "  "))
+		}
+		b.WriteString(" }")
+		if queryRequired {
+			args = append(args, "query: "+b.String())
+		} else {
+			args = append(args, "query: "+b.String()+" = {}")
+		}
+	}
+
+	options := "{ withCredentials: true }"
+	if len(query) > 0 {
+		options = "{ params: toParams(query), withCredentials: true }"
+	}
+
+	url := "`${this.baseUrl}" + urlPath + "`"
+	var call string
+	switch method {
+	case "GET", "DELETE":
+		call = fmt.Sprintf("this.http.%s<%s>(%s, %s)", strings.ToLower(method), responseType, url, options)
+	default:
+		body := "null"
+		if hasBody {
+			body = "body"
+		}
+		call = fmt.Sprintf("this.http.%s<%s>(%s, %s, %s)", strings.ToLower(method), responseType, url, body, options)
+	}
+
+	summary := op.Summary
+	if op.Deprecated {
+		summary += " @deprecated"
+	}
+	g.printf("\n")
+	g.comment("  ", summary)
+	g.printf("  %s(%s): Observable<%s> {\n    return %s;\n  }\n", op.OperationID, strings.Join(args, ", "), responseType, call)
+	return nil
+}
+
+// successType 返回 2xx JSON 回應的型別，沒有 JSON 回應時返回空字串
+func (g *generator) successType(op *openapi3.Operation) string {
+	for _, status := range []string{"200", "201", "202"} {
+		response := op.Responses.Value(status)
+		if response == nil || response.Value == nil {
+			continue
+		}
+		if media := response.Value.Content.Get("application/json"); media != nil {
+			return g.typeOf(media.Schema, "  ")
+		}
+	}
+	if response := op.Responses.Value("204"); response != nil {
+		return "void"
+	}
+	return ""
+}
+
+// comment 輸出 JSDoc 註解
+func (g *generator) comment(indent, text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		g.printf("%s/** %s */\n", indent, oneLine(text))
+	}
+}
+
+// oneLine 將多行說明合併為一行
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(text, "*/", "* /")), " ")
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/pkg/configs"
+)
+
+// apiRoutes 註冊 API 路由所需的處理器與中間件
+type apiRoutes struct {
+	auth         *handlers.AuthHandler
+	sheet        *handlers.SheetHandler
+	sheetCache   *handlers.SheetCacheHandler // 停用快取時為 nil
+	audit        *handlers.AuditHandler
+	health       *handlers.HealthHandler
+	staff        *handlers.StaffHandler
+	appointment  *handlers.AppointmentHandler
+	visit        *handlers.VisitHandler
+	schedule     *handlers.ScheduleHandler
+	availability *handlers.AvailabilityHandler
+	catalog      *handlers.CatalogHandler
+	commission   *handlers.CommissionHandler
+	prepaid      *handlers.PrepaidHandler
+	loyalty      *handlers.LoyaltyHandler
+	reminder     *handlers.ReminderHandler
+	outbox       *handlers.OutboxHandler
+	inventory    *handlers.InventoryHandler
+	customer     *handlers.CustomerRecordHandler
+
+	// protected 受保護路由的中間件 (身份驗證、使用者限流)
+	protected []gin.HandlerFunc
+	// admin 管理員路由額外的中間件
+	admin []gin.HandlerFunc
+}
+
+// apiVersion 一個 API 版本，掛載於 /api/<name>
+// 新增 v2 時加入一筆並實作其 register；舊版本可設定 deprecation 以附帶淘汰標頭
+type apiVersion struct {
+	name        string
+	register    func(api *gin.RouterGroup, routes *apiRoutes)
+	deprecation *middleware.Deprecation
+}
+
+// apiVersions 目前提供的 API 版本
+var apiVersions = []apiVersion{
+	{name: "v1", register: registerV1},
+}
+
+// currentAPIPrefix 舊路由遷移的目標版本
+const currentAPIPrefix = "/api/v1"
+
+// mountAPI 掛載所有 API 版本，以及遷移期間的舊路由
+func mountAPI(r *gin.Engine, routes *apiRoutes, cfg configs.APIConfig) {
+	for _, version := range apiVersions {
+		group := r.Group("/api/" + version.name)
+		if version.deprecation != nil {
+			group.Use(middleware.Deprecated(*version.deprecation))
+		}
+		version.register(group, routes)
+	}
+
+	if cfg.LegacyRoutes {
+		mountLegacy(r, routes, cfg)
+	}
+}
+
+// registerV1 註冊 v1 路由
+func registerV1(api *gin.RouterGroup, routes *apiRoutes) {
+	// 公開路由
+	api.POST("/login/google", routes.auth.HandleGoogleSignIn)
+	api.GET("/logout", routes.auth.HandleLogout)
+
+	// 受保護路由
+	protected := api.Group("", routes.protected...)
+	{
+		protected.GET("/profile", routes.auth.HandleGetProfile)
+		protected.GET("/sheets", routes.sheet.SearchCustomerHandler)
+
+		protected.GET("/customer-notes", routes.customer.HandleListNotes)
+		protected.POST("/customer-notes", routes.customer.HandleCreateNote)
+		protected.GET("/customer-tags", routes.customer.HandleGetTags)
+		protected.PUT("/customer-tags", routes.customer.HandleSetTags)
+		protected.GET("/tags", routes.customer.HandleListAllTags)
+		protected.GET("/customer-forms", routes.customer.HandleListForms)
+		protected.POST("/customer-forms", routes.customer.HandleCreateForm)
+		protected.GET("/customer-forms/:id", routes.customer.HandleGetForm)
+
+		protected.GET("/staff", routes.staff.HandleListStaff)
+
+		protected.GET("/appointments", routes.appointment.HandleListAppointments)
+		protected.POST("/appointments", routes.appointment.HandleCreateAppointment)
+		protected.GET("/appointments/:id", routes.appointment.HandleGetAppointment)
+		protected.PUT("/appointments/:id", routes.appointment.HandleUpdateAppointment)
+		protected.DELETE("/appointments/:id", routes.appointment.HandleDeleteAppointment)
+		protected.PATCH("/appointments/:id/status", routes.appointment.HandleUpdateAppointmentStatus)
+		protected.POST("/appointments/:id/visit", routes.appointment.HandleConvertToVisit)
+
+		protected.GET("/availability", routes.availability.HandleGetAvailability)
+
+		protected.GET("/services", routes.catalog.HandleListServices)
+		protected.GET("/products", routes.inventory.HandleListProducts)
+
+		protected.GET("/visits", routes.visit.HandleListVisits)
+		protected.POST("/visits", routes.visit.HandleCreateVisit)
+		protected.GET("/visits/:id", routes.visit.HandleGetVisit)
+
+		protected.GET("/packages", routes.prepaid.HandleListPackages)
+		protected.POST("/packages", routes.prepaid.HandleCreatePackage)
+		protected.GET("/packages/:id/ledger", routes.prepaid.HandleGetPackageLedger)
+		protected.GET("/wallets", routes.prepaid.HandleGetWallet)
+		protected.POST("/wallets/top-up", routes.prepaid.HandleTopUp)
+		protected.GET("/wallets/:id/ledger", routes.prepaid.HandleGetWalletLedger)
+
+		protected.GET("/loyalty", routes.loyalty.HandleGetLedger)
+	}
+
+	// 管理員路由
+	admin := protected.Group("/admin", routes.admin...)
+	{
+		admin.GET("/audit", routes.audit.HandleListAuditLogs)
+
+		if routes.sheetCache != nil {
+			admin.GET("/sheets/cache", routes.sheetCache.HandleGetStatus)
+			admin.POST("/sheets/refresh", routes.sheetCache.HandleRefresh)
+		}
+
+		admin.GET("/staff", routes.staff.HandleListAllStaff)
+		admin.POST("/staff", routes.staff.HandleCreateStaff)
+		admin.PUT("/staff/:id", routes.staff.HandleUpdateStaff)
+		admin.GET("/staff/:id/schedule", routes.schedule.HandleGetSchedule)
+		admin.PUT("/staff/:id/schedule", routes.schedule.HandleReplaceSchedule)
+		admin.GET("/staff/:id/time-off", routes.schedule.HandleListTimeOff)
+		admin.POST("/staff/:id/time-off", routes.schedule.HandleCreateTimeOff)
+		admin.DELETE("/staff/:id/time-off/:timeOffID", routes.schedule.HandleDeleteTimeOff)
+
+		admin.GET("/services", routes.catalog.HandleListAllServices)
+		admin.POST("/services", routes.catalog.HandleCreateService)
+		admin.PUT("/services/:id", routes.catalog.HandleUpdateService)
+		admin.GET("/services/:id/prices", routes.catalog.HandleListPriceHistory)
+
+		admin.GET("/products", routes.inventory.HandleListAllProducts)
+		admin.POST("/products", routes.inventory.HandleCreateProduct)
+		admin.PUT("/products/:id", routes.inventory.HandleUpdateProduct)
+		admin.GET("/products/:id/movements", routes.inventory.HandleListMovements)
+		admin.POST("/products/:id/movements", routes.inventory.HandleRecordMovement)
+		admin.GET("/inventory/low-stock", routes.inventory.HandleListLowStock)
+		admin.GET("/inventory/valuation", routes.inventory.HandleGetValuation)
+
+		admin.GET("/commission-rules", routes.commission.HandleListRules)
+		admin.POST("/commission-rules", routes.commission.HandleCreateRule)
+		admin.PUT("/commission-rules/:id", routes.commission.HandleUpdateRule)
+		admin.DELETE("/commission-rules/:id", routes.commission.HandleDeleteRule)
+		admin.GET("/commissions/:month", routes.commission.HandleGetReport)
+		admin.POST("/commissions/:month/close", routes.commission.HandleCloseMonth)
+
+		admin.POST("/loyalty/adjust", routes.loyalty.HandleAdjustPoints)
+
+		admin.GET("/reminders", routes.reminder.HandleListReminders)
+		admin.GET("/reminders/preview", routes.reminder.HandlePreview)
+		admin.POST("/reminders/run", routes.reminder.HandleRun)
+
+		admin.GET("/outbox", routes.outbox.HandleListMessages)
+		admin.POST("/outbox/:id/retry", routes.outbox.HandleRetry)
+
+		admin.GET("/holidays", routes.schedule.HandleListHolidays)
+		admin.PUT("/holidays/:date", routes.schedule.HandleSaveHoliday)
+		admin.DELETE("/holidays/:date", routes.schedule.HandleDeleteHoliday)
+	}
+}
+
+// mountLegacy 相容舊路徑：未加版本前綴的 /api/... 與舊版程式的 /api/logout/google
+// 以 v1 的處理器提供服務，回應附帶淘汰標頭並指向新路徑
+func mountLegacy(r *gin.Engine, routes *apiRoutes, cfg configs.APIConfig) {
+	deprecation := middleware.Deprecation{
+		DeprecatedAt: cfg.LegacyDeprecatedAt,
+		Sunset:       cfg.LegacySunset,
+		Successor: func(path string) string {
+			return currentAPIPrefix + strings.TrimPrefix(path, "/api")
+		},
+	}
+
+	legacy := r.Group("/api", middleware.Deprecated(deprecation))
+	registerV1(legacy, routes)
+
+	// 以下舊路徑在 v1 中已更名
+	renamed := func(successor string) middleware.Deprecation {
+		d := deprecation
+		d.Successor = func(string) string { return successor }
+		return d
+	}
+	r.GET("/api/logout/google", middleware.Deprecated(renamed(currentAPIPrefix+"/logout")), routes.auth.HandleLogout)
+	r.GET("/api/health", middleware.Deprecated(renamed("/healthz")), routes.health.HandleLiveness)
+}
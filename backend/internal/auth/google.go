@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/idtoken"
@@ -13,7 +15,8 @@ import (
 
 // GoogleOAuthConfig holds the configuration for Google OAuth
 type GoogleAuth struct {
-	Config *oauth2.Config
+	Config    *oauth2.Config
+	validator *idtoken.Validator
 }
 
 // NewGoogleAuth creates a new Google auth handler
@@ -32,16 +35,27 @@ func NewGoogleAuth(configPath string) (*GoogleAuth, error) {
 		return nil, fmt.Errorf("unable to parse client secret file to config: %w", err)
 	}
 
+	// 使用 Google API 的 HTTP 傳輸層，取得憑證公鑰的請求會自動產生追蹤 span
+	validator, err := idtoken.NewValidator(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create ID token validator: %w", err)
+	}
+
 	return &GoogleAuth{
-		Config: config,
+		Config:    config,
+		validator: validator,
 	}, nil
 }
 
 // ValidateIDToken validates a Google ID token and returns the claims
 func (g *GoogleAuth) ValidateIDToken(ctx context.Context, idToken string) (map[string]interface{}, error) {
+	ctx, span := otel.Tracer("backend/internal/auth").Start(ctx, "GoogleAuth.ValidateIDToken")
+	defer span.End()
+
 	// Validate the ID token
-	payload, err := idtoken.Validate(ctx, idToken, g.Config.ClientID)
+	payload, err := g.validator.Validate(ctx, idToken, g.Config.ClientID)
 	if err != nil {
+		span.SetStatus(codes.Error, "invalid token")
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+)
+
+// errSessionKeyExpired 舊金鑰已超過寬限期
+var errSessionKeyExpired = errors.New("session key expired")
+
+// expiringCodec 包裝輪替中的舊金鑰，超過寬限期後不再接受以該金鑰簽署的 Cookie
+type expiringCodec struct {
+	securecookie.Codec
+	expiresAt time.Time
+}
+
+// Decode 在寬限期內才使用舊金鑰解碼
+func (c expiringCodec) Decode(name, value string, dst interface{}) error {
+	if !c.expiresAt.IsZero() && time.Now().After(c.expiresAt) {
+		return errSessionKeyExpired
+	}
+	return c.Codec.Decode(name, value, dst)
+}
+
+// NewSessionStore 依設定建立會話存儲
+// 第一組金鑰用於簽署與加密新的 Cookie，其餘金鑰只在寬限期內用於驗證舊 Cookie
+func NewSessionStore(config configs.SessionConfig) (*sessions.CookieStore, error) {
+	var codecs []securecookie.Codec
+	for i, key := range config.Keys {
+		if !key.ExpiresAt.IsZero() && time.Now().After(key.ExpiresAt) {
+			logging.Component("auth").Warn("會話金鑰已超過寬限期，略過", "index", i, "expires_at", key.ExpiresAt)
+			continue
+		}
+
+		hashKey, encryptionKey, err := key.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("會話金鑰 #%d 無效: %w", i, err)
+		}
+
+		codec := securecookie.New(hashKey, encryptionKey)
+		codec.MaxAge(config.MaxAge)
+		if i == 0 {
+			codecs = append(codecs, codec)
+			continue
+		}
+		codecs = append(codecs, expiringCodec{Codec: codec, expiresAt: key.ExpiresAt})
+	}
+
+	if len(codecs) == 0 {
+		return nil, errors.New("沒有可用的會話金鑰")
+	}
+
+	return &sessions.CookieStore{
+		Codecs: codecs,
+		Options: &sessions.Options{
+			Path:     "/",
+			MaxAge:   config.MaxAge,
+			HttpOnly: true,
+			Secure:   config.Secure,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}, nil
+}
+
+// UsesCurrentKey 檢查請求中的會話 Cookie 是否以目前金鑰簽署
+// 回傳 false 代表 Cookie 仍使用舊金鑰，應重新儲存以換成新金鑰
+func UsesCurrentKey(store *sessions.CookieStore, r *http.Request, name string) bool {
+	cookie, err := r.Cookie(name)
+	if err != nil || len(store.Codecs) == 0 {
+		return true
+	}
+
+	values := make(map[interface{}]interface{})
+	return store.Codecs[0].Decode(name, cookie.Value, &values) == nil
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// AppointmentHandler 處理預約相關的 HTTP 請求
+type AppointmentHandler struct {
+	appointmentService *service.AppointmentService
+	auditService       *service.AuditService
+	location           *time.Location
+}
+
+// NewAppointmentHandler 創建一個新的預約處理器，location 用於解析僅含日期的查詢參數
+func NewAppointmentHandler(appointmentService *service.AppointmentService, auditService *service.AuditService, location *time.Location) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+		auditService:       auditService,
+		location:           location,
+	}
+}
+
+// appointmentStatusRequest 變更預約狀態的請求內容
+type appointmentStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// HandleListAppointments 依員工、客戶、狀態與時間區間查詢預約
+func (h *AppointmentHandler) HandleListAppointments(c *gin.Context) {
+	filter := repository.AppointmentFilter{
+		CustomerName: c.Query("customer"),
+		Status:       c.Query("status"),
+	}
+
+	var err error
+	if filter.StaffID, err = parseIDQuery(c, "staff_id"); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if filter.From, err = parseTimeQuery(c, "from", h.location, false); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to", h.location, true); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	appointments, err := h.appointmentService.List(c.Request.Context(), filter)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": appointments})
+}
+
+// HandleGetAppointment 取得單筆預約
+func (h *AppointmentHandler) HandleGetAppointment(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	appointment, err := h.appointmentService.Get(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment)
+}
+
+// HandleCreateAppointment 處理新增預約請求，員工時段重疊時返回 409
+func (h *AppointmentHandler) HandleCreateAppointment(c *gin.Context) {
+	var input service.AppointmentInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	appointment, err := h.appointmentService.Create(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, appointment.CustomerName, fmt.Sprintf("appointment=%d created", appointment.ID))
+	c.JSON(http.StatusCreated, appointment)
+}
+
+// HandleUpdateAppointment 處理修改預約請求
+func (h *AppointmentHandler) HandleUpdateAppointment(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.AppointmentInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	appointment, err := h.appointmentService.Update(c.Request.Context(), id, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, appointment.CustomerName, fmt.Sprintf("appointment=%d updated", appointment.ID))
+	c.JSON(http.StatusOK, appointment)
+}
+
+// HandleUpdateAppointmentStatus 處理變更預約狀態請求
+func (h *AppointmentHandler) HandleUpdateAppointmentStatus(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var req appointmentStatusRequest
+	if err := c.BindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	appointment, err := h.appointmentService.UpdateStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, appointment.CustomerName, fmt.Sprintf("appointment=%d status=%s", appointment.ID, appointment.Status))
+	c.JSON(http.StatusOK, appointment)
+}
+
+// HandleDeleteAppointment 處理刪除預約請求
+func (h *AppointmentHandler) HandleDeleteAppointment(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if err := h.appointmentService.Delete(c.Request.Context(), id); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, "", fmt.Sprintf("appointment=%d deleted", id))
+	c.Status(http.StatusNoContent)
+}
+
+// HandleConvertToVisit 將已完成的預約轉為消費紀錄，請求內容 (套票扣抵) 可省略
+func (h *AppointmentHandler) HandleConvertToVisit(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.ConversionInput
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&input); err != nil {
+			middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+			return
+		}
+	}
+
+	visit, err := h.appointmentService.ConvertToVisit(c.Request.Context(), id, input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, visit.CustomerName, fmt.Sprintf("appointment=%d visit=%d", id, visit.ID))
+	c.JSON(http.StatusCreated, visit)
+}
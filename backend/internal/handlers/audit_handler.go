@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// AuditHandler 處理稽核紀錄查詢相關的 HTTP 請求
+type AuditHandler struct {
+	auditService *service.AuditService
+	location     *time.Location
+	logger       *slog.Logger
+}
+
+// NewAuditHandler 創建一個新的稽核紀錄處理器，location 為營業時區，用於解析只有日期的查詢條件
+func NewAuditHandler(auditService *service.AuditService, location *time.Location) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		location:     location,
+		logger:       logging.Component("audit"),
+	}
+}
+
+// HandleListAuditLogs 處理稽核紀錄查詢請求，format=csv 時匯出 CSV 檔案
+func (h *AuditHandler) HandleListAuditLogs(c *gin.Context) {
+	filter, err := parseAuditFilter(c, h.location)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		h.exportCSV(c, filter)
+		return
+	}
+
+	logs, total, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, "audit.list_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  logs,
+		"total": total,
+	})
+}
+
+// exportCSV 將稽核紀錄以 CSV 格式輸出
+func (h *AuditHandler) exportCSV(c *gin.Context, filter repository.AuditFilter) {
+	logs, err := h.auditService.Export(c.Request.Context(), filter)
+	if err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, "audit.export_failed"))
+		return
+	}
+
+	// 匯出稽核紀錄本身也需要留下紀錄
+	recordAudit(c, h.auditService, models.AuditActionExport, "", fmt.Sprintf("audit_logs rows=%d", len(logs)))
+
+	filename := fmt.Sprintf("audit-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// 寫入 UTF-8 BOM，讓 Excel 正確顯示中文
+	c.Writer.Write([]byte("\xEF\xBB\xBF"))
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "created_at", "actor_id", "actor_email", "action", "target_customer", "ip", "user_agent", "detail"})
+	for _, entry := range logs {
+		w.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			entry.CreatedAt.Format(time.RFC3339),
+			entry.ActorID,
+			entry.ActorEmail,
+			entry.Action,
+			entry.TargetCustomer,
+			entry.IP,
+			entry.UserAgent,
+			entry.Detail,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "輸出 CSV 失敗", "error", err)
+	}
+}
+
+// parseAuditFilter 從查詢參數解析稽核紀錄篩選條件，只有日期時以 loc 的當天計算
+func parseAuditFilter(c *gin.Context, loc *time.Location) (repository.AuditFilter, error) {
+	filter := repository.AuditFilter{
+		ActorID:        c.Query("actor_id"),
+		ActorEmail:     c.Query("actor_email"),
+		Action:         c.Query("action"),
+		TargetCustomer: c.Query("customer"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from", loc, false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to", loc, true); err != nil {
+		return filter, err
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return filter, apperror.New(apperror.CodeInvalidRequest, "audit.invalid_integer", "limit")
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			return filter, apperror.New(apperror.CodeInvalidRequest, "audit.invalid_integer", "offset")
+		}
+	}
+
+	return filter, nil
+}
+
+// recordAudit 以目前請求的使用者資訊寫入稽核紀錄
+func recordAudit(c *gin.Context, auditService *service.AuditService, action, targetCustomer, detail string) {
+	if auditService == nil {
+		return
+	}
+
+	auditService.Record(c.Request.Context(), &models.AuditLog{
+		ActorID:        c.GetString(middleware.ContextUserID),
+		ActorEmail:     c.GetString(middleware.ContextEmail),
+		Action:         action,
+		TargetCustomer: targetCustomer,
+		IP:             c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+		Detail:         detail,
+	})
+}
@@ -1,29 +1,39 @@
 package handlers
 
 import (
-	"context"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/sessions"
 
+	"backend/internal/middleware"
 	"backend/internal/services"
 	"backend/internal/models"
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
 )
 
 // AuthHandler 處理身份驗證相關的 HTTP 請求
 type AuthHandler struct {
-	authService *service.AuthService
-	store       *sessions.CookieStore
+	authService   *service.AuthService
+	auditService  *service.AuditService
+	store         *sessions.CookieStore
+	sessionConfig configs.SessionConfig
+	logger        *slog.Logger
 }
 
 // NewAuthHandler 創建一個新的身份驗證處理器
-func NewAuthHandler(authService *service.AuthService, store *sessions.CookieStore) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, auditService *service.AuditService, store *sessions.CookieStore, sessionConfig configs.SessionConfig) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		store:       store,
+		authService:   authService,
+		auditService:  auditService,
+		store:         store,
+		sessionConfig: sessionConfig,
+		logger:        logging.Component("auth"),
 	}
 }
 
@@ -33,29 +43,27 @@ func (h *AuthHandler) HandleGoogleSignIn(c *gin.Context) {
 	
 	// 解析請求體
 	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "無法解析請求",
-			"details": err.Error(),
-		})
+		metrics.ObserveLogin(metrics.LoginFailure, "invalid_request")
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "auth.invalid_body"))
 		return
 	}
 	
 	// 驗證請求參數
 	if req.Credential == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少憑證"})
+		metrics.ObserveLogin(metrics.LoginFailure, "missing_credential")
+		middleware.AbortWithError(c, apperror.New(apperror.CodeInvalidRequest, "auth.missing_credential"))
 		return
 	}
 	
-	log.Printf("收到 Google ID Token，長度: %d 字符", len(req.Credential))
+	h.logger.DebugContext(c.Request.Context(), "收到 Google ID Token", "length", len(req.Credential))
 	
 	// 驗證 Google Token
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	payload, err := h.authService.ValidateGoogleToken(ctx, req.Credential)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "無效的憑證",
-			"details": err.Error(),
-		})
+		recordAudit(c, h.auditService, models.AuditActionLoginFailed, "", err.Error())
+		metrics.ObserveLogin(metrics.LoginFailure, "invalid_token")
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidCredential, ""))
 		return
 	}
 	
@@ -67,24 +75,22 @@ func (h *AuthHandler) HandleGoogleSignIn(c *gin.Context) {
 	expTime, _ := payload["exp"].(float64)     // Token 過期時間
 	
 	// 處理用戶身份驗證 (查找或創建用戶)
-	userID, err := h.authService.AuthenticateUser(email, name, picture, sub)
+	userID, err := h.authService.AuthenticateUser(ctx, email, name, picture, sub)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "身份驗證失敗",
-			"details": err.Error(),
-		})
+		c.Set(middleware.ContextEmail, email)
+		recordAudit(c, h.auditService, models.AuditActionLoginFailed, "", err.Error())
+		metrics.ObserveLogin(metrics.LoginFailure, "user_error")
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, "auth.authentication_failed"))
 		return
 	}
 	
 	// 創建用戶會話
 	sessionID, expiresAt, err := h.authService.CreateUserSession(
-		userID, c.ClientIP(), c.Request.UserAgent(), expTime,
+		ctx, userID, c.ClientIP(), c.Request.UserAgent(), expTime,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "創建會話失敗",
-			"details": err.Error(),
-		})
+		metrics.ObserveLogin(metrics.LoginFailure, "session_error")
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, "auth.session_create_failed"))
 		return
 	}
 	
@@ -100,27 +106,30 @@ func (h *AuthHandler) HandleGoogleSignIn(c *gin.Context) {
 	// 計算 Cookie 過期時間
 	maxAge := int(expiresAt.Sub(time.Now()).Seconds())
 	if maxAge <= 0 {
-		maxAge = 3600 * 24 * 30// 預設 24 * 30 小時
+		maxAge = h.sessionConfig.MaxAge
 	}
 	
 	// 配置 Cookie 選項
 	webSession.Options.MaxAge = maxAge
 	webSession.Options.Path = "/"
 	webSession.Options.HttpOnly = true
-	webSession.Options.Secure = false // 生產環境設為 true
+	webSession.Options.Secure = h.sessionConfig.Secure
 	webSession.Options.SameSite = http.SameSiteLaxMode
 	
 	// 保存會話
 	if err := webSession.Save(c.Request, c.Writer); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "儲存會話失敗",
-			"details": err.Error(),
-		})
+		metrics.ObserveLogin(metrics.LoginFailure, "cookie_error")
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, "auth.session_save_failed"))
 		return
 	}
 	
+	c.Set(middleware.ContextUserID, userID)
+	c.Set(middleware.ContextEmail, email)
+	recordAudit(c, h.auditService, models.AuditActionLogin, "", "")
+	metrics.ObserveLogin(metrics.LoginSuccess, "ok")
+
 	// 獲取活躍會話數 (可用於限制並發登入)
-	activeSessions, _ := h.authService.GetUserActiveSessions(userID)
+	activeSessions, _ := h.authService.GetUserActiveSessions(c.Request.Context(), userID)
 	
 	// 返回成功響應
 	c.JSON(http.StatusOK, gin.H{
@@ -142,15 +151,21 @@ func (h *AuthHandler) HandleLogout(c *gin.Context) {
 	sessionID, ok := session.Values["session_id"].(string)
 	if ok && sessionID != "" {
 		// 調用服務層登出用戶
-		if err := h.authService.LogoutUser(sessionID); err != nil {
-			log.Printf("從資料庫刪除會話失敗: %v", err)
+		if err := h.authService.LogoutUser(c.Request.Context(), sessionID); err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "從資料庫刪除會話失敗", "error", err)
 		}
+
+		userID, _ := session.Values["user_id"].(string)
+		email, _ := session.Values["email"].(string)
+		c.Set(middleware.ContextUserID, userID)
+		c.Set(middleware.ContextEmail, email)
+		recordAudit(c, h.auditService, models.AuditActionLogout, "", "")
 	}
 	
 	// 清除 Cookie
 	session.Options.MaxAge = -1
 	if err := session.Save(c.Request, c.Writer); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "登出失敗"})
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, "auth.logout_failed"))
 		return
 	}
 	
@@ -170,7 +185,7 @@ func (h *AuthHandler) HandleGetProfile(c *gin.Context) {
 	// 獲取活躍會話數 (可選)
 	var activeSessions int64
 	if userID != "" {
-		activeSessions, _ = h.authService.GetUserActiveSessions(userID)
+		activeSessions, _ = h.authService.GetUserActiveSessions(c.Request.Context(), userID)
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
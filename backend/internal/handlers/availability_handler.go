@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// AvailabilityHandler 處理可預約時段查詢
+type AvailabilityHandler struct {
+	availabilityService *service.AvailabilityService
+}
+
+// NewAvailabilityHandler 創建一個新的可預約時段處理器
+func NewAvailabilityHandler(availabilityService *service.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+	}
+}
+
+// HandleGetAvailability 依服務項目與日期返回可預約時段，可用 staff_id 限定員工
+func (h *AvailabilityHandler) HandleGetAvailability(c *gin.Context) {
+	serviceID, err := parseIDQuery(c, "service")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if serviceID == 0 {
+		middleware.AbortWithError(c, apperror.New(apperror.CodeInvalidRequest, "availability.missing_parameter", "service"))
+		return
+	}
+	date := c.Query("date")
+	if date == "" {
+		middleware.AbortWithError(c, apperror.New(apperror.CodeInvalidRequest, "availability.missing_parameter", "date"))
+		return
+	}
+	staffID, err := parseIDQuery(c, "staff_id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	availability, err := h.availabilityService.Slots(c.Request.Context(), serviceID, staffID, date)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// CatalogHandler 處理服務項目目錄相關的 HTTP 請求
+type CatalogHandler struct {
+	catalogService *service.CatalogService
+}
+
+// NewCatalogHandler 創建一個新的服務項目目錄處理器
+func NewCatalogHandler(catalogService *service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// HandleListServices 列出上架中的服務項目，可依分類篩選
+func (h *CatalogHandler) HandleListServices(c *gin.Context) {
+	h.list(c, true)
+}
+
+// HandleListAllServices 列出所有服務項目 (包含下架)，供管理員維護
+func (h *CatalogHandler) HandleListAllServices(c *gin.Context) {
+	h.list(c, false)
+}
+
+func (h *CatalogHandler) list(c *gin.Context, activeOnly bool) {
+	services, err := h.catalogService.List(c.Request.Context(), repository.ServiceFilter{
+		Category:   c.Query("category"),
+		ActiveOnly: activeOnly,
+	})
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": services})
+}
+
+// HandleCreateService 處理新增服務項目請求
+func (h *CatalogHandler) HandleCreateService(c *gin.Context) {
+	var input service.ServiceInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	svc, err := h.catalogService.Create(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, svc)
+}
+
+// HandleUpdateService 處理修改服務項目請求，下架以 active=false 表示
+func (h *CatalogHandler) HandleUpdateService(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.ServiceInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	svc, err := h.catalogService.Update(c.Request.Context(), id, input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, svc)
+}
+
+// HandleListPriceHistory 列出服務項目的定價紀錄
+func (h *CatalogHandler) HandleListPriceHistory(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	prices, err := h.catalogService.PriceHistory(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": prices})
+}
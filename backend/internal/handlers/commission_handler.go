@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// CommissionHandler 處理抽成規則與月份抽成報表的請求
+type CommissionHandler struct {
+	commissionService *service.CommissionService
+	auditService      *service.AuditService
+}
+
+// NewCommissionHandler 創建一個新的抽成處理器
+func NewCommissionHandler(commissionService *service.CommissionService, auditService *service.AuditService) *CommissionHandler {
+	return &CommissionHandler{
+		commissionService: commissionService,
+		auditService:      auditService,
+	}
+}
+
+// HandleListRules 列出所有抽成規則
+func (h *CommissionHandler) HandleListRules(c *gin.Context) {
+	rules, err := h.commissionService.ListRules(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// HandleCreateRule 處理新增抽成規則請求
+func (h *CommissionHandler) HandleCreateRule(c *gin.Context) {
+	var input service.CommissionRuleInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	rule, err := h.commissionService.CreateRule(c.Request.Context(), input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, "", fmt.Sprintf("commission_rule=%d created", rule.ID))
+	c.JSON(http.StatusCreated, rule)
+}
+
+// HandleUpdateRule 處理修改抽成規則請求
+func (h *CommissionHandler) HandleUpdateRule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.CommissionRuleInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	rule, err := h.commissionService.UpdateRule(c.Request.Context(), id, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, "", fmt.Sprintf("commission_rule=%d updated", rule.ID))
+	c.JSON(http.StatusOK, rule)
+}
+
+// HandleDeleteRule 處理刪除抽成規則請求
+func (h *CommissionHandler) HandleDeleteRule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if err := h.commissionService.DeleteRule(c.Request.Context(), id); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, "", fmt.Sprintf("commission_rule=%d deleted", id))
+	c.Status(http.StatusNoContent)
+}
+
+// HandleGetReport 返回月份抽成報表，尚未結算的月份依目前的規則即時計算
+func (h *CommissionHandler) HandleGetReport(c *gin.Context) {
+	report, err := h.commissionService.Report(c.Request.Context(), c.Param("month"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// HandleCloseMonth 結算月份，結算後的明細不可再修改
+func (h *CommissionHandler) HandleCloseMonth(c *gin.Context) {
+	month := c.Param("month")
+	report, err := h.commissionService.Close(c.Request.Context(), month, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, "", fmt.Sprintf("commission_month=%s closed total=%d", month, report.TotalCommission))
+	c.JSON(http.StatusOK, report)
+}
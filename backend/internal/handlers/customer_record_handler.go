@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// CustomerRecordHandler 處理客戶備註、標籤與表單相關的 HTTP 請求
+type CustomerRecordHandler struct {
+	recordService *service.CustomerRecordService
+	auditService  *service.AuditService
+}
+
+// NewCustomerRecordHandler 創建一個新的客戶紀錄處理器
+func NewCustomerRecordHandler(recordService *service.CustomerRecordService, auditService *service.AuditService) *CustomerRecordHandler {
+	return &CustomerRecordHandler{
+		recordService: recordService,
+		auditService:  auditService,
+	}
+}
+
+// HandleListNotes 列出客戶的備註
+func (h *CustomerRecordHandler) HandleListNotes(c *gin.Context) {
+	customerName := c.Query("customer")
+	notes, err := h.recordService.Notes(c.Request.Context(), customerName)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, customerName, fmt.Sprintf("notes=%d", len(notes)))
+	c.JSON(http.StatusOK, gin.H{"data": notes})
+}
+
+// HandleCreateNote 處理新增客戶備註請求
+func (h *CustomerRecordHandler) HandleCreateNote(c *gin.Context) {
+	var input service.NoteInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	note, err := h.recordService.AddNote(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, note.CustomerName, fmt.Sprintf("note_id=%d", note.ID))
+	c.JSON(http.StatusCreated, note)
+}
+
+// HandleListAllTags 列出所有標籤與使用它的客戶數
+func (h *CustomerRecordHandler) HandleListAllTags(c *gin.Context) {
+	tags, err := h.recordService.AllTags(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// HandleGetTags 列出客戶的標籤
+func (h *CustomerRecordHandler) HandleGetTags(c *gin.Context) {
+	customerName := c.Query("customer")
+	tags, err := h.recordService.Tags(c.Request.Context(), customerName)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, customerName, fmt.Sprintf("tags=%d", len(tags)))
+	c.JSON(http.StatusOK, gin.H{"customer_name": strings.TrimSpace(customerName), "tags": tags})
+}
+
+// HandleSetTags 處理設定客戶標籤請求，以請求中的標籤取代目前的標籤
+func (h *CustomerRecordHandler) HandleSetTags(c *gin.Context) {
+	var input service.TagsInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	tags, err := h.recordService.SetTags(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	customerName := strings.TrimSpace(input.CustomerName)
+	recordAudit(c, h.auditService, models.AuditActionEdit, customerName, "tags="+strings.Join(tags, ","))
+	c.JSON(http.StatusOK, gin.H{"customer_name": customerName, "tags": tags})
+}
+
+// HandleListForms 列出客戶簽署的健康問卷與療程同意書，可依類型篩選
+func (h *CustomerRecordHandler) HandleListForms(c *gin.Context) {
+	customerName := c.Query("customer")
+	forms, err := h.recordService.Forms(c.Request.Context(), customerName, c.Query("kind"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, customerName, fmt.Sprintf("forms=%d", len(forms)))
+	c.JSON(http.StatusOK, gin.H{"data": forms})
+}
+
+// HandleGetForm 取得一份表單
+func (h *CustomerRecordHandler) HandleGetForm(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	form, err := h.recordService.GetForm(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, form.CustomerName, fmt.Sprintf("form_id=%d", form.ID))
+	c.JSON(http.StatusOK, form)
+}
+
+// HandleCreateForm 處理登錄客戶簽署表單請求
+func (h *CustomerRecordHandler) HandleCreateForm(c *gin.Context) {
+	var input service.FormInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	form, err := h.recordService.AddForm(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, form.CustomerName, fmt.Sprintf("form_id=%d kind=%s", form.ID, form.Kind))
+	c.JSON(http.StatusCreated, form)
+}
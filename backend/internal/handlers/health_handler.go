@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/health"
+)
+
+// HealthHandler 處理存活與就緒檢查請求
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler 創建一個新的健康檢查處理器
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		registry: registry,
+	}
+}
+
+// HandleLiveness 處理存活檢查，只確認程序仍可回應，不探測相依元件
+func (h *HealthHandler) HandleLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// HandleReadiness 處理就緒檢查，探測資料庫與客戶資料來源等相依元件
+func (h *HealthHandler) HandleReadiness(c *gin.Context) {
+	report := h.registry.Run(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// defaultMovementLimit 庫存異動預設回傳筆數
+const defaultMovementLimit = 100
+
+// InventoryHandler 處理零售商品與庫存相關的 HTTP 請求
+type InventoryHandler struct {
+	inventoryService *service.InventoryService
+}
+
+// NewInventoryHandler 創建一個新的庫存處理器
+func NewInventoryHandler(inventoryService *service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// HandleListProducts 列出上架中的商品，可依分類篩選
+func (h *InventoryHandler) HandleListProducts(c *gin.Context) {
+	h.list(c, true)
+}
+
+// HandleListAllProducts 列出所有商品 (包含下架)，供管理員維護
+func (h *InventoryHandler) HandleListAllProducts(c *gin.Context) {
+	h.list(c, false)
+}
+
+func (h *InventoryHandler) list(c *gin.Context, activeOnly bool) {
+	products, err := h.inventoryService.List(c.Request.Context(), repository.ProductFilter{
+		Category:   c.Query("category"),
+		ActiveOnly: activeOnly,
+	})
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": products})
+}
+
+// HandleCreateProduct 處理新增商品請求
+func (h *InventoryHandler) HandleCreateProduct(c *gin.Context) {
+	var input service.ProductInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	product, err := h.inventoryService.Create(c.Request.Context(), input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, product)
+}
+
+// HandleUpdateProduct 處理修改商品請求，下架以 active=false 表示
+func (h *InventoryHandler) HandleUpdateProduct(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.ProductInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	product, err := h.inventoryService.Update(c.Request.Context(), id, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// HandleListMovements 列出商品的庫存異動
+func (h *InventoryHandler) HandleListMovements(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	limit, err := parseIntQuery(c, "limit", defaultMovementLimit)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	movements, err := h.inventoryService.Movements(c.Request.Context(), id, limit)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": movements})
+}
+
+// HandleRecordMovement 處理登錄進貨或盤點調整請求
+func (h *InventoryHandler) HandleRecordMovement(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.StockMovementInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	movement, err := h.inventoryService.RecordMovement(c.Request.Context(), id, input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, movement)
+}
+
+// HandleListLowStock 列出庫存已到補貨點的商品
+func (h *InventoryHandler) HandleListLowStock(c *gin.Context) {
+	products, err := h.inventoryService.LowStock(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": products})
+}
+
+// HandleGetValuation 返回庫存價值報表
+func (h *InventoryHandler) HandleGetValuation(c *gin.Context) {
+	valuation, err := h.inventoryService.Valuation(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, valuation)
+}
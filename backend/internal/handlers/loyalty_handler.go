@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// LoyaltyHandler 處理會員點數相關的 HTTP 請求
+type LoyaltyHandler struct {
+	loyaltyService *service.LoyaltyService
+	auditService   *service.AuditService
+}
+
+// NewLoyaltyHandler 創建一個新的會員點數處理器
+func NewLoyaltyHandler(loyaltyService *service.LoyaltyService, auditService *service.AuditService) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+		auditService:   auditService,
+	}
+}
+
+// HandleGetLedger 取得客戶的點數餘額、會員等級與點數異動紀錄
+func (h *LoyaltyHandler) HandleGetLedger(c *gin.Context) {
+	summary, entries, err := h.loyaltyService.Ledger(c.Request.Context(), c.Query("customer"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, summary.CustomerName, fmt.Sprintf("points_ledger=%d", len(entries)))
+	c.JSON(http.StatusOK, gin.H{"summary": summary, "data": entries})
+}
+
+// HandleAdjustPoints 處理人工調整點數請求
+func (h *LoyaltyHandler) HandleAdjustPoints(c *gin.Context) {
+	var input service.PointsAdjustInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	summary, err := h.loyaltyService.Adjust(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, summary.CustomerName, fmt.Sprintf("points_adjust=%d", input.Points))
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIPage Swagger UI 頁面，從 CDN 載入 swagger-ui-dist 並讀取 /api/openapi.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="zh-TW">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Little Sun API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.18.2/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.18.2/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({
+        url: "/api/openapi.json",
+        dom_id: "#swagger-ui",
+        withCredentials: true,
+      });
+    };
+  </script>
+</body>
+</html>`
+
+// OpenAPIHandler 提供 API 規格與 Swagger UI 頁面
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+// NewOpenAPIHandler 創建一個新的 API 規格處理器，spec 為 JSON 格式的規格
+func NewOpenAPIHandler(spec []byte) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		spec: spec,
+	}
+}
+
+// HandleSpec 返回 JSON 格式的 API 規格
+func (h *OpenAPIHandler) HandleSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// HandleDocs 返回 Swagger UI 頁面
+func (h *OpenAPIHandler) HandleDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
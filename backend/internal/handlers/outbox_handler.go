@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/repository"
+	"backend/internal/services"
+)
+
+// defaultOutboxLimit 通知預設回傳筆數
+const defaultOutboxLimit = 100
+
+// OutboxHandler 處理通知佇列相關的 HTTP 請求
+type OutboxHandler struct {
+	outboxService *service.OutboxService
+}
+
+// NewOutboxHandler 創建一個新的通知佇列處理器
+func NewOutboxHandler(outboxService *service.OutboxService) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService: outboxService,
+	}
+}
+
+// HandleListMessages 依狀態、管道與類型查詢通知，status=dead 為發送失敗需要處理的通知
+func (h *OutboxHandler) HandleListMessages(c *gin.Context) {
+	filter := repository.OutboxFilter{
+		Status:  c.Query("status"),
+		Channel: c.Query("channel"),
+		Kind:    c.Query("kind"),
+	}
+
+	var err error
+	if filter.Limit, err = parseIntQuery(c, "limit", defaultOutboxLimit); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	messages, err := h.outboxService.List(c.Request.Context(), filter)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": messages})
+}
+
+// HandleRetry 重新發送一則發送失敗的通知
+func (h *OutboxHandler) HandleRetry(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	msg, err := h.outboxService.Retry(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, msg)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/apperror"
+)
+
+// parseIDParam 解析路徑中的數字 ID
+func parseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperror.New(apperror.CodeInvalidRequest, "request.invalid_id", name)
+	}
+	return uint(id), nil
+}
+
+// parseIDQuery 解析查詢參數中的數字 ID，未提供時返回 0
+func parseIDQuery(c *gin.Context, name string) (uint, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperror.New(apperror.CodeInvalidRequest, "request.invalid_id", name)
+	}
+	return uint(id), nil
+}
+
+// parseTimeQuery 解析日期或時間查詢參數，僅提供日期時以 loc 的當天零時計算
+// endOfDay 為 true 時日期包含當天整日，未提供時返回零值
+func parseTimeQuery(c *gin.Context, name string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, apperror.New(apperror.CodeInvalidRequest, "request.invalid_time", name)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// parseIntQuery 解析非負整數查詢參數，未提供時返回 fallback
+func parseIntQuery(c *gin.Context, name string, fallback int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, apperror.New(apperror.CodeInvalidRequest, "request.invalid_integer", name)
+	}
+	return n, nil
+}
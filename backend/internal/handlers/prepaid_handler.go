@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// PrepaidHandler 處理課程套票與儲值金相關的 HTTP 請求
+type PrepaidHandler struct {
+	prepaidService *service.PrepaidService
+	auditService   *service.AuditService
+}
+
+// NewPrepaidHandler 創建一個新的套票與儲值金處理器
+func NewPrepaidHandler(prepaidService *service.PrepaidService, auditService *service.AuditService) *PrepaidHandler {
+	return &PrepaidHandler{
+		prepaidService: prepaidService,
+		auditService:   auditService,
+	}
+}
+
+// HandleListPackages 列出套票，可依客戶篩選
+func (h *PrepaidHandler) HandleListPackages(c *gin.Context) {
+	customerName := c.Query("customer")
+
+	packages, err := h.prepaidService.ListPackages(c.Request.Context(), customerName)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if customerName != "" {
+		recordAudit(c, h.auditService, models.AuditActionView, customerName, fmt.Sprintf("packages=%d", len(packages)))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": packages})
+}
+
+// HandleCreatePackage 處理購買套票請求
+func (h *PrepaidHandler) HandleCreatePackage(c *gin.Context) {
+	var input service.PackageInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	pkg, err := h.prepaidService.CreatePackage(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, pkg.CustomerName, fmt.Sprintf("package=%d created sessions=%d", pkg.ID, pkg.TotalSessions))
+	c.JSON(http.StatusCreated, pkg)
+}
+
+// HandleGetPackageLedger 取得套票與其堂數異動紀錄
+func (h *PrepaidHandler) HandleGetPackageLedger(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	pkg, entries, err := h.prepaidService.PackageLedger(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, pkg.CustomerName, fmt.Sprintf("package=%d ledger", pkg.ID))
+	c.JSON(http.StatusOK, gin.H{"package": pkg, "data": entries})
+}
+
+// HandleGetWallet 取得客戶的儲值金餘額
+func (h *PrepaidHandler) HandleGetWallet(c *gin.Context) {
+	wallet, err := h.prepaidService.GetWallet(c.Request.Context(), c.Query("customer"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, wallet.CustomerName, fmt.Sprintf("wallet=%d", wallet.ID))
+	c.JSON(http.StatusOK, wallet)
+}
+
+// HandleTopUp 處理儲值請求
+func (h *PrepaidHandler) HandleTopUp(c *gin.Context) {
+	var input service.TopUpInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	wallet, err := h.prepaidService.TopUp(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, wallet.CustomerName, fmt.Sprintf("wallet=%d top_up=%d", wallet.ID, input.Amount))
+	c.JSON(http.StatusOK, wallet)
+}
+
+// HandleGetWalletLedger 取得儲值金帳戶與其異動紀錄
+func (h *PrepaidHandler) HandleGetWalletLedger(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	wallet, entries, err := h.prepaidService.WalletLedger(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, wallet.CustomerName, fmt.Sprintf("wallet=%d ledger", wallet.ID))
+	c.JSON(http.StatusOK, gin.H{"wallet": wallet, "data": entries})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/repository"
+	"backend/internal/services"
+)
+
+// defaultReminderLimit 提醒紀錄預設回傳筆數
+const defaultReminderLimit = 100
+
+// ReminderHandler 處理生日與回流提醒相關的 HTTP 請求
+type ReminderHandler struct {
+	reminderService *service.ReminderService
+}
+
+// NewReminderHandler 創建一個新的提醒處理器
+func NewReminderHandler(reminderService *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// HandlePreview 產生今天的提醒名單，不發送
+func (h *ReminderHandler) HandlePreview(c *gin.Context) {
+	lists, err := h.reminderService.Preview(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lists)
+}
+
+// HandleRun 立即產生今天的提醒並加入通知佇列，已提醒過的客戶會略過
+func (h *ReminderHandler) HandleRun(c *gin.Context) {
+	result, err := h.reminderService.Run(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleListReminders 依類型與狀態查詢提醒紀錄
+func (h *ReminderHandler) HandleListReminders(c *gin.Context) {
+	filter := repository.ReminderFilter{
+		Kind:   c.Query("kind"),
+		Status: c.Query("status"),
+	}
+
+	var err error
+	if filter.Limit, err = parseIntQuery(c, "limit", defaultReminderLimit); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	reminders, err := h.reminderService.History(c.Request.Context(), filter)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reminders})
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// ScheduleHandler 處理員工排班、休假與店休日的管理請求
+type ScheduleHandler struct {
+	scheduleService *service.ScheduleService
+}
+
+// holidayRequest 設定店休日的請求內容
+type holidayRequest struct {
+	Name string `json:"name"`
+}
+
+// NewScheduleHandler 創建一個新的排班處理器
+func NewScheduleHandler(scheduleService *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// HandleGetSchedule 取得員工的每週排班
+func (h *ScheduleHandler) HandleGetSchedule(c *gin.Context) {
+	staffID, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	schedule, err := h.scheduleService.GetWeeklySchedule(c.Request.Context(), staffID)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// HandleReplaceSchedule 以請求內容取代員工的每週排班
+func (h *ScheduleHandler) HandleReplaceSchedule(c *gin.Context) {
+	staffID, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var schedule repository.WeeklySchedule
+	if err := c.BindJSON(&schedule); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	saved, err := h.scheduleService.ReplaceWeeklySchedule(c.Request.Context(), staffID, &schedule)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// HandleListTimeOff 列出員工的休假，可依日期區間篩選
+func (h *ScheduleHandler) HandleListTimeOff(c *gin.Context) {
+	staffID, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	from, err := parseTimeQuery(c, "from", time.UTC, false)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	to, err := parseTimeQuery(c, "to", time.UTC, true)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	timeOff, err := h.scheduleService.ListTimeOff(c.Request.Context(), staffID, from, to)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": timeOff})
+}
+
+// HandleCreateTimeOff 新增員工休假
+func (h *ScheduleHandler) HandleCreateTimeOff(c *gin.Context) {
+	staffID, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.TimeOffInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	timeOff, err := h.scheduleService.CreateTimeOff(c.Request.Context(), staffID, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, timeOff)
+}
+
+// HandleDeleteTimeOff 刪除員工休假
+func (h *ScheduleHandler) HandleDeleteTimeOff(c *gin.Context) {
+	staffID, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	timeOffID, err := parseIDParam(c, "timeOffID")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if err := h.scheduleService.DeleteTimeOff(c.Request.Context(), staffID, timeOffID); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleListHolidays 列出店休日，可依日期區間篩選
+func (h *ScheduleHandler) HandleListHolidays(c *gin.Context) {
+	from, err := parseTimeQuery(c, "from", time.UTC, false)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	to, err := parseTimeQuery(c, "to", time.UTC, true)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	holidays, err := h.scheduleService.ListHolidays(c.Request.Context(), from, to)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": holidays})
+}
+
+// HandleSaveHoliday 將路徑中的日期設為店休日，已存在時更新名稱
+func (h *ScheduleHandler) HandleSaveHoliday(c *gin.Context) {
+	var req holidayRequest
+	if err := c.BindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	holiday, err := h.scheduleService.SaveHoliday(c.Request.Context(), c.Param("date"), req.Name)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holiday)
+}
+
+// HandleDeleteHoliday 刪除店休日
+func (h *ScheduleHandler) HandleDeleteHoliday(c *gin.Context) {
+	if err := h.scheduleService.DeleteHoliday(c.Request.Context(), c.Param("date")); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
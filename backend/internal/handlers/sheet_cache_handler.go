@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// SheetCacheHandler 處理試算表快取的管理請求
+type SheetCacheHandler struct {
+	cache  *repository.SheetCache
+	logger *slog.Logger
+}
+
+// NewSheetCacheHandler 創建一個新的試算表快取處理器
+func NewSheetCacheHandler(cache *repository.SheetCache) *SheetCacheHandler {
+	return &SheetCacheHandler{
+		cache:  cache,
+		logger: logging.Component("sheets"),
+	}
+}
+
+// HandleGetStatus 返回目前快取的版本與載入時間
+func (h *SheetCacheHandler) HandleGetStatus(c *gin.Context) {
+	snapshot := h.cache.Snapshot()
+	if snapshot == nil {
+		c.JSON(http.StatusOK, gin.H{"loaded": false})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshotResponse(snapshot))
+}
+
+// HandleRefresh 強制重新下載試算表，供資料剛修改需立即生效時使用
+func (h *SheetCacheHandler) HandleRefresh(c *gin.Context) {
+	snapshot, err := h.cache.Refresh(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeUpstreamUnavailable, "sheets.refresh_failed"))
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "管理員重新載入試算表快取", "actor_email", c.GetString(middleware.ContextEmail))
+	c.JSON(http.StatusOK, snapshotResponse(snapshot))
+}
+
+// snapshotResponse 將快取內容轉為回應格式 (不含資料本身)
+func snapshotResponse(snapshot *repository.SheetSnapshot) gin.H {
+	return gin.H{
+		"loaded":     true,
+		"revision":   snapshot.Revision,
+		"rows":       len(snapshot.Values),
+		"fetched_at": snapshot.FetchedAt.Format(time.RFC3339),
+	}
+}
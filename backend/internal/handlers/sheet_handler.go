@@ -1,53 +1,78 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/option"
-	"google.golang.org/api/sheets/v4"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
 )
 
+// SheetHandler 處理試算表客戶資料相關的 HTTP 請求
+type SheetHandler struct {
+	source         repository.CustomerSource
+	auditService   *service.AuditService
+	loyaltyService *service.LoyaltyService
+	recordService  *service.CustomerRecordService
+	logger         *slog.Logger
+}
+
+// NewSheetHandler 創建一個新的試算表處理器，source 可為試算表或其快取
+func NewSheetHandler(source repository.CustomerSource, auditService *service.AuditService, loyaltyService *service.LoyaltyService, recordService *service.CustomerRecordService) *SheetHandler {
+	return &SheetHandler{
+		source:         source,
+		auditService:   auditService,
+		loyaltyService: loyaltyService,
+		recordService:  recordService,
+		logger:         logging.Component("sheets"),
+	}
+}
+
 // SearchCustomerHandler 處理客戶搜尋請求
-func SearchCustomerHandler(c *gin.Context) {
-	// 從查詢參數獲取客戶名
+// 可依客戶名與標籤 (可重複提供，須同時符合) 搜尋，至少提供其中一項
+func (h *SheetHandler) SearchCustomerHandler(c *gin.Context) {
+	// 從查詢參數獲取客戶名與標籤
 	customerName := c.Query("customer")
-	if customerName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "請提供 customer 查詢參數"})
+	tags := c.QueryArray("tag")
+	if customerName == "" && len(tags) == 0 {
+		middleware.AbortWithError(c, apperror.New(apperror.CodeInvalidRequest, "sheets.missing_customer"))
 		return
 	}
 
-	// 設定服務帳號 JSON 金鑰、試算表 ID 與範圍
-	serviceAccountFile := filepath.Join("pkg", "configs", "little-sun-system-d5e3eda49d9f.json")
-	spreadsheetId := "10IIJuGiur0HGpvjAippllfg1XhYq_wIHwR4_xWn-z_c"
-	readRange := "客戶細項!A1:Q"
-
-	// 建立 Sheets API 服務物件
-	ctx := context.Background()
-	srv, err := sheets.NewService(ctx, option.WithCredentialsFile(serviceAccountFile))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("建立 Sheets 服務失敗: %v", err)})
-		return
+	// 依標籤篩選時先找出符合的客戶
+	var tagged map[string]bool
+	if len(tags) > 0 {
+		var err error
+		if tagged, err = h.recordService.CustomersWithTags(c.Request.Context(), tags); err != nil {
+			middleware.AbortWithError(c, err)
+			return
+		}
 	}
 
 	// 取得指定範圍內的資料
-	resp, err := srv.Spreadsheets.Values.Get(spreadsheetId, readRange).Do()
+	values, err := h.source.GetValues(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("讀取試算表失敗: %v", err)})
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeUpstreamUnavailable, "sheets.read_failed"))
 		return
 	}
 
 	// 確認至少有一列標題與一筆資料
-	if len(resp.Values) < 2 {
-		c.JSON(http.StatusOK, gin.H{"message": "未找到資料"})
+	if len(values) < 2 {
+		middleware.AbortWithError(c, apperror.New(apperror.CodeCustomerNotFound, ""))
 		return
 	}
 
 	// 取得第一列作為 header，找出「客戶名」所在的欄位索引
-	header := resp.Values[0]
+	header := values[0]
 	customerNameIdx := -1
 	for i, col := range header {
 		if colStr, ok := col.(string); ok && colStr == "客戶名" {
@@ -57,25 +82,50 @@ func SearchCustomerHandler(c *gin.Context) {
 	}
 	
 	if customerNameIdx == -1 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "找不到 [客戶名] 欄位"})
+		middleware.AbortWithError(c, apperror.Internal(errors.New("試算表找不到 [客戶名] 欄位")))
 		return
 	}
 
-	// 遍歷資料列，找出符合客戶名的資料
+	// 遍歷資料列，找出符合客戶名與標籤的資料
 	var results []interface{}
-	for _, row := range resp.Values[1:] {
+	for _, row := range values[1:] {
 		if len(row) > customerNameIdx {
-			if val, ok := row[customerNameIdx].(string); ok && val == customerName {
-				results = append(results, row)
+			val, ok := row[customerNameIdx].(string)
+			if !ok || (customerName != "" && val != customerName) || (tagged != nil && !tagged[val]) {
+				continue
 			}
+			results = append(results, row)
 		}
 	}
 
+	// 記錄查詢客戶資料的稽核紀錄
+	detail := fmt.Sprintf("results=%d", len(results))
+	if len(tags) > 0 {
+		detail += " tags=" + strings.Join(tags, ",")
+	}
+	recordAudit(c, h.auditService, models.AuditActionSearch, customerName, detail)
+
 	if len(results) == 0 {
-		c.JSON(http.StatusOK, gin.H{"message": "查無此客戶資料"})
+		middleware.AbortWithError(c, apperror.New(apperror.CodeCustomerNotFound, ""))
 		return
 	}
 
-	// 回傳搜尋到的資料列
-	c.JSON(http.StatusOK, gin.H{"data": results})
+	// 回傳搜尋到的資料列；指定客戶時附上點數餘額與標籤，查詢失敗時不影響客戶資料
+	response := gin.H{"data": results}
+	if customerName != "" {
+		summary, err := h.loyaltyService.Summary(c.Request.Context(), customerName)
+		if err != nil {
+			h.logger.WarnContext(c.Request.Context(), "查詢點數餘額失敗", "error", err)
+		} else {
+			response["loyalty"] = summary
+		}
+
+		customerTags, err := h.recordService.Tags(c.Request.Context(), customerName)
+		if err != nil {
+			h.logger.WarnContext(c.Request.Context(), "查詢客戶標籤失敗", "error", err)
+		} else {
+			response["tags"] = customerTags
+		}
+	}
+	c.JSON(http.StatusOK, response)
 }
\ No newline at end of file
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// StaffHandler 處理員工相關的 HTTP 請求
+type StaffHandler struct {
+	staffService *service.StaffService
+}
+
+// NewStaffHandler 創建一個新的員工處理器
+func NewStaffHandler(staffService *service.StaffService) *StaffHandler {
+	return &StaffHandler{
+		staffService: staffService,
+	}
+}
+
+// HandleListStaff 列出在職員工，供預約時選擇
+func (h *StaffHandler) HandleListStaff(c *gin.Context) {
+	h.list(c, true)
+}
+
+// HandleListAllStaff 列出所有員工 (包含離職)，供管理員維護
+func (h *StaffHandler) HandleListAllStaff(c *gin.Context) {
+	h.list(c, false)
+}
+
+func (h *StaffHandler) list(c *gin.Context, activeOnly bool) {
+	staff, err := h.staffService.List(c.Request.Context(), activeOnly)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": staff})
+}
+
+// HandleCreateStaff 處理新增員工請求
+func (h *StaffHandler) HandleCreateStaff(c *gin.Context) {
+	var input service.StaffInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	staff, err := h.staffService.Create(c.Request.Context(), input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, staff)
+}
+
+// HandleUpdateStaff 處理修改員工請求
+func (h *StaffHandler) HandleUpdateStaff(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	var input service.StaffInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	staff, err := h.staffService.Update(c.Request.Context(), id, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, staff)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/apperror"
+)
+
+// VisitHandler 處理消費紀錄相關的 HTTP 請求
+type VisitHandler struct {
+	visitService *service.VisitService
+	auditService *service.AuditService
+}
+
+// NewVisitHandler 創建一個新的消費紀錄處理器
+func NewVisitHandler(visitService *service.VisitService, auditService *service.AuditService) *VisitHandler {
+	return &VisitHandler{
+		visitService: visitService,
+		auditService: auditService,
+	}
+}
+
+// HandleListVisits 依客戶、員工與日期區間查詢消費紀錄
+func (h *VisitHandler) HandleListVisits(c *gin.Context) {
+	filter := repository.VisitFilter{
+		CustomerName: c.Query("customer"),
+	}
+
+	// 消費日期以日期儲存，不需時區換算
+	var err error
+	if filter.StaffID, err = parseIDQuery(c, "staff_id"); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if filter.From, err = parseTimeQuery(c, "from", time.UTC, false); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to", time.UTC, true); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	visits, err := h.visitService.List(c.Request.Context(), filter)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if filter.CustomerName != "" {
+		recordAudit(c, h.auditService, models.AuditActionView, filter.CustomerName, fmt.Sprintf("visits=%d", len(visits)))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": visits})
+}
+
+// HandleCreateVisit 處理新增消費紀錄請求，項目金額與總額依服務項目目錄計算
+func (h *VisitHandler) HandleCreateVisit(c *gin.Context) {
+	var input service.VisitInput
+	if err := c.BindJSON(&input); err != nil {
+		middleware.AbortWithError(c, apperror.Wrap(err, apperror.CodeInvalidRequest, "request.invalid_body"))
+		return
+	}
+
+	visit, err := h.visitService.Create(c.Request.Context(), input, c.GetString(middleware.ContextEmail))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionEdit, visit.CustomerName, fmt.Sprintf("visit=%d created total=%d", visit.ID, visit.Total))
+	c.JSON(http.StatusCreated, visit)
+}
+
+// HandleGetVisit 取得單筆消費紀錄
+func (h *VisitHandler) HandleGetVisit(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	visit, err := h.visitService.Get(c.Request.Context(), id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionView, visit.CustomerName, fmt.Sprintf("visit=%d", visit.ID))
+	c.JSON(http.StatusOK, visit)
+}
@@ -1,26 +1,37 @@
 package middleware
 
 import (
-	"log"
-	"net/http"
+	"log/slog"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/sessions"
 
+	"backend/internal/auth"
 	"backend/internal/services"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// 通過驗證後寫入 gin.Context 的鍵值
+const (
+	ContextUserID    = "user_id"
+	ContextEmail     = "email"
+	ContextSessionID = "session_id"
 )
 
 // AuthMiddleware 處理身份驗證中間件
 type AuthMiddleware struct {
 	store       *sessions.CookieStore
 	authService *service.AuthService
+	logger      *slog.Logger
 }
 
 // NewAuthMiddleware 創建一個新的身份驗證中間件
-func NewAuthMiddleware(key []byte, authService *service.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(store *sessions.CookieStore, authService *service.AuthService) *AuthMiddleware {
 	return &AuthMiddleware{
-		store:       sessions.NewCookieStore(key),
+		store:       store,
 		authService: authService,
+		logger:      logging.Component("auth"),
 	}
 }
 
@@ -30,18 +41,15 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 		// 獲取會話
 		session, err := m.store.Get(c.Request, "user-session")
 		if err != nil {
-			log.Printf("獲取會話失敗: %v", err)
 			m.clearSession(c)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "無效的會話"})
-			c.Abort()
+			AbortWithError(c, apperror.Wrap(err, apperror.CodeUnauthenticated, "auth.invalid_session"))
 			return
 		}
 		
 		// 檢查用戶是否已通過身份驗證
-		auth, ok := session.Values["auth"].(bool)
-		if !ok || !auth {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "需要身份驗證"})
-			c.Abort()
+		authenticated, ok := session.Values["auth"].(bool)
+		if !ok || !authenticated {
+			AbortWithError(c, apperror.New(apperror.CodeUnauthenticated, ""))
 			return
 		}
 		
@@ -49,40 +57,70 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 		sessionID, ok := session.Values["session_id"].(string)
 		if !ok || sessionID == "" {
 			m.clearSession(c)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "無效的會話"})
-			c.Abort()
+			AbortWithError(c, apperror.New(apperror.CodeUnauthenticated, "auth.invalid_session"))
 			return
 		}
 		
 		// 使用服務層驗證會話
-		valid, err := m.authService.ValidateSession(sessionID)
+		valid, err := m.authService.ValidateSession(c.Request.Context(), sessionID)
 		if err != nil {
-			log.Printf("驗證會話失敗: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "服務器錯誤"})
-			c.Abort()
+			AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, ""))
 			return
 		}
 		
 		// 會話無效
 		if !valid {
 			m.clearSession(c)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "會話已過期"})
-			c.Abort()
+			AbortWithError(c, apperror.New(apperror.CodeSessionExpired, ""))
 			return
 		}
 		
-		// 刷新會話超時 (可選，根據需求)
-		go func() {
-			if err := m.authService.RefreshSession(sessionID); err != nil {
-				log.Printf("刷新會話失敗: %v", err)
+		// 以舊金鑰簽署的 Cookie 在寬限期內重新以目前金鑰儲存
+		if !auth.UsesCurrentKey(m.store, c.Request, "user-session") {
+			if err := session.Save(c.Request, c.Writer); err != nil {
+				m.logger.ErrorContext(c.Request.Context(), "以新金鑰重新儲存會話失敗", "error", err)
 			}
-		}()
+		}
+
+		// 刷新會話超時 (可選，根據需求)
+		m.authService.RefreshSessionAsync(c.Request.Context(), sessionID)
 		
+		// 將使用者資訊寫入請求上下文，供後續處理器使用
+		userID, _ := session.Values["user_id"].(string)
+		email, _ := session.Values["email"].(string)
+		c.Set(ContextUserID, userID)
+		c.Set(ContextEmail, email)
+		c.Set(ContextSessionID, sessionID)
+
 		// 會話有效，繼續處理請求
 		c.Next()
 	}
 }
 
+// AdminRequired 是檢查用戶是否具有管理員角色的中間件，須接在 AuthRequired 之後
+func (m *AuthMiddleware) AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(ContextUserID)
+		if userID == "" {
+			AbortWithError(c, apperror.New(apperror.CodeUnauthenticated, ""))
+			return
+		}
+
+		isAdmin, err := m.authService.IsAdmin(c.Request.Context(), userID)
+		if err != nil {
+			AbortWithError(c, apperror.Wrap(err, apperror.CodeInternal, ""))
+			return
+		}
+
+		if !isAdmin {
+			AbortWithError(c, apperror.New(apperror.CodeForbidden, "auth.admin_required"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // GetSessionStore 返回會話存儲
 func (m *AuthMiddleware) GetSessionStore() *sessions.CookieStore {
 	return m.store
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
+)
+
+// Deprecation 舊版路由的淘汰資訊
+type Deprecation struct {
+	// DeprecatedAt 開始淘汰的時間，輸出為 Deprecation 標頭 (RFC 9745)
+	DeprecatedAt time.Time
+	// Sunset 預計停止服務的時間，輸出為 Sunset 標頭 (RFC 8594)，零值表示尚未決定
+	Sunset time.Time
+	// Successor 依請求路徑返回取代的新路徑，輸出為 Link rel="successor-version"，可為 nil
+	Successor func(path string) string
+}
+
+// Deprecated 在回應中加上淘汰標頭，並記錄舊路由的使用次數，方便確認何時可以移除
+func Deprecated(d Deprecation) gin.HandlerFunc {
+	logger := logging.Component("http")
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", fmt.Sprintf("@%d", d.DeprecatedAt.Unix()))
+		if !d.Sunset.IsZero() {
+			c.Header("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Successor != nil {
+			if successor := d.Successor(c.Request.URL.Path); successor != "" {
+				c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+			}
+		}
+
+		metrics.ObserveDeprecatedRequest(c.Request.Method, c.FullPath())
+		logger.DebugContext(c.Request.Context(), "呼叫已淘汰的路由",
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"user_agent", c.Request.UserAgent(),
+		)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// AbortWithError 記錄錯誤並中止請求，回應由 ErrorHandler 統一產生
+func AbortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// ErrorHandler 將處理器透過 AbortWithError 回報的錯誤轉為統一的 JSON 回應
+// 回應只包含錯誤代碼、依 Accept-Language 翻譯的訊息與請求 ID，內部原因只寫入日誌
+// 須註冊在記錄狀態碼的中間件 (RequestLogger、metrics) 之後，才能記錄到正確的狀態碼
+func ErrorHandler() gin.HandlerFunc {
+	logger := logging.Component("http")
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		appErr := apperror.From(c.Errors.Last().Err)
+		level := slog.LevelInfo
+		if appErr.Status() >= 500 {
+			level = slog.LevelError
+		}
+		logger.Log(c.Request.Context(), level, "請求失敗",
+			"code", appErr.Code,
+			"status", appErr.Status(),
+			"route", c.FullPath(),
+			"error", appErr.Err,
+		)
+
+		if c.Writer.Written() {
+			return
+		}
+		writeError(c, appErr)
+	}
+}
+
+// Recovery 攔截 panic 並以統一格式回應 500
+func Recovery() gin.HandlerFunc {
+	logger := logging.Component("http")
+
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		logger.ErrorContext(c.Request.Context(), "處理請求時發生 panic",
+			"route", c.FullPath(),
+			"panic", fmt.Sprint(recovered),
+		)
+		writeError(c, apperror.Internal(fmt.Errorf("panic: %v", recovered)))
+		c.Abort()
+	})
+}
+
+// writeError 以使用者語言輸出錯誤回應
+func writeError(c *gin.Context, appErr *apperror.Error) {
+	c.JSON(appErr.Status(), gin.H{
+		"error":      appErr.Message(requestLanguage(c)),
+		"code":       appErr.Code,
+		"request_id": c.GetString(ContextRequestID),
+	})
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/i18n"
+)
+
+// ContextLanguage 使用者語言在 gin.Context 中的鍵
+const ContextLanguage = "language"
+
+// Language 依 Accept-Language 標頭決定回應語言，寫入 gin.Context 與請求 context
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		c.Set(ContextLanguage, lang)
+		c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+		c.Header("Content-Language", lang)
+		c.Header("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
+
+// requestLanguage 取得請求的回應語言，Language 尚未執行時直接由標頭判斷
+func requestLanguage(c *gin.Context) string {
+	if lang := c.GetString(ContextLanguage); lang != "" {
+		return lang
+	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
+}
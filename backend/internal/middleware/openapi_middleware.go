@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// OpenAPIValidator 依 API 規格驗證請求與回應
+// 不符合規格的請求回應 400；回應驗證只記錄警告，用於開發與測試環境發現規格與實作不一致
+type OpenAPIValidator struct {
+	router            routers.Router
+	validateRequests  bool
+	validateResponses bool
+	logger            *slog.Logger
+}
+
+// NewOpenAPIValidator 創建一個新的規格驗證中間件
+func NewOpenAPIValidator(doc *openapi3.T, validateRequests, validateResponses bool) (*OpenAPIValidator, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAPIValidator{
+		router:            router,
+		validateRequests:  validateRequests,
+		validateResponses: validateResponses,
+		logger:            logging.Component("openapi"),
+	}, nil
+}
+
+// Middleware 返回 gin 中間件，規格中未定義的路由不做驗證
+func (v *OpenAPIValidator) Middleware() gin.HandlerFunc {
+	// 身份驗證由 AuthMiddleware 負責，這裡只檢查參數與內容格式
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+	options.WithCustomSchemaErrorFunc(schemaErrorMessage)
+	responseOptions := &openapi3filter.Options{IncludeResponseStatus: true}
+	responseOptions.WithCustomSchemaErrorFunc(schemaErrorMessage)
+
+	return func(c *gin.Context) {
+		route, pathParams, err := v.router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+
+		if v.validateRequests {
+			if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+				AbortWithError(c, requestValidationError(err))
+				return
+			}
+		}
+
+		if !v.validateResponses {
+			c.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// 只驗證處理器直接輸出的 JSON 回應；CSV、HTML 與監控指標等不在驗證範圍，
+		// 錯誤回應由外層的 ErrorHandler 輸出，格式固定，也不在此驗證
+		mediaType, _, _ := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+		if mediaType != "application/json" {
+			return
+		}
+
+		err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 recorder.Status(),
+			Header:                 recorder.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options:                responseOptions,
+		})
+		if err != nil {
+			v.logger.WarnContext(c.Request.Context(), "回應不符合 API 規格",
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"status", recorder.Status(),
+				"error", err,
+			)
+		}
+	}
+}
+
+// requestValidationError 將規格驗證錯誤轉為 API 錯誤，只指出有問題的參數，細節寫入日誌
+func requestValidationError(err error) *apperror.Error {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		if requestErr.Parameter != nil {
+			return apperror.Wrap(err, apperror.CodeInvalidRequest, "openapi.invalid_parameter", requestErr.Parameter.Name)
+		}
+		if requestErr.RequestBody != nil {
+			return apperror.Wrap(err, apperror.CodeInvalidRequest, "openapi.invalid_body")
+		}
+	}
+	return apperror.Wrap(err, apperror.CodeInvalidRequest, "")
+}
+
+// schemaErrorMessage 只保留驗證錯誤的位置與原因
+// kin-openapi 預設會附上實際值與整段 schema，實際值可能包含客戶姓名等個資，不可寫入日誌
+func schemaErrorMessage(err *openapi3.SchemaError) string {
+	reason := err.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("不符合 schema 的 %s 限制", err.SchemaField)
+	}
+	if path := err.JSONPointer(); len(path) > 0 {
+		return fmt.Sprintf("/%s: %s", strings.Join(path, "/"), reason)
+	}
+	return reason
+}
+
+// responseRecorder 在寫出回應的同時保留一份內容供驗證
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
+	"backend/pkg/ratelimit"
+)
+
+// 限流範圍，同時作為限流鍵的前綴與指標標籤
+const (
+	rateLimitScopeIP    = "ip"
+	rateLimitScopeUser  = "user"
+	rateLimitScopeRoute = "route"
+)
+
+// RateLimitMiddleware 以令牌桶限制每個 IP、使用者與路由的請求頻率
+type RateLimitMiddleware struct {
+	limiter ratelimit.Limiter
+	perIP   ratelimit.Limit
+	perUser ratelimit.Limit
+	routes  map[string]ratelimit.Limit
+	logger  *slog.Logger
+}
+
+// NewRateLimitMiddleware 創建一個新的限流中間件
+func NewRateLimitMiddleware(limiter ratelimit.Limiter, cfg configs.RateLimitConfig) *RateLimitMiddleware {
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for route, rule := range cfg.Routes {
+		routes[route] = toLimit(rule)
+	}
+
+	return &RateLimitMiddleware{
+		limiter: limiter,
+		perIP:   toLimit(cfg.PerIP),
+		perUser: toLimit(cfg.PerUser),
+		routes:  routes,
+		logger:  logging.Component("ratelimit"),
+	}
+}
+
+// PerIP 限制每個來源 IP 的整體請求頻率，並套用個別路由的限制 (以 IP 區分)
+// 掛在全域，登入等未驗證的端點也會受到保護
+func (m *RateLimitMiddleware) PerIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		if !m.allow(c, rateLimitScopeIP, rateLimitScopeIP+":"+ip, m.perIP) {
+			return
+		}
+
+		route := c.Request.Method + " " + unversionedPath(c.FullPath())
+		if limit, ok := m.routes[route]; ok {
+			if !m.allow(c, rateLimitScopeRoute, rateLimitScopeRoute+":"+route+":"+ip, limit) {
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// PerUser 限制每個已登入使用者的請求頻率，須接在 AuthRequired 之後
+func (m *RateLimitMiddleware) PerUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(ContextUserID)
+		if userID != "" && !m.allow(c, rateLimitScopeUser, rateLimitScopeUser+":"+userID, m.perUser) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow 檢查一次限流，超過限制時回應 429 並中止請求
+// 限流儲存發生錯誤時放行請求，避免 Redis 異常導致整個 API 無法使用
+func (m *RateLimitMiddleware) allow(c *gin.Context, scope, key string, limit ratelimit.Limit) bool {
+	result, err := m.limiter.Allow(c.Request.Context(), key, limit)
+	if err != nil {
+		m.logger.ErrorContext(c.Request.Context(), "限流檢查失敗，放行請求", "scope", scope, "error", err)
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	if result.Allowed {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+	metrics.ObserveRateLimited(scope, c.FullPath())
+	m.logger.WarnContext(c.Request.Context(), "請求過於頻繁",
+		"scope", scope,
+		"client_ip", c.ClientIP(),
+		"path", c.FullPath(),
+		"retry_after", retryAfter,
+	)
+
+	AbortWithError(c, apperror.New(apperror.CodeRateLimited, ""))
+	return false
+}
+
+// apiVersionPrefix 路由中的 API 版本前綴，例如 /api/v1/
+var apiVersionPrefix = regexp.MustCompile(`^/api/v[0-9]+/`)
+
+// unversionedPath 移除 API 版本前綴，讓路由限制同時套用於各版本與舊路由，
+// 也避免切換版本繞過限制
+func unversionedPath(path string) string {
+	return apiVersionPrefix.ReplaceAllString(path, "/api/")
+}
+
+// toLimit 將設定中的每分鐘請求數轉為令牌桶參數
+func toLimit(rule configs.RateLimitRule) ratelimit.Limit {
+	return ratelimit.PerMinute(rule.RequestsPerMinute, rule.Burst)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"backend/pkg/logging"
+)
+
+// RequestIDHeader 請求 ID 的 HTTP 標頭
+const RequestIDHeader = "X-Request-ID"
+
+// ContextRequestID 請求 ID 在 gin.Context 中的鍵
+const ContextRequestID = "request_id"
+
+// validRequestID 接受上游代理傳入的請求 ID 格式，避免寫入任意內容到日誌
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID 為每個請求指定 ID (沿用合法的 X-Request-ID 標頭)，寫入回應標頭與請求 context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(ContextRequestID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// RequestLogger 以結構化日誌記錄每個請求，取代 gin 預設的文字日誌
+// 只記錄路徑不含查詢參數，避免客戶姓名等查詢條件寫入日誌
+func RequestLogger() gin.HandlerFunc {
+	logger := logging.Component("http")
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		logger.Log(c.Request.Context(), level, "HTTP 請求",
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"user_id", c.GetString(ContextUserID),
+		)
+	}
+}
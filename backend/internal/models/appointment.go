@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// 預約狀態
+const (
+	AppointmentStatusScheduled = "scheduled"
+	AppointmentStatusConfirmed = "confirmed"
+	AppointmentStatusCompleted = "completed"
+	AppointmentStatusCancelled = "cancelled"
+	AppointmentStatusNoShow    = "no_show"
+)
+
+// Appointment 代表一筆客戶預約
+// 已取消或未到的預約不佔用員工時段；完成後可轉為消費紀錄 (VisitID)
+type Appointment struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	CustomerName  string            `gorm:"size:255;not null;index" json:"customer_name"`
+	CustomerPhone string            `gorm:"size:32" json:"customer_phone"`
+	StaffID       uint              `gorm:"not null;index" json:"staff_id"`
+	Staff         *Staff            `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+	StartAt       time.Time         `gorm:"not null;index" json:"start_at"`
+	EndAt         time.Time         `gorm:"not null" json:"end_at"`
+	Status        string            `gorm:"size:16;not null;default:scheduled;index" json:"status"`
+	Note          string            `gorm:"type:text" json:"note"`
+	Items         []AppointmentItem `gorm:"foreignKey:AppointmentID" json:"items"`
+	VisitID       *uint             `gorm:"uniqueIndex" json:"visit_id,omitempty"`
+	CreatedBy     string            `gorm:"size:255" json:"created_by"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AppointmentItem 預約的服務項目，金額單位為新台幣元
+// 名稱、金額與時間為預約當下服務項目目錄的內容；ServiceID 為 nil 的項目為目錄建立前的舊資料
+type AppointmentItem struct {
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	AppointmentID   uint   `gorm:"not null;index" json:"-"`
+	ServiceID       *uint  `gorm:"index" json:"service_id,omitempty"`
+	Name            string `gorm:"size:255;not null" json:"name"`
+	Price           int64  `gorm:"not null" json:"price"`
+	DurationMinutes int    `gorm:"not null;default:0" json:"duration_minutes"`
+}
+
+// Total 返回預約項目的金額合計
+func (a *Appointment) Total() int64 {
+	var total int64
+	for _, item := range a.Items {
+		total += item.Price
+	}
+	return total
+}
+
+// Open 預約是否仍可修改：尚未完成、取消或未到，也尚未轉為消費紀錄
+func (a *Appointment) Open() bool {
+	return a.VisitID == nil && (a.Status == AppointmentStatusScheduled || a.Status == AppointmentStatusConfirmed)
+}
+
+// OccupiesSlot 預約是否佔用員工時段
+func (a *Appointment) OccupiesSlot() bool {
+	return a.Status != AppointmentStatusCancelled && a.Status != AppointmentStatusNoShow
+}
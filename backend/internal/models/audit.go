@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// 稽核動作類型
+const (
+	AuditActionLogin       = "login"
+	AuditActionLoginFailed = "login_failed"
+	AuditActionLogout      = "logout"
+	AuditActionSearch      = "search"
+	AuditActionView        = "view"
+	AuditActionEdit        = "edit"
+	AuditActionExport      = "export"
+	AuditActionRoleChange  = "role_change"
+)
+
+// AuditLog 代表一筆稽核紀錄，只允許新增，不允許修改或刪除
+type AuditLog struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ActorID        string    `gorm:"size:255;index" json:"actor_id"`
+	ActorEmail     string    `gorm:"size:255" json:"actor_email"`
+	Action         string    `gorm:"size:32;not null;index" json:"action"`
+	TargetCustomer string    `gorm:"size:255;index" json:"target_customer"`
+	IP             string    `gorm:"size:45" json:"ip"`
+	UserAgent      string    `gorm:"type:text" json:"user_agent"`
+	Detail         string    `gorm:"type:text" json:"detail"`
+	CreatedAt      time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
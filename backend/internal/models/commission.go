@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 抽成規則適用範圍
+const (
+	CommissionScopeService = "service" // 服務項目與加項
+	CommissionScopeRetail  = "retail"  // 零售商品
+)
+
+// 抽成計算方式
+const (
+	CommissionTypePercentage = "percentage" // 依金額的比例
+	CommissionTypeFixed      = "fixed"      // 每個項目固定金額
+	CommissionTypeTiered     = "tiered"     // 依當月業績達到的級距決定比例
+)
+
+// CommissionRule 員工抽成規則
+// StaffID 為空時適用所有員工，ServiceID 為空時適用範圍內所有項目；
+// 同一項目符合多條規則時，以指定員工與服務項目者優先
+// 比例以萬分比表示，例如 1000 為 10%；以套票扣抵的項目金額為 0，比例與級距改以扣抵時的目錄售價計算
+type CommissionRule struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	StaffID   *uint           `gorm:"index" json:"staff_id,omitempty"`
+	Scope     string          `gorm:"size:16;not null" json:"scope"`
+	ServiceID *uint           `gorm:"index" json:"service_id,omitempty"`
+	Type      string          `gorm:"size:16;not null" json:"type"`
+	RateBP    int             `gorm:"column:rate_bp;not null;default:0" json:"rate_bp"`
+	Amount    int64           `gorm:"not null;default:0" json:"amount"`
+	Tiers     CommissionTiers `gorm:"type:jsonb" json:"tiers"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CommissionTier 級距抽成的一個級距：當月業績達到 Threshold 時以 RateBP 計算全部業績
+type CommissionTier struct {
+	Threshold int64 `json:"threshold"`
+	RateBP    int   `json:"rate_bp"`
+}
+
+// CommissionTiers 級距抽成的級距，以 JSON 儲存
+type CommissionTiers []CommissionTier
+
+// Value 實作 driver.Valuer
+func (t CommissionTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 實作 sql.Scanner
+func (t *CommissionTiers) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	default:
+		return errors.New("無法解析抽成級距")
+	}
+}
+
+// Specificity 規則的優先程度，數字越大越優先
+func (r *CommissionRule) Specificity() int {
+	score := 0
+	if r.StaffID != nil {
+		score += 2
+	}
+	if r.ServiceID != nil {
+		score++
+	}
+	return score
+}
+
+// CommissionStatement 已結算月份的抽成明細，結算後不可修改
+// Report 為結算當下的完整報表 (JSON)
+type CommissionStatement struct {
+	Month           string          `gorm:"primaryKey;size:7" json:"month"`
+	TotalCommission int64           `gorm:"not null" json:"total_commission"`
+	Report          json.RawMessage `gorm:"type:jsonb;not null" json:"report"`
+	FinalizedAt     time.Time       `gorm:"not null" json:"finalized_at"`
+	FinalizedBy     string          `gorm:"size:255" json:"finalized_by"`
+}
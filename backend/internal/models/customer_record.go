@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// 客戶表單類型
+const (
+	CustomerFormHealth  = "health"  // 健康問卷
+	CustomerFormConsent = "consent" // 療程同意書
+)
+
+// CustomerNote 客戶的一則備註，取代試算表每列一格的「備註」；只能新增，更正請新增一則
+type CustomerNote struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CustomerName string    `gorm:"size:255;not null;index" json:"customer_name"`
+	Body         string    `gorm:"type:text;not null" json:"body"`
+	Author       string    `gorm:"size:255;not null" json:"author"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CustomerTag 客戶的一個自由標籤，例如過敏、VIP、敏感肌；同一位客戶的標籤不重複
+type CustomerTag struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CustomerName string    `gorm:"size:255;not null;uniqueIndex:idx_customer_tags_customer_tag,priority:1" json:"customer_name"`
+	Tag          string    `gorm:"size:32;not null;uniqueIndex:idx_customer_tags_customer_tag,priority:2;index" json:"tag"`
+	CreatedBy    string    `gorm:"size:255" json:"created_by"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// HealthAnswers 健康問卷的內容
+type HealthAnswers struct {
+	Allergies        string `json:"allergies"`
+	Medications      string `json:"medications"`
+	SkinConditions   string `json:"skin_conditions"`
+	RecentProcedures string `json:"recent_procedures"` // 近期的醫美療程或手術
+	Pregnant         bool   `json:"pregnant"`
+	Other            string `json:"other"`
+}
+
+// ConsentAnswers 療程同意書的內容
+type ConsentAnswers struct {
+	Treatment      string `json:"treatment"`
+	RisksExplained bool   `json:"risks_explained"` // 已說明療程風險與注意事項
+	Agreed         bool   `json:"agreed"`
+	PhotoConsent   bool   `json:"photo_consent"` // 同意拍攝療程前後照片
+}
+
+// CustomerForm 客戶簽署的健康問卷或療程同意書，依 Kind 填寫 Health 或 Consent
+// 表單簽署後不可修改，內容變更時重新簽署一份，同類型最新的一份為目前有效
+type CustomerForm struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	CustomerName string          `gorm:"size:255;not null;index" json:"customer_name"`
+	Kind         string          `gorm:"size:16;not null" json:"kind"`
+	Health       *HealthAnswers  `gorm:"type:jsonb;serializer:json" json:"health,omitempty"`
+	Consent      *ConsentAnswers `gorm:"type:jsonb;serializer:json" json:"consent,omitempty"`
+	SignedName   string          `gorm:"size:255;not null" json:"signed_name"` // 簽署人姓名，代簽時可能與客戶名不同
+	SignedAt     time.Time       `gorm:"not null" json:"signed_at"`
+	RecordedBy   string          `gorm:"size:255" json:"recorded_by"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// 庫存異動類型
+const (
+	StockPurchase   = "purchase"   // 進貨
+	StockSale       = "sale"       // 零售售出，由消費紀錄自動扣減
+	StockAdjustment = "adjustment" // 盤點調整、損耗或退貨
+)
+
+// Product 零售商品，對應試算表的「零售」與「當日零售」
+// Stock 為目前庫存，只透過庫存異動變更；Cost 為單位成本，進貨時以移動平均更新，用於計算庫存價值
+type Product struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	SKU          string `gorm:"column:sku;size:64;not null;uniqueIndex" json:"sku"`
+	Name         string `gorm:"size:255;not null" json:"name"`
+	Category     string `gorm:"size:64;not null;default:'';index" json:"category"`
+	Price        int64  `gorm:"not null;default:0" json:"price"`
+	Cost         int64  `gorm:"not null;default:0" json:"cost"`
+	Stock        int    `gorm:"not null;default:0" json:"stock"`
+	ReorderLevel int    `gorm:"not null;default:0" json:"reorder_level"` // 庫存低於或等於此數量時提醒補貨
+	Active       bool   `gorm:"not null;default:true" json:"active"`
+	// LowStockAlertedAt 已發送低庫存通知的時間，庫存回到補貨點以上時清除
+	LowStockAlertedAt *time.Time `json:"low_stock_alerted_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// LowStock 判斷庫存是否已到補貨點
+func (p *Product) LowStock() bool {
+	return p.Stock <= p.ReorderLevel
+}
+
+// StockMovement 商品庫存的一筆異動，Quantity 增加為正、減少為負，StockAfter 為異動後的庫存
+// UnitCost 進貨時為進貨單價，其餘為異動當時的平均成本
+type StockMovement struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ProductID  uint      `gorm:"not null;index" json:"product_id"`
+	Type       string    `gorm:"size:16;not null" json:"type"`
+	Quantity   int       `gorm:"not null" json:"quantity"`
+	StockAfter int       `gorm:"not null" json:"stock_after"`
+	UnitCost   int64     `gorm:"not null;default:0" json:"unit_cost"`
+	VisitID    *uint     `gorm:"index" json:"visit_id,omitempty"`
+	Note       string    `gorm:"size:255" json:"note"`
+	CreatedBy  string    `gorm:"size:255" json:"created_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
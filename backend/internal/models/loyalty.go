@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// 點數異動類型
+const (
+	PointsEarn   = "earn"   // 消費累積
+	PointsRedeem = "redeem" // 消費時折抵
+	PointsExpire = "expire" // 到期失效
+	PointsAdjust = "adjust" // 人工調整
+)
+
+// LoyaltyAccount 客戶的會員點數帳戶，每位客戶一個；餘額只透過帳本異動變更
+type LoyaltyAccount struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CustomerName string    `gorm:"size:255;not null;uniqueIndex" json:"customer_name"`
+	Balance      int64     `gorm:"not null;default:0" json:"balance"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PointsLedgerEntry 點數的一筆異動，Points 增加為正、減少為負，Balance 為異動後的餘額
+type PointsLedgerEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	AccountID uint      `gorm:"not null;index" json:"account_id"`
+	Type      string    `gorm:"size:16;not null" json:"type"`
+	Points    int64     `gorm:"not null" json:"points"`
+	Balance   int64     `gorm:"not null" json:"balance"`
+	VisitID   *uint     `gorm:"index" json:"visit_id,omitempty"`
+	Note      string    `gorm:"size:255" json:"note"`
+	CreatedBy string    `gorm:"size:255" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName 指定資料表名稱
+func (PointsLedgerEntry) TableName() string {
+	return "points_ledger"
+}
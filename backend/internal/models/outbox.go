@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"backend/pkg/notify"
+)
+
+// 通知類型
+const (
+	OutboxKindReminder                = "reminder"
+	OutboxKindAppointmentConfirmation = "appointment_confirmation"
+	OutboxKindLowStock                = "low_stock"
+)
+
+// 通知發送狀態
+const (
+	OutboxPending = "pending" // 等待發送或等待重試
+	OutboxSent    = "sent"
+	OutboxDead    = "dead" // 超過重試次數或無法發送，需要人工處理
+)
+
+// OutboxMessage 待發送的通知 (transactional outbox)
+// 與產生通知的業務資料在同一交易內寫入，由背景工作依 NextAttemptAt 發送並以指數退避重試
+type OutboxMessage struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	Kind          string           `gorm:"size:32;not null" json:"kind"`
+	Channel       string           `gorm:"size:16;not null;index" json:"channel"`
+	Recipient     notify.Recipient `gorm:"type:jsonb;serializer:json;not null" json:"recipient"`
+	Subject       string           `gorm:"size:255" json:"subject"`
+	Body          string           `gorm:"type:text;not null" json:"body"`
+	Status        string           `gorm:"size:16;not null;default:pending" json:"status"`
+	Attempts      int              `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time        `gorm:"not null" json:"next_attempt_at"`
+	LastError     string           `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time       `json:"sent_at,omitempty"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定資料表名稱
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+// Message 轉為通知管道的訊息
+func (m *OutboxMessage) Message() notify.Message {
+	return notify.Message{
+		To:      m.Recipient,
+		Subject: m.Subject,
+		Body:    m.Body,
+	}
+}
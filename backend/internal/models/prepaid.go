@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// 套票狀態
+const (
+	PackageStatusActive    = "active"
+	PackageStatusExhausted = "exhausted" // 堂數已用完
+	PackageStatusExpired   = "expired"
+)
+
+// 帳本異動類型
+const (
+	LedgerPurchase = "purchase" // 購買套票
+	LedgerTopUp    = "top_up"   // 儲值
+	LedgerRedeem   = "redeem"   // 消費時扣抵
+	LedgerExpire   = "expire"   // 到期歸零
+)
+
+// Package 客戶購買的課程套票，例如 10 堂臉部保養
+// ServiceID 為空時可用於任何服務項目；剩餘堂數只透過帳本異動變更
+type Package struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	CustomerName      string     `gorm:"size:255;not null;index" json:"customer_name"`
+	Name              string     `gorm:"size:255;not null" json:"name"`
+	ServiceID         *uint      `gorm:"index" json:"service_id,omitempty"`
+	TotalSessions     int        `gorm:"not null" json:"total_sessions"`
+	RemainingSessions int        `gorm:"not null" json:"remaining_sessions"`
+	Price             int64      `gorm:"not null" json:"price"`
+	Status            string     `gorm:"size:16;not null;default:active;index" json:"status"`
+	PurchasedAt       time.Time  `gorm:"not null" json:"purchased_at"`
+	ExpiresAt         *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	CreatedBy         string     `gorm:"size:255" json:"created_by"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PackageLedgerEntry 套票堂數的一筆異動，Remaining 為異動後的剩餘堂數
+type PackageLedgerEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PackageID uint      `gorm:"not null;index" json:"package_id"`
+	Type      string    `gorm:"size:16;not null" json:"type"`
+	Sessions  int       `gorm:"not null" json:"sessions"`
+	Remaining int       `gorm:"not null" json:"remaining"`
+	VisitID   *uint     `gorm:"index" json:"visit_id,omitempty"`
+	Note      string    `gorm:"size:255" json:"note"`
+	CreatedBy string    `gorm:"size:255" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定資料表名稱
+func (PackageLedgerEntry) TableName() string {
+	return "package_ledger"
+}
+
+// Wallet 客戶的儲值金帳戶，每位客戶一個；餘額只透過帳本異動變更
+type Wallet struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	CustomerName string     `gorm:"size:255;not null;uniqueIndex" json:"customer_name"`
+	Balance      int64      `gorm:"not null;default:0" json:"balance"`
+	ExpiresAt    *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WalletLedgerEntry 儲值金的一筆異動，Balance 為異動後的餘額
+type WalletLedgerEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	WalletID  uint      `gorm:"not null;index" json:"wallet_id"`
+	Type      string    `gorm:"size:16;not null" json:"type"`
+	Amount    int64     `gorm:"not null" json:"amount"`
+	Balance   int64     `gorm:"not null" json:"balance"`
+	VisitID   *uint     `gorm:"index" json:"visit_id,omitempty"`
+	Note      string    `gorm:"size:255" json:"note"`
+	CreatedBy string    `gorm:"size:255" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定資料表名稱
+func (WalletLedgerEntry) TableName() string {
+	return "wallet_ledger"
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// 提醒類型
+const (
+	ReminderBirthday     = "birthday"     // 生日提醒
+	ReminderReactivation = "reactivation" // 久未到店的回流提醒
+)
+
+// 提醒狀態，提醒交由通知佇列發送，實際發送結果見 Delivery
+const (
+	ReminderQueued  = "queued"  // 已加入通知佇列
+	ReminderSkipped = "skipped" // 客戶缺少該管道的聯絡方式
+)
+
+// Reminder 一則提醒的發送紀錄
+// 同一位客戶、同一類型、同一期間 (生日為年份，回流為最後消費日期) 只會有一筆，用於避免重複發送
+type Reminder struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	CustomerName string         `gorm:"size:255;not null;uniqueIndex:idx_reminders_dedup,priority:1" json:"customer_name"`
+	Kind         string         `gorm:"size:16;not null;uniqueIndex:idx_reminders_dedup,priority:2" json:"kind"`
+	PeriodKey    string         `gorm:"size:32;not null;uniqueIndex:idx_reminders_dedup,priority:3" json:"period_key"`
+	Channel      string         `gorm:"size:16;not null" json:"channel"`
+	Status       string         `gorm:"size:16;not null;index" json:"status"`
+	Error        string         `gorm:"type:text" json:"error,omitempty"`
+	OutboxID     *uint          `gorm:"index" json:"outbox_id,omitempty"`
+	Delivery     *OutboxMessage `gorm:"foreignKey:OutboxID" json:"delivery,omitempty"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
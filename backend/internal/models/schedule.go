@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ClockTime 一天中的時間，以距離午夜的分鐘數儲存，JSON 格式為 "HH:MM"
+type ClockTime int
+
+// ParseClockTime 解析 "HH:MM" 格式的時間，允許 "24:00" 表示一天結束
+func ParseClockTime(value string) (ClockTime, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("時間格式錯誤，請使用 HH:MM: %q", value)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("時間超出範圍: %q", value)
+	}
+	return ClockTime(hour*60 + minute), nil
+}
+
+// String 返回 "HH:MM" 格式
+func (t ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+// On 返回 date 當天 (依 date 的時區) 的這個時間
+func (t ClockTime) On(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, int(t), 0, 0, date.Location())
+}
+
+// MarshalJSON 以 "HH:MM" 輸出
+func (t ClockTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON 解析 "HH:MM"
+func (t *ClockTime) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseClockTime(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// StaffWorkingHours 員工每週固定的上班時段，同一天可有多個時段 (例如中午休息的分段班)
+// Weekday 與 time.Weekday 相同，0 為星期日
+type StaffWorkingHours struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	StaffID   uint      `gorm:"not null;index" json:"-"`
+	Weekday   int       `gorm:"not null" json:"weekday"`
+	StartTime ClockTime `gorm:"not null" json:"start"`
+	EndTime   ClockTime `gorm:"not null" json:"end"`
+}
+
+// StaffBreak 員工每週固定的休息時間，休息時間內不可預約
+type StaffBreak struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	StaffID   uint      `gorm:"not null;index" json:"-"`
+	Weekday   int       `gorm:"not null" json:"weekday"`
+	StartTime ClockTime `gorm:"not null" json:"start"`
+	EndTime   ClockTime `gorm:"not null" json:"end"`
+}
+
+// StaffTimeOff 員工的休假，未設定起訖時間時為整天休假
+type StaffTimeOff struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	StaffID   uint       `gorm:"not null;index" json:"staff_id"`
+	Date      time.Time  `gorm:"type:date;not null;index" json:"date"`
+	StartTime *ClockTime `json:"start,omitempty"`
+	EndTime   *ClockTime `json:"end,omitempty"`
+	Reason    string     `gorm:"size:255" json:"reason"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定資料表名稱
+func (StaffTimeOff) TableName() string {
+	return "staff_time_off"
+}
+
+// Holiday 店休日 (例如國定假日)，當天所有員工皆不可預約
+type Holiday struct {
+	Date      time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
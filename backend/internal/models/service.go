@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// Service 店內提供的服務項目，對應試算表的「服務項目」與「加購項目」
+// Price 為目前售價 (新台幣元)，調價紀錄保存在 ServicePrice；DurationMinutes 用於計算可預約時段
+type Service struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Name            string    `gorm:"size:255;not null;uniqueIndex" json:"name"`
+	Category        string    `gorm:"size:64;not null;default:'';index" json:"category"`
+	Price           int64     `gorm:"not null;default:0" json:"price"`
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+	Active          bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ServicePrice 服務項目的一次定價，新增項目或調價時寫入
+type ServicePrice struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ServiceID   uint      `gorm:"not null;index" json:"service_id"`
+	Price       int64     `gorm:"not null" json:"price"`
+	EffectiveAt time.Time `gorm:"not null" json:"effective_at"`
+	ChangedBy   string    `gorm:"size:255" json:"changed_by"`
+}
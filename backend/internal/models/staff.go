@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// Staff 代表提供服務的員工 (美容師)，不一定有系統帳號
+type Staff struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	UserID    *string   `gorm:"size:255;index" json:"user_id,omitempty"` // 對應的系統使用者
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定資料表名稱 (staff 為不可數名詞)
+func (Staff) TableName() string {
+	return "staff"
+}
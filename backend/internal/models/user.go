@@ -5,12 +5,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// 使用者角色
+const (
+	RoleStaff = "staff"
+	RoleAdmin = "admin"
+)
+
 /// User 代表系統使用者，使用 GORM 標籤
 type User struct {
 	ID        string         `gorm:"primaryKey" json:"id"`
 	Email     string         `gorm:"uniqueIndex;size:255;not null" json:"email"`
 	Name      string         `gorm:"size:255;not null" json:"name"`
 	Picture   string         `gorm:"type:text" json:"picture"`
+	Role      string         `gorm:"size:32;not null;default:staff" json:"role"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	LastLogin time.Time      `gorm:"autoUpdateTime:false" json:"last_login"`
 	Sessions  []Session      `gorm:"foreignKey:UserID" json:"-"`
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// 消費項目類型，對應試算表的「服務項目」、「加項」與「零售」欄位
+const (
+	VisitItemService = "service"
+	VisitItemExtra   = "extra"
+	VisitItemRetail  = "retail"
+)
+
+// Visit 代表一筆客戶消費紀錄 (來店紀錄)，Total 對應試算表的「總額」
+type Visit struct {
+	ID             uint        `gorm:"primaryKey" json:"id"`
+	CustomerName   string      `gorm:"size:255;not null;index" json:"customer_name"`
+	VisitDate      time.Time   `gorm:"type:date;not null;index" json:"visit_date"`
+	StaffID        *uint       `gorm:"index" json:"staff_id,omitempty"`
+	StaffName      string      `gorm:"size:255" json:"staff_name"`
+	AppointmentID  *uint       `gorm:"uniqueIndex" json:"appointment_id,omitempty"`
+	Items          []VisitItem `gorm:"foreignKey:VisitID" json:"items"`
+	Note           string      `gorm:"type:text" json:"note"`
+	Total          int64       `gorm:"not null" json:"total"`
+	WalletPaid     int64       `gorm:"not null;default:0" json:"wallet_paid"`     // 以儲值金支付的金額
+	PointsRedeemed int64       `gorm:"not null;default:0" json:"points_redeemed"` // 折抵使用的點數
+	PointsDiscount int64       `gorm:"not null;default:0" json:"points_discount"` // 點數折抵的金額
+	PointsEarned   int64       `gorm:"not null;default:0" json:"points_earned"`   // 本次累積的點數
+	CreatedBy      string      `gorm:"size:255" json:"created_by"`
+	CreatedAt      time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// VisitItem 消費紀錄中的一個項目，金額單位為新台幣元
+type VisitItem struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	VisitID   uint   `gorm:"not null;index" json:"-"`
+	ServiceID *uint  `gorm:"index" json:"service_id,omitempty"` // 對應的服務項目，舊資料或預約轉入的項目可能為空
+	PackageID *uint  `gorm:"index" json:"package_id,omitempty"` // 以套票扣抵時的套票，金額為 0
+	ProductID *uint  `gorm:"index" json:"product_id,omitempty"` // 零售項目的商品，寫入時自動扣減庫存
+	Kind      string `gorm:"size:16;not null" json:"kind"`
+	Name      string `gorm:"size:255;not null" json:"name"`
+	Quantity  int    `gorm:"not null;default:1" json:"quantity"`
+	Price     int64  `gorm:"not null" json:"price"`                          // 項目金額 (數量 × 單價)
+	ListPrice int64  `gorm:"not null;default:0" json:"list_price,omitempty"` // 以套票扣抵時的目錄售價，抽成依此計算
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// 預約寫入錯誤
+var (
+	ErrAppointmentConflict      = errors.New("員工時段已被預約")
+	ErrAppointmentClosed        = errors.New("預約已結束")
+	ErrAppointmentStatusChanged = errors.New("預約狀態已被變更")
+)
+
+// appointmentLockNamespace 預約時段檢查使用的 advisory lock 命名空間，
+// 同一員工的新增與修改依序執行，避免同時建立重疊的預約
+const appointmentLockNamespace = 41001
+
+// AppointmentFilter 預約查詢條件，零值欄位代表不篩選
+type AppointmentFilter struct {
+	StaffID      uint
+	CustomerName string
+	Status       string
+	From         time.Time
+	To           time.Time
+}
+
+// AppointmentRepository 提供預約的資料存取方法
+type AppointmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAppointmentRepository 創建一個新的預約資料存取層
+func NewAppointmentRepository(db *gorm.DB) *AppointmentRepository {
+	return &AppointmentRepository{
+		db: db,
+	}
+}
+
+// ListAppointments 依條件查詢預約，依開始時間排序
+func (r *AppointmentRepository) ListAppointments(ctx context.Context, filter AppointmentFilter) ([]models.Appointment, error) {
+	query := r.db.WithContext(ctx).Preload("Items").Preload("Staff")
+
+	if filter.StaffID != 0 {
+		query = query.Where("staff_id = ?", filter.StaffID)
+	}
+	if filter.CustomerName != "" {
+		query = query.Where("customer_name = ?", filter.CustomerName)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("end_at > ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("start_at < ?", filter.To)
+	}
+
+	var appointments []models.Appointment
+	if err := query.Order("start_at, id").Find(&appointments).Error; err != nil {
+		return nil, fmt.Errorf("查詢預約失敗: %w", err)
+	}
+
+	return appointments, nil
+}
+
+// GetAppointmentByID 透過 ID 查找預約 (包含項目與員工)，不存在時返回 nil
+func (r *AppointmentRepository) GetAppointmentByID(ctx context.Context, id uint) (*models.Appointment, error) {
+	var appointment models.Appointment
+
+	result := r.db.WithContext(ctx).Preload("Items").Preload("Staff").First(&appointment, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢預約失敗: %w", result.Error)
+	}
+
+	return &appointment, nil
+}
+
+// CreateAppointment 在確認員工時段沒有重疊後新增預約與其項目
+// confirmation 不為 nil 時在同一交易內將預約確認通知加入通知佇列；時段重疊時返回 ErrAppointmentConflict
+func (r *AppointmentRepository) CreateAppointment(ctx context.Context, appointment *models.Appointment, confirmation *models.OutboxMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockStaffSchedule(tx, appointment.StaffID); err != nil {
+			return err
+		}
+		if err := checkOverlap(tx, appointment); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Staff").Create(appointment).Error; err != nil {
+			return fmt.Errorf("新增預約失敗: %w", err)
+		}
+		if confirmation != nil {
+			return enqueue(tx, confirmation)
+		}
+		return nil
+	})
+}
+
+// UpdateAppointment 更新預約內容並以新的項目取代舊項目，狀態不在此變更
+// 在交易內鎖定預約並重新確認仍可修改，已結束或已轉為消費紀錄時返回 ErrAppointmentClosed；
+// 時段重疊時返回 ErrAppointmentConflict
+func (r *AppointmentRepository) UpdateAppointment(ctx context.Context, appointment *models.Appointment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockStaffSchedule(tx, appointment.StaffID); err != nil {
+			return err
+		}
+
+		var current models.Appointment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, appointment.ID).Error
+		if err != nil {
+			return fmt.Errorf("查詢預約失敗: %w", err)
+		}
+		if !current.Open() {
+			return ErrAppointmentClosed
+		}
+		appointment.Status = current.Status
+
+		if err := checkOverlap(tx, appointment); err != nil {
+			return err
+		}
+
+		result := tx.Model(appointment).
+			Select("customer_name", "customer_phone", "staff_id", "start_at", "end_at", "note").
+			Updates(appointment)
+		if result.Error != nil {
+			return fmt.Errorf("更新預約失敗: %w", result.Error)
+		}
+
+		if err := tx.Where("appointment_id = ?", appointment.ID).Delete(&models.AppointmentItem{}).Error; err != nil {
+			return fmt.Errorf("更新預約項目失敗: %w", err)
+		}
+		for i := range appointment.Items {
+			appointment.Items[i].ID = 0
+			appointment.Items[i].AppointmentID = appointment.ID
+		}
+		if len(appointment.Items) > 0 {
+			if err := tx.Create(&appointment.Items).Error; err != nil {
+				return fmt.Errorf("更新預約項目失敗: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateAppointmentStatus 將預約狀態由 from 變更為 to
+// 狀態在讀取後已被其他請求變更時不更新，並返回 ErrAppointmentStatusChanged
+func (r *AppointmentRepository) UpdateAppointmentStatus(ctx context.Context, id uint, from, to string) error {
+	result := r.db.WithContext(ctx).Model(&models.Appointment{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return fmt.Errorf("更新預約狀態失敗: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAppointmentStatusChanged
+	}
+
+	return nil
+}
+
+// DeleteAppointment 刪除預約與其項目
+// 在交易內鎖定預約並重新確認尚未轉為消費紀錄，已轉換時返回 ErrAppointmentConverted；
+// 預約已被刪除時不視為錯誤
+func (r *AppointmentRepository) DeleteAppointment(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var appointment models.Appointment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&appointment, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("查詢預約失敗: %w", err)
+		}
+		if appointment.VisitID != nil {
+			return ErrAppointmentConverted
+		}
+
+		if err := tx.Delete(&appointment).Error; err != nil {
+			return fmt.Errorf("刪除預約失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// lockStaffSchedule 在交易內鎖定員工的排程，交易結束時自動釋放
+func lockStaffSchedule(tx *gorm.DB, staffID uint) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", appointmentLockNamespace, int32(staffID)).Error; err != nil {
+		return fmt.Errorf("鎖定員工排程失敗: %w", err)
+	}
+	return nil
+}
+
+// checkOverlap 確認員工在預約時段內沒有其他佔用時段的預約
+func checkOverlap(tx *gorm.DB, appointment *models.Appointment) error {
+	if !appointment.OccupiesSlot() {
+		return nil
+	}
+
+	var count int64
+	err := tx.Model(&models.Appointment{}).
+		Where("staff_id = ? AND id <> ?", appointment.StaffID, appointment.ID).
+		Where("status NOT IN ?", []string{models.AppointmentStatusCancelled, models.AppointmentStatusNoShow}).
+		Where("start_at < ? AND end_at > ?", appointment.EndAt, appointment.StartAt).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("檢查預約時段失敗: %w", err)
+	}
+	if count > 0 {
+		return ErrAppointmentConflict
+	}
+	return nil
+}
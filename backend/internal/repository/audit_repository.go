@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditFilter 稽核紀錄查詢條件，零值欄位代表不篩選
+type AuditFilter struct {
+	ActorID        string
+	ActorEmail     string
+	Action         string
+	TargetCustomer string
+	From           time.Time
+	To             time.Time
+	Limit          int
+	Offset         int
+}
+
+// AuditRepository 提供稽核紀錄的資料存取方法
+// 稽核紀錄為僅可附加 (append-only)，因此不提供更新與刪除方法
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 創建一個新的稽核紀錄資料存取層
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+// CreateAuditLog 新增一筆稽核紀錄
+func (r *AuditRepository) CreateAuditLog(ctx context.Context, entry *models.AuditLog) error {
+	result := r.db.WithContext(ctx).Create(entry)
+	if result.Error != nil {
+		return fmt.Errorf("新增稽核紀錄失敗: %w", result.Error)
+	}
+
+	return nil
+}
+
+// ListAuditLogs 依條件查詢稽核紀錄，回傳符合條件的紀錄與總筆數
+func (r *AuditRepository) ListAuditLogs(ctx context.Context, filter AuditFilter) ([]models.AuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.ActorEmail != "" {
+		query = query.Where("actor_email = ?", filter.ActorEmail)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetCustomer != "" {
+		query = query.Where("target_customer = ?", filter.TargetCustomer)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("計算稽核紀錄失敗: %w", err)
+	}
+
+	var logs []models.AuditLog
+	query = query.Order("created_at DESC, id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("查詢稽核紀錄失敗: %w", err)
+	}
+
+	return logs, total, nil
+}
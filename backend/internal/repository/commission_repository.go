@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+var (
+	// ErrStatementFinalized 該月份已結算
+	ErrStatementFinalized = errors.New("該月份的抽成已結算")
+	// ErrMonthClosed 消費日期所在月份已結算，不可再新增消費紀錄
+	ErrMonthClosed = errors.New("消費日期所在月份已結算")
+)
+
+// commissionMonthLockNamespace 月結 advisory lock 的命名空間
+// 結算時取得獨佔鎖，新增消費紀錄時取得共享鎖，避免結算期間有新的消費紀錄寫入
+const commissionMonthLockNamespace = 44001
+
+// monthLayout 月份格式
+const monthLayout = "2006-01"
+
+// CommissionRepository 提供抽成規則與月結明細的存取方法
+type CommissionRepository struct {
+	db *gorm.DB
+}
+
+// NewCommissionRepository 創建一個新的抽成資料存取層
+func NewCommissionRepository(db *gorm.DB) *CommissionRepository {
+	return &CommissionRepository{
+		db: db,
+	}
+}
+
+// ListRules 列出所有抽成規則
+func (r *CommissionRepository) ListRules(ctx context.Context) ([]models.CommissionRule, error) {
+	var rules []models.CommissionRule
+	if err := r.db.WithContext(ctx).Order("scope, staff_id NULLS FIRST, service_id NULLS FIRST, id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查詢抽成規則失敗: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRuleByID 透過 ID 查找抽成規則，不存在時返回 nil
+func (r *CommissionRepository) GetRuleByID(ctx context.Context, id uint) (*models.CommissionRule, error) {
+	var rule models.CommissionRule
+
+	result := r.db.WithContext(ctx).First(&rule, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢抽成規則失敗: %w", result.Error)
+	}
+	return &rule, nil
+}
+
+// CreateRule 新增抽成規則
+func (r *CommissionRepository) CreateRule(ctx context.Context, rule *models.CommissionRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return fmt.Errorf("新增抽成規則失敗: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule 更新抽成規則
+func (r *CommissionRepository) UpdateRule(ctx context.Context, rule *models.CommissionRule) error {
+	err := r.db.WithContext(ctx).Model(rule).
+		Select("staff_id", "scope", "service_id", "type", "rate_bp", "amount", "tiers").
+		Updates(rule).Error
+	if err != nil {
+		return fmt.Errorf("更新抽成規則失敗: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule 刪除抽成規則，返回是否有資料被刪除
+func (r *CommissionRepository) DeleteRule(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&models.CommissionRule{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("刪除抽成規則失敗: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetStatement 取得月份 (YYYY-MM) 的結算明細，尚未結算時返回 nil
+func (r *CommissionRepository) GetStatement(ctx context.Context, month string) (*models.CommissionStatement, error) {
+	var statement models.CommissionStatement
+
+	result := r.db.WithContext(ctx).Where("month = ?", month).First(&statement)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢抽成明細失敗: %w", result.Error)
+	}
+	return &statement, nil
+}
+
+// FinalizeStatement 結算月份：持有該月份的獨佔鎖期間以 build 產生明細並寫入
+// 進行中的消費紀錄交易完成前不會開始計算；該月份已結算時返回 ErrStatementFinalized
+func (r *CommissionRepository) FinalizeStatement(ctx context.Context, month time.Time, build func(ctx context.Context) (*models.CommissionStatement, error)) (*models.CommissionStatement, error) {
+	var statement *models.CommissionStatement
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockMonth(tx, month, false); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.CommissionStatement{}).Where("month = ?", month.Format(monthLayout)).Count(&count).Error; err != nil {
+			return fmt.Errorf("查詢抽成明細失敗: %w", err)
+		}
+		if count > 0 {
+			return ErrStatementFinalized
+		}
+
+		var err error
+		if statement, err = build(ctx); err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(statement)
+		if result.Error != nil {
+			return fmt.Errorf("寫入抽成明細失敗: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatementFinalized
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// lockMonth 在交易內鎖定月份，shared 為 true 時取得共享鎖
+func lockMonth(tx *gorm.DB, month time.Time, shared bool) error {
+	fn := "pg_advisory_xact_lock"
+	if shared {
+		fn = "pg_advisory_xact_lock_shared"
+	}
+	key := int32(month.Year()*100 + int(month.Month()))
+	if err := tx.Exec("SELECT "+fn+"(?, ?)", commissionMonthLockNamespace, key).Error; err != nil {
+		return fmt.Errorf("鎖定結算月份失敗: %w", err)
+	}
+	return nil
+}
+
+// ensureMonthOpen 確認日期所在月份尚未結算，並持有共享鎖直到交易結束
+func ensureMonthOpen(tx *gorm.DB, date time.Time) error {
+	if err := lockMonth(tx, date, true); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.CommissionStatement{}).Where("month = ?", date.Format(monthLayout)).Count(&count).Error; err != nil {
+		return fmt.Errorf("查詢抽成明細失敗: %w", err)
+	}
+	if count > 0 {
+		return ErrMonthClosed
+	}
+	return nil
+}
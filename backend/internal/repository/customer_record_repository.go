@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// TagCount 一個標籤與使用它的客戶數
+type TagCount struct {
+	Tag       string `json:"tag"`
+	Customers int    `json:"customers"`
+}
+
+// CustomerRecordRepository 提供客戶備註、標籤與表單的存取方法
+type CustomerRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRecordRepository 創建一個新的客戶紀錄資料存取層
+func NewCustomerRecordRepository(db *gorm.DB) *CustomerRecordRepository {
+	return &CustomerRecordRepository{
+		db: db,
+	}
+}
+
+// ListNotes 列出客戶的備註，新的在前
+func (r *CustomerRecordRepository) ListNotes(ctx context.Context, customerName string) ([]models.CustomerNote, error) {
+	var notes []models.CustomerNote
+	err := r.db.WithContext(ctx).
+		Where("customer_name = ?", customerName).
+		Order("created_at DESC, id DESC").
+		Find(&notes).Error
+	if err != nil {
+		return nil, fmt.Errorf("查詢客戶備註失敗: %w", err)
+	}
+	return notes, nil
+}
+
+// CreateNote 新增客戶備註
+func (r *CustomerRecordRepository) CreateNote(ctx context.Context, note *models.CustomerNote) error {
+	if err := r.db.WithContext(ctx).Create(note).Error; err != nil {
+		return fmt.Errorf("新增客戶備註失敗: %w", err)
+	}
+	return nil
+}
+
+// ListTags 列出客戶的標籤，依名稱排序
+func (r *CustomerRecordRepository) ListTags(ctx context.Context, customerName string) ([]string, error) {
+	tags := []string{}
+	err := r.db.WithContext(ctx).Model(&models.CustomerTag{}).
+		Where("customer_name = ?", customerName).
+		Order("tag").
+		Pluck("tag", &tags).Error
+	if err != nil {
+		return nil, fmt.Errorf("查詢客戶標籤失敗: %w", err)
+	}
+	return tags, nil
+}
+
+// ReplaceTags 以 tags 取代客戶的標籤，保留既有標籤的建立者與時間
+func (r *CustomerRecordRepository) ReplaceTags(ctx context.Context, customerName string, tags []string, createdBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		remove := tx.Where("customer_name = ?", customerName)
+		if len(tags) > 0 {
+			remove = remove.Where("tag NOT IN ?", tags)
+		}
+		if err := remove.Delete(&models.CustomerTag{}).Error; err != nil {
+			return fmt.Errorf("刪除客戶標籤失敗: %w", err)
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+
+		rows := make([]models.CustomerTag, len(tags))
+		for i, tag := range tags {
+			rows[i] = models.CustomerTag{CustomerName: customerName, Tag: tag, CreatedBy: createdBy}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return fmt.Errorf("新增客戶標籤失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountTags 列出所有標籤與使用它的客戶數，依名稱排序
+func (r *CustomerRecordRepository) CountTags(ctx context.Context) ([]TagCount, error) {
+	counts := []TagCount{}
+	err := r.db.WithContext(ctx).Model(&models.CustomerTag{}).
+		Select("tag, COUNT(*) AS customers").
+		Group("tag").
+		Order("tag").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("查詢標籤失敗: %w", err)
+	}
+	return counts, nil
+}
+
+// CustomersWithTags 返回同時擁有所有 tags 的客戶名
+func (r *CustomerRecordRepository) CustomersWithTags(ctx context.Context, tags []string) ([]string, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Model(&models.CustomerTag{}).
+		Where("tag IN ?", tags).
+		Group("customer_name").
+		Having("COUNT(*) = ?", len(tags)).
+		Pluck("customer_name", &names).Error
+	if err != nil {
+		return nil, fmt.Errorf("依標籤查詢客戶失敗: %w", err)
+	}
+	return names, nil
+}
+
+// ListForms 列出客戶簽署的表單，kind 為空時列出全部，新的在前
+func (r *CustomerRecordRepository) ListForms(ctx context.Context, customerName, kind string) ([]models.CustomerForm, error) {
+	query := r.db.WithContext(ctx).Where("customer_name = ?", customerName)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var forms []models.CustomerForm
+	if err := query.Order("signed_at DESC, id DESC").Find(&forms).Error; err != nil {
+		return nil, fmt.Errorf("查詢客戶表單失敗: %w", err)
+	}
+	return forms, nil
+}
+
+// GetFormByID 透過 ID 查找表單，不存在時返回 nil
+func (r *CustomerRecordRepository) GetFormByID(ctx context.Context, id uint) (*models.CustomerForm, error) {
+	var form models.CustomerForm
+
+	result := r.db.WithContext(ctx).First(&form, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢客戶表單失敗: %w", result.Error)
+	}
+
+	return &form, nil
+}
+
+// CreateForm 新增簽署的表單
+func (r *CustomerRecordRepository) CreateForm(ctx context.Context, form *models.CustomerForm) error {
+	if err := r.db.WithContext(ctx).Create(form).Error; err != nil {
+		return fmt.Errorf("新增客戶表單失敗: %w", err)
+	}
+	return nil
+}
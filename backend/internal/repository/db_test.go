@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend/pkg/migrations"
+)
+
+// openTestDB 連接 TEST_DATABASE_URL 指定的 PostgreSQL，在獨立的 schema 套用所有遷移，測試結束時刪除
+// 未設定 TEST_DATABASE_URL 時略過測試，需要資料庫的測試只在 CI 或本機有資料庫時執行
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("未設定 TEST_DATABASE_URL，略過需要資料庫的測試")
+	}
+
+	config := &gorm.Config{Logger: logger.Discard}
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("連接測試資料庫失敗: %v", err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("建立測試 schema 失敗: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema)), config)
+	if err != nil {
+		t.Fatalf("連接測試 schema 失敗: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("取得 DB 連接失敗: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		t.Fatalf("載入遷移失敗: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("套用遷移失敗: %v", err)
+	}
+	return db
+}
+
+// withSearchPath 在 DSN 加上 search_path，支援 URL 與 key=value 兩種格式
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			query := u.Query()
+			query.Set("search_path", schema)
+			u.RawQuery = query.Encode()
+			return u.String()
+		}
+	}
+	return dsn + " search_path=" + schema
+}
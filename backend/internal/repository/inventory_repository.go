@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// ErrInsufficientStock 商品庫存不足
+var ErrInsufficientStock = errors.New("商品庫存不足")
+
+// ProductFilter 商品查詢條件，零值欄位代表不篩選
+type ProductFilter struct {
+	Category     string
+	ActiveOnly   bool
+	LowStockOnly bool
+}
+
+// InventoryRepository 提供零售商品與庫存異動的存取方法
+// 庫存只在交易內鎖定商品後變更，並同時寫入異動紀錄
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+// NewInventoryRepository 創建一個新的庫存資料存取層
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: db,
+	}
+}
+
+// ListProducts 依條件列出商品，依分類與名稱排序
+func (r *InventoryRepository) ListProducts(ctx context.Context, filter ProductFilter) ([]models.Product, error) {
+	query := r.db.WithContext(ctx).Order("category, name, id")
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("active = ?", true)
+	}
+	if filter.LowStockOnly {
+		query = query.Where("stock <= reorder_level")
+	}
+
+	var products []models.Product
+	if err := query.Find(&products).Error; err != nil {
+		return nil, fmt.Errorf("查詢商品失敗: %w", err)
+	}
+	return products, nil
+}
+
+// GetProductByID 透過 ID 查找商品，不存在時返回 nil
+func (r *InventoryRepository) GetProductByID(ctx context.Context, id uint) (*models.Product, error) {
+	var product models.Product
+
+	result := r.db.WithContext(ctx).First(&product, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢商品失敗: %w", result.Error)
+	}
+
+	return &product, nil
+}
+
+// GetProductBySKU 透過 SKU 查找商品，不存在時返回 nil
+func (r *InventoryRepository) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	var product models.Product
+
+	result := r.db.WithContext(ctx).Where("sku = ?", sku).First(&product)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢商品失敗: %w", result.Error)
+	}
+
+	return &product, nil
+}
+
+// GetProductsByIDs 一次查詢多個商品，以 ID 為鍵返回，不存在的 ID 不會出現在結果中
+func (r *InventoryRepository) GetProductsByIDs(ctx context.Context, ids []uint) (map[uint]models.Product, error) {
+	result := make(map[uint]models.Product, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var products []models.Product
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, fmt.Errorf("查詢商品失敗: %w", err)
+	}
+	for _, product := range products {
+		result[product.ID] = product
+	}
+	return result, nil
+}
+
+// CreateProduct 新增商品，初始庫存為 0
+func (r *InventoryRepository) CreateProduct(ctx context.Context, product *models.Product) error {
+	if err := r.db.WithContext(ctx).Create(product).Error; err != nil {
+		return fmt.Errorf("新增商品失敗: %w", err)
+	}
+	return nil
+}
+
+// UpdateProduct 在交易內鎖定商品後以 update 修改商品資料，返回更新後的商品，庫存只透過異動變更
+// 成本與低庫存通知紀錄只在 update 變更時寫入，避免覆蓋同時進貨算出的平均成本或通知紀錄；
+// 商品不存在時返回 nil，update 返回錯誤時不寫入並原樣返回該錯誤
+func (r *InventoryRepository) UpdateProduct(ctx context.Context, id uint, update func(product *models.Product) error) (*models.Product, error) {
+	var product models.Product
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error
+		if err != nil {
+			return err
+		}
+
+		cost, alertedAt := product.Cost, product.LowStockAlertedAt
+		if err := update(&product); err != nil {
+			return err
+		}
+
+		columns := []string{"sku", "name", "category", "price", "reorder_level", "active"}
+		if product.Cost != cost {
+			columns = append(columns, "cost")
+		}
+		if !equalTime(product.LowStockAlertedAt, alertedAt) {
+			columns = append(columns, "low_stock_alerted_at")
+		}
+		if err := tx.Model(&product).Select(columns).Updates(&product).Error; err != nil {
+			return fmt.Errorf("更新商品失敗: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// RecordMovement 寫入一筆進貨或盤點調整並更新庫存，返回更新後的商品
+// 商品不存在時返回 nil，調整後庫存為負時返回 ErrInsufficientStock
+func (r *InventoryRepository) RecordMovement(ctx context.Context, movement *models.StockMovement) (*models.Product, error) {
+	var product models.Product
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, movement.ProductID).Error
+		if err != nil {
+			return err
+		}
+		return moveStock(tx, &product, movement)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if errors.Is(err, ErrInsufficientStock) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("寫入庫存異動失敗: %w", err)
+	}
+	return &product, nil
+}
+
+// ListMovements 列出商品的庫存異動，新的在前
+func (r *InventoryRepository) ListMovements(ctx context.Context, productID uint, limit int) ([]models.StockMovement, error) {
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var movements []models.StockMovement
+	if err := query.Order("created_at DESC, id DESC").Find(&movements).Error; err != nil {
+		return nil, fmt.Errorf("查詢庫存異動失敗: %w", err)
+	}
+	return movements, nil
+}
+
+// AlertLowStock 找出到達補貨點且尚未通知的上架商品，以 build 組合一則通知並在同一交易內加入通知佇列
+// 返回本次通知的商品；build 返回 nil 時不通知也不標記
+func (r *InventoryRepository) AlertLowStock(ctx context.Context, build func([]models.Product) *models.OutboxMessage) ([]models.Product, error) {
+	var products []models.Product
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("active = ? AND stock <= reorder_level AND low_stock_alerted_at IS NULL", true).
+			Order("category, name, id").
+			Find(&products).Error
+		if err != nil {
+			return fmt.Errorf("查詢低庫存商品失敗: %w", err)
+		}
+		if len(products) == 0 {
+			return nil
+		}
+
+		msg := build(products)
+		if msg == nil {
+			products = nil
+			return nil
+		}
+		if err := enqueue(tx, msg); err != nil {
+			return err
+		}
+
+		ids := make([]uint, len(products))
+		for i, product := range products {
+			ids[i] = product.ID
+		}
+		err = tx.Model(&models.Product{}).Where("id IN ?", ids).Update("low_stock_alerted_at", time.Now()).Error
+		if err != nil {
+			return fmt.Errorf("更新商品失敗: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// deductStock 依消費紀錄的零售項目扣減庫存，庫存不足時返回 ErrInsufficientStock
+// 依商品 ID 順序鎖定，避免同時寫入的消費紀錄互相等待
+func deductStock(tx *gorm.DB, visit *models.Visit) error {
+	quantities := make(map[uint]int)
+	for _, item := range visit.Items {
+		if item.ProductID != nil {
+			quantities[*item.ProductID] += item.Quantity
+		}
+	}
+
+	ids := make([]uint, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		var product models.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error
+		if err != nil {
+			return fmt.Errorf("查詢商品失敗: %w", err)
+		}
+
+		movement := &models.StockMovement{
+			ProductID: id,
+			Type:      models.StockSale,
+			Quantity:  -quantities[id],
+			VisitID:   &visit.ID,
+			CreatedBy: visit.CreatedBy,
+		}
+		if err := moveStock(tx, &product, movement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveStock 依異動更新已鎖定的商品庫存並寫入異動紀錄
+func moveStock(tx *gorm.DB, product *models.Product, movement *models.StockMovement) error {
+	if err := applyMovement(product, movement); err != nil {
+		return err
+	}
+
+	err := tx.Model(product).Select("stock", "cost", "low_stock_alerted_at").Updates(product).Error
+	if err != nil {
+		return fmt.Errorf("更新商品庫存失敗: %w", err)
+	}
+
+	if err := tx.Create(movement).Error; err != nil {
+		return fmt.Errorf("寫入庫存異動失敗: %w", err)
+	}
+	return nil
+}
+
+// applyMovement 依異動計算商品的庫存與成本，並填入異動後的庫存與單位成本，調整後庫存為負時返回 ErrInsufficientStock
+// 進貨以移動平均重新計算單位成本；庫存回到補貨點以上時清除低庫存通知紀錄，下次到達補貨點會再通知
+func applyMovement(product *models.Product, movement *models.StockMovement) error {
+	stock := product.Stock + movement.Quantity
+	if stock < 0 {
+		return ErrInsufficientStock
+	}
+
+	if movement.Type == models.StockPurchase {
+		product.Cost = (int64(product.Stock)*product.Cost + int64(movement.Quantity)*movement.UnitCost) / int64(stock)
+	} else {
+		movement.UnitCost = product.Cost
+	}
+	product.Stock = stock
+	if !product.LowStock() {
+		product.LowStockAlertedAt = nil
+	}
+	movement.StockAfter = stock
+	return nil
+}
+
+// equalTime 比較兩個可為空的時間
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+)
+
+func TestApplyMovement(t *testing.T) {
+	alertedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		product       models.Product
+		movement      models.StockMovement
+		wantErr       error
+		wantStock     int
+		wantCost      int64
+		wantUnitCost  int64
+		wantAlertKept bool
+	}{
+		{
+			name:         "進貨以移動平均計算成本",
+			product:      models.Product{Stock: 10, Cost: 100},
+			movement:     models.StockMovement{Type: models.StockPurchase, Quantity: 30, UnitCost: 140},
+			wantStock:    40,
+			wantCost:     130, // (10×100 + 30×140) / 40
+			wantUnitCost: 140,
+		},
+		{
+			name:         "平均成本不足一元的部分捨去",
+			product:      models.Product{Stock: 2, Cost: 100},
+			movement:     models.StockMovement{Type: models.StockPurchase, Quantity: 1, UnitCost: 101},
+			wantStock:    3,
+			wantCost:     100, // 301 / 3
+			wantUnitCost: 101,
+		},
+		{
+			name:         "沒有庫存時的進貨以進貨單價為成本",
+			product:      models.Product{Stock: 0, Cost: 80},
+			movement:     models.StockMovement{Type: models.StockPurchase, Quantity: 5, UnitCost: 120},
+			wantStock:    5,
+			wantCost:     120,
+			wantUnitCost: 120,
+		},
+		{
+			name:         "盤點調整沿用平均成本",
+			product:      models.Product{Stock: 10, Cost: 130},
+			movement:     models.StockMovement{Type: models.StockAdjustment, Quantity: -2, UnitCost: 999},
+			wantStock:    8,
+			wantCost:     130,
+			wantUnitCost: 130,
+		},
+		{
+			name:         "售出沿用平均成本",
+			product:      models.Product{Stock: 10, Cost: 130},
+			movement:     models.StockMovement{Type: models.StockSale, Quantity: -10},
+			wantStock:    0,
+			wantCost:     130,
+			wantUnitCost: 130,
+		},
+		{
+			name:     "庫存不可為負",
+			product:  models.Product{Stock: 3, Cost: 130},
+			movement: models.StockMovement{Type: models.StockSale, Quantity: -4},
+			wantErr:  ErrInsufficientStock,
+		},
+		{
+			name:     "盤點調整也不可使庫存為負",
+			product:  models.Product{Stock: 0, Cost: 130},
+			movement: models.StockMovement{Type: models.StockAdjustment, Quantity: -1},
+			wantErr:  ErrInsufficientStock,
+		},
+		{
+			name:         "進貨超過補貨點時清除低庫存通知紀錄",
+			product:      models.Product{Stock: 2, Cost: 100, ReorderLevel: 5, LowStockAlertedAt: &alertedAt},
+			movement:     models.StockMovement{Type: models.StockPurchase, Quantity: 4, UnitCost: 100},
+			wantStock:    6,
+			wantCost:     100,
+			wantUnitCost: 100,
+		},
+		{
+			name:          "仍在補貨點時保留低庫存通知紀錄",
+			product:       models.Product{Stock: 2, Cost: 100, ReorderLevel: 5, LowStockAlertedAt: &alertedAt},
+			movement:      models.StockMovement{Type: models.StockPurchase, Quantity: 3, UnitCost: 100},
+			wantStock:     5,
+			wantCost:      100,
+			wantUnitCost:  100,
+			wantAlertKept: true,
+		},
+		{
+			name:          "售出後仍低於補貨點時保留低庫存通知紀錄",
+			product:       models.Product{Stock: 4, Cost: 100, ReorderLevel: 5, LowStockAlertedAt: &alertedAt},
+			movement:      models.StockMovement{Type: models.StockSale, Quantity: -1},
+			wantStock:     3,
+			wantCost:      100,
+			wantUnitCost:  100,
+			wantAlertKept: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := tt.product
+			movement := tt.movement
+			err := applyMovement(&product, &movement)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("applyMovement error = %v, want %v", err, tt.wantErr)
+				}
+				if product.Stock != tt.product.Stock || product.Cost != tt.product.Cost {
+					t.Errorf("product changed after rejection: stock %d cost %d", product.Stock, product.Cost)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyMovement error = %v", err)
+			}
+			if product.Stock != tt.wantStock || product.Cost != tt.wantCost {
+				t.Errorf("Stock = %d, Cost = %d, want %d, %d", product.Stock, product.Cost, tt.wantStock, tt.wantCost)
+			}
+			if movement.StockAfter != tt.wantStock || movement.UnitCost != tt.wantUnitCost {
+				t.Errorf("StockAfter = %d, UnitCost = %d, want %d, %d", movement.StockAfter, movement.UnitCost, tt.wantStock, tt.wantUnitCost)
+			}
+			if kept := product.LowStockAlertedAt != nil; kept != tt.wantAlertKept {
+				t.Errorf("LowStockAlertedAt kept = %v, want %v", kept, tt.wantAlertKept)
+			}
+		})
+	}
+}
+
+func TestCreateVisitDeductsStockAtomically(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	visitRepo := NewVisitRepository(db)
+
+	serum := models.Product{SKU: "SERUM-30", Name: "精華液", Price: 1290, Cost: 600, Stock: 5, Active: true}
+	mask := models.Product{SKU: "MASK-5", Name: "面膜", Price: 350, Cost: 120, Stock: 1, Active: true}
+	for _, product := range []*models.Product{&serum, &mask} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("建立商品失敗: %v", err)
+		}
+	}
+
+	newVisit := func(maskQuantity int) *models.Visit {
+		return &models.Visit{
+			CustomerName: "王小明",
+			VisitDate:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			Total:        1290*2 + 350*int64(maskQuantity),
+			Items: []models.VisitItem{
+				{Kind: models.VisitItemRetail, Name: serum.Name, ProductID: &serum.ID, Quantity: 2, Price: 1290 * 2},
+				{Kind: models.VisitItemRetail, Name: mask.Name, ProductID: &mask.ID, Quantity: maskQuantity, Price: 350 * int64(maskQuantity)},
+			},
+		}
+	}
+	stock := func(id uint) int {
+		var product models.Product
+		if err := db.First(&product, id).Error; err != nil {
+			t.Fatalf("查詢商品失敗: %v", err)
+		}
+		return product.Stock
+	}
+
+	// 面膜庫存不足時，已扣減的精華液一併還原
+	if err := visitRepo.CreateVisit(ctx, newVisit(2)); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("CreateVisit error = %v, want ErrInsufficientStock", err)
+	}
+	if got := stock(serum.ID); got != 5 {
+		t.Errorf("serum stock = %d after rollback, want 5", got)
+	}
+	var movements int64
+	db.Model(&models.StockMovement{}).Count(&movements)
+	if movements != 0 {
+		t.Errorf("stock movements = %d after rollback, want 0", movements)
+	}
+
+	visit := newVisit(1)
+	if err := visitRepo.CreateVisit(ctx, visit); err != nil {
+		t.Fatalf("CreateVisit error = %v", err)
+	}
+	if serumStock, maskStock := stock(serum.ID), stock(mask.ID); serumStock != 3 || maskStock != 0 {
+		t.Errorf("stock = %d, %d, want 3, 0", serumStock, maskStock)
+	}
+
+	var sales []models.StockMovement
+	db.Where("visit_id = ?", visit.ID).Order("product_id").Find(&sales)
+	if len(sales) != 2 {
+		t.Fatalf("sale movements = %d, want 2", len(sales))
+	}
+	if sales[0].Type != models.StockSale || sales[0].Quantity != -2 || sales[0].StockAfter != 3 || sales[0].UnitCost != 600 {
+		t.Errorf("serum movement = %+v", sales[0])
+	}
+	if sales[1].Quantity != -1 || sales[1].StockAfter != 0 || sales[1].UnitCost != 120 {
+		t.Errorf("mask movement = %+v", sales[1])
+	}
+}
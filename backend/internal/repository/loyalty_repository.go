@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// ErrInsufficientPoints 點數餘額不足
+var ErrInsufficientPoints = errors.New("點數餘額不足")
+
+// expiringPointsQuery 計算各帳戶在 cutoff 之前取得、尚未被使用或失效的點數
+// 兌換、失效與扣除的點數依先進先出視為先使用最早取得的點數，因此只要比較總量即可
+const expiringPointsQuery = `
+	SELECT account_id,
+		SUM(CASE WHEN points > 0 AND created_at < @cutoff THEN points ELSE 0 END)
+			- SUM(CASE WHEN points < 0 THEN -points ELSE 0 END) AS points
+	FROM points_ledger
+	WHERE account_id IN (@accounts)
+	GROUP BY account_id`
+
+// expiringPoints expiringPointsQuery 的查詢結果
+type expiringPoints struct {
+	AccountID uint
+	Points    int64
+}
+
+// LoyaltyRepository 提供會員點數的存取方法
+// 餘額只在交易內鎖定帳戶後變更，並同時寫入帳本
+type LoyaltyRepository struct {
+	db *gorm.DB
+}
+
+// NewLoyaltyRepository 創建一個新的會員點數資料存取層
+func NewLoyaltyRepository(db *gorm.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{
+		db: db,
+	}
+}
+
+// GetAccount 查找客戶的點數帳戶，不存在時返回 nil
+func (r *LoyaltyRepository) GetAccount(ctx context.Context, customerName string) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+
+	result := r.db.WithContext(ctx).Where("customer_name = ?", customerName).First(&account)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢點數帳戶失敗: %w", result.Error)
+	}
+
+	return &account, nil
+}
+
+// ListLedger 列出帳戶的點數異動，新的在前
+func (r *LoyaltyRepository) ListLedger(ctx context.Context, accountID uint) ([]models.PointsLedgerEntry, error) {
+	var entries []models.PointsLedgerEntry
+	if err := r.db.WithContext(ctx).Where("account_id = ?", accountID).Order("id DESC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("查詢點數帳本失敗: %w", err)
+	}
+	return entries, nil
+}
+
+// Adjust 人工調整客戶點數，扣除後餘額不足時返回 ErrInsufficientPoints
+func (r *LoyaltyRepository) Adjust(ctx context.Context, customerName string, points int64, note, createdBy string) (*models.LoyaltyAccount, error) {
+	var account *models.LoyaltyAccount
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		account, err = changePoints(tx, customerName, models.PointsAdjust, points, nil, note, createdBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ExpirePoints 將 cutoff 之前取得且尚未使用的點數記為失效，返回處理的帳戶數
+func (r *LoyaltyRepository) ExpirePoints(ctx context.Context, cutoff time.Time) (int, error) {
+	var accountIDs []uint
+	err := r.db.WithContext(ctx).Model(&models.LoyaltyAccount{}).Where("balance > 0").Pluck("id", &accountIDs).Error
+	if err != nil {
+		return 0, fmt.Errorf("查詢點數帳戶失敗: %w", err)
+	}
+	if len(accountIDs) == 0 {
+		return 0, nil
+	}
+
+	var due []expiringPoints
+	err = r.db.WithContext(ctx).
+		Raw(expiringPointsQuery, map[string]any{"cutoff": cutoff, "accounts": accountIDs}).
+		Scan(&due).Error
+	if err != nil {
+		return 0, fmt.Errorf("計算到期點數失敗: %w", err)
+	}
+
+	count := 0
+	for _, item := range due {
+		if item.Points <= 0 {
+			continue
+		}
+		// 每個帳戶各自一個交易，鎖定後重新計算，避免與同時進行的兌換衝突
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var account models.LoyaltyAccount
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, item.AccountID).Error; err != nil {
+				return fmt.Errorf("查詢點數帳戶失敗: %w", err)
+			}
+
+			var current []expiringPoints
+			err := tx.Raw(expiringPointsQuery, map[string]any{"cutoff": cutoff, "accounts": []uint{account.ID}}).Scan(&current).Error
+			if err != nil {
+				return fmt.Errorf("計算到期點數失敗: %w", err)
+			}
+			if len(current) == 0 || current[0].Points <= 0 {
+				return nil
+			}
+
+			expired := min(current[0].Points, account.Balance)
+			_, err = changePoints(tx, account.CustomerName, models.PointsExpire, -expired, nil, "", "")
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// postPoints 在新增消費紀錄的交易內先扣除折抵的點數，再累積本次取得的點數，visit 必須已寫入
+func postPoints(tx *gorm.DB, visit *models.Visit) error {
+	if visit.PointsRedeemed > 0 {
+		_, err := changePoints(tx, visit.CustomerName, models.PointsRedeem, -visit.PointsRedeemed, &visit.ID, "", visit.CreatedBy)
+		if err != nil {
+			return err
+		}
+	}
+	if visit.PointsEarned > 0 {
+		_, err := changePoints(tx, visit.CustomerName, models.PointsEarn, visit.PointsEarned, &visit.ID, "", visit.CreatedBy)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changePoints 鎖定客戶的點數帳戶並寫入一筆異動，增加點數時帳戶不存在會自動建立
+func changePoints(tx *gorm.DB, customerName, entryType string, points int64, visitID *uint, note, createdBy string) (*models.LoyaltyAccount, error) {
+	if points > 0 {
+		placeholder := models.LoyaltyAccount{CustomerName: customerName}
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "customer_name"}}, DoNothing: true}).
+			Create(&placeholder).Error
+		if err != nil {
+			return nil, fmt.Errorf("建立點數帳戶失敗: %w", err)
+		}
+	}
+
+	var account models.LoyaltyAccount
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("customer_name = ?", customerName).First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInsufficientPoints
+		}
+		return nil, fmt.Errorf("查詢點數帳戶失敗: %w", err)
+	}
+
+	if account.Balance+points < 0 {
+		return nil, ErrInsufficientPoints
+	}
+	account.Balance += points
+	if err := tx.Model(&account).Update("balance", account.Balance).Error; err != nil {
+		return nil, fmt.Errorf("更新點數帳戶失敗: %w", err)
+	}
+
+	entry := models.PointsLedgerEntry{
+		AccountID: account.ID,
+		Type:      entryType,
+		Points:    points,
+		Balance:   account.Balance,
+		VisitID:   visitID,
+		Note:      note,
+		CreatedBy: createdBy,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return nil, fmt.Errorf("寫入點數帳本失敗: %w", err)
+	}
+	return &account, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// ErrOutboxNotDead 只有標記為 dead 的通知可以重新發送
+var ErrOutboxNotDead = errors.New("通知不是發送失敗狀態")
+
+// OutboxFilter 通知佇列查詢條件，零值欄位代表不篩選
+type OutboxFilter struct {
+	Status  string
+	Channel string
+	Kind    string
+	Limit   int
+}
+
+// OutboxRepository 提供通知佇列的存取方法
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository 創建一個新的通知佇列資料存取層
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// Enqueue 將通知加入佇列
+func (r *OutboxRepository) Enqueue(ctx context.Context, msg *models.OutboxMessage) error {
+	return enqueue(r.db.WithContext(ctx), msg)
+}
+
+// ClaimDue 取出最多 limit 則到期的待發送通知，並將其下次發送時間延後 lease
+// 使用 SKIP LOCKED 讓多個執行個體不會取得同一則通知；
+// 發送途中程序中止的通知會在 lease 過後重新取出，因此同一則通知可能送出不只一次
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil {
+			return fmt.Errorf("查詢待發送通知失敗: %w", err)
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(messages))
+		for i, msg := range messages {
+			ids[i] = msg.ID
+		}
+		err = tx.Model(&models.OutboxMessage{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return fmt.Errorf("更新通知發送時間失敗: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkSent 記錄通知已送出
+func (r *OutboxRepository) MarkSent(ctx context.Context, id uint) error {
+	return r.update(ctx, id, map[string]any{
+		"status":     models.OutboxSent,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": "",
+		"sent_at":    time.Now(),
+	})
+}
+
+// MarkRetry 記錄一次發送失敗，並安排於 next 重試
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id uint, errMessage string, next time.Time) error {
+	return r.update(ctx, id, map[string]any{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      errMessage,
+		"next_attempt_at": next,
+	})
+}
+
+// MarkDead 記錄一次發送失敗，並停止重試
+func (r *OutboxRepository) MarkDead(ctx context.Context, id uint, errMessage string) error {
+	return r.update(ctx, id, map[string]any{
+		"status":     models.OutboxDead,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": errMessage,
+	})
+}
+
+// Retry 將發送失敗 (dead) 的通知重新排入佇列並重置重試次數
+// 通知不存在時返回 nil，不是 dead 狀態時返回 ErrOutboxNotDead
+func (r *OutboxRepository) Retry(ctx context.Context, id uint) (*models.OutboxMessage, error) {
+	var msg models.OutboxMessage
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&msg, id).Error
+		if err != nil {
+			return err
+		}
+		if msg.Status != models.OutboxDead {
+			return ErrOutboxNotDead
+		}
+
+		err = tx.Model(&msg).Updates(map[string]any{
+			"status":          models.OutboxPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("更新通知失敗: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if errors.Is(err, ErrOutboxNotDead) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("重新發送通知失敗: %w", err)
+	}
+	return &msg, nil
+}
+
+// List 依條件查詢通知，新的在前
+func (r *OutboxRepository) List(ctx context.Context, filter OutboxFilter) ([]models.OutboxMessage, error) {
+	query := r.db.WithContext(ctx)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var messages []models.OutboxMessage
+	if err := query.Order("updated_at DESC, id DESC").Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("查詢通知失敗: %w", err)
+	}
+	return messages, nil
+}
+
+// update 更新一則通知的發送狀態
+func (r *OutboxRepository) update(ctx context.Context, id uint, updates map[string]any) error {
+	if err := r.db.WithContext(ctx).Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新通知失敗: %w", err)
+	}
+	return nil
+}
+
+// enqueue 在 tx 內將通知加入佇列，讓通知與產生它的業務資料同時寫入或同時放棄
+func enqueue(tx *gorm.DB, msg *models.OutboxMessage) error {
+	msg.Status = models.OutboxPending
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = time.Now()
+	}
+	if err := tx.Create(msg).Error; err != nil {
+		return fmt.Errorf("新增通知失敗: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+var (
+	// ErrPackageUnavailable 套票不存在、不屬於該客戶、已用完或已到期
+	ErrPackageUnavailable = errors.New("套票無法使用")
+	// ErrInsufficientBalance 儲值金餘額不足或已到期
+	ErrInsufficientBalance = errors.New("儲值金餘額不足")
+)
+
+// PrepaidRepository 提供課程套票與儲值金的存取方法
+// 剩餘堂數與餘額只在交易內鎖定該列後變更，並同時寫入帳本
+type PrepaidRepository struct {
+	db *gorm.DB
+}
+
+// NewPrepaidRepository 創建一個新的套票與儲值金資料存取層
+func NewPrepaidRepository(db *gorm.DB) *PrepaidRepository {
+	return &PrepaidRepository{
+		db: db,
+	}
+}
+
+// ListPackages 列出套票，customerName 為空時列出全部，新的在前
+func (r *PrepaidRepository) ListPackages(ctx context.Context, customerName string) ([]models.Package, error) {
+	query := r.db.WithContext(ctx)
+	if customerName != "" {
+		query = query.Where("customer_name = ?", customerName)
+	}
+
+	var packages []models.Package
+	if err := query.Order("purchased_at DESC, id DESC").Find(&packages).Error; err != nil {
+		return nil, fmt.Errorf("查詢套票失敗: %w", err)
+	}
+	return packages, nil
+}
+
+// GetPackageByID 透過 ID 查找套票，不存在時返回 nil
+func (r *PrepaidRepository) GetPackageByID(ctx context.Context, id uint) (*models.Package, error) {
+	var pkg models.Package
+
+	result := r.db.WithContext(ctx).First(&pkg, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢套票失敗: %w", result.Error)
+	}
+
+	return &pkg, nil
+}
+
+// CreatePackage 新增套票並寫入購買紀錄
+func (r *PrepaidRepository) CreatePackage(ctx context.Context, pkg *models.Package) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pkg).Error; err != nil {
+			return fmt.Errorf("新增套票失敗: %w", err)
+		}
+
+		entry := models.PackageLedgerEntry{
+			PackageID: pkg.ID,
+			Type:      models.LedgerPurchase,
+			Sessions:  pkg.TotalSessions,
+			Remaining: pkg.RemainingSessions,
+			CreatedBy: pkg.CreatedBy,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("寫入套票帳本失敗: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListPackageLedger 列出套票的堂數異動，依時間排序
+func (r *PrepaidRepository) ListPackageLedger(ctx context.Context, packageID uint) ([]models.PackageLedgerEntry, error) {
+	var entries []models.PackageLedgerEntry
+	if err := r.db.WithContext(ctx).Where("package_id = ?", packageID).Order("id").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("查詢套票帳本失敗: %w", err)
+	}
+	return entries, nil
+}
+
+// GetWalletByID 透過 ID 查找儲值金帳戶，不存在時返回 nil
+func (r *PrepaidRepository) GetWalletByID(ctx context.Context, id uint) (*models.Wallet, error) {
+	var wallet models.Wallet
+
+	result := r.db.WithContext(ctx).First(&wallet, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢儲值金失敗: %w", result.Error)
+	}
+
+	return &wallet, nil
+}
+
+// GetWalletByCustomer 查找客戶的儲值金帳戶，不存在時返回 nil
+func (r *PrepaidRepository) GetWalletByCustomer(ctx context.Context, customerName string) (*models.Wallet, error) {
+	var wallet models.Wallet
+
+	result := r.db.WithContext(ctx).Where("customer_name = ?", customerName).First(&wallet)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢儲值金失敗: %w", result.Error)
+	}
+
+	return &wallet, nil
+}
+
+// TopUpWallet 為客戶儲值，帳戶不存在時自動建立
+// expiresAt 不為 nil 時將帳戶到期日更新為該時間 (整個餘額的期限隨最後一次儲值延長)
+func (r *PrepaidRepository) TopUpWallet(ctx context.Context, customerName string, amount int64, expiresAt *time.Time, note, createdBy string) (*models.Wallet, error) {
+	var wallet models.Wallet
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先確保帳戶存在，再鎖定該列，避免同時儲值時重複建立帳戶
+		placeholder := models.Wallet{CustomerName: customerName}
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "customer_name"}}, DoNothing: true}).
+			Create(&placeholder).Error
+		if err != nil {
+			return fmt.Errorf("建立儲值金帳戶失敗: %w", err)
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("customer_name = ?", customerName).First(&wallet).Error
+		if err != nil {
+			return fmt.Errorf("查詢儲值金失敗: %w", err)
+		}
+
+		wallet.Balance += amount
+		if expiresAt != nil {
+			wallet.ExpiresAt = expiresAt
+		}
+		if err := tx.Model(&wallet).Select("balance", "expires_at").Updates(&wallet).Error; err != nil {
+			return fmt.Errorf("更新儲值金失敗: %w", err)
+		}
+
+		entry := models.WalletLedgerEntry{
+			WalletID:  wallet.ID,
+			Type:      models.LedgerTopUp,
+			Amount:    amount,
+			Balance:   wallet.Balance,
+			Note:      note,
+			CreatedBy: createdBy,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("寫入儲值金帳本失敗: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+// ListWalletLedger 列出儲值金的異動，依時間排序
+func (r *PrepaidRepository) ListWalletLedger(ctx context.Context, walletID uint) ([]models.WalletLedgerEntry, error) {
+	var entries []models.WalletLedgerEntry
+	if err := r.db.WithContext(ctx).Where("wallet_id = ?", walletID).Order("id").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("查詢儲值金帳本失敗: %w", err)
+	}
+	return entries, nil
+}
+
+// ExpirePackages 將到期的套票剩餘堂數歸零並標記為已到期，返回處理的套票數
+func (r *PrepaidRepository) ExpirePackages(ctx context.Context, now time.Time) (int, error) {
+	count := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var packages []models.Package
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at <= ?", models.PackageStatusActive, now).
+			Find(&packages).Error
+		if err != nil {
+			return fmt.Errorf("查詢到期套票失敗: %w", err)
+		}
+
+		for i := range packages {
+			pkg := &packages[i]
+			entry := models.PackageLedgerEntry{
+				PackageID: pkg.ID,
+				Type:      models.LedgerExpire,
+				Sessions:  -pkg.RemainingSessions,
+				Remaining: 0,
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return fmt.Errorf("寫入套票帳本失敗: %w", err)
+			}
+
+			err := tx.Model(pkg).Updates(map[string]any{
+				"remaining_sessions": 0,
+				"status":             models.PackageStatusExpired,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("更新套票失敗: %w", err)
+			}
+		}
+
+		count = len(packages)
+		return nil
+	})
+
+	return count, err
+}
+
+// ExpireWallets 將到期的儲值金餘額歸零，返回處理的帳戶數
+func (r *PrepaidRepository) ExpireWallets(ctx context.Context, now time.Time) (int, error) {
+	count := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallets []models.Wallet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("balance > 0 AND expires_at <= ?", now).
+			Find(&wallets).Error
+		if err != nil {
+			return fmt.Errorf("查詢到期儲值金失敗: %w", err)
+		}
+
+		for i := range wallets {
+			wallet := &wallets[i]
+			entry := models.WalletLedgerEntry{
+				WalletID: wallet.ID,
+				Type:     models.LedgerExpire,
+				Amount:   -wallet.Balance,
+				Balance:  0,
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return fmt.Errorf("寫入儲值金帳本失敗: %w", err)
+			}
+
+			if err := tx.Model(wallet).Update("balance", 0).Error; err != nil {
+				return fmt.Errorf("更新儲值金失敗: %w", err)
+			}
+		}
+
+		count = len(wallets)
+		return nil
+	})
+
+	return count, err
+}
+
+// redeemPrepaid 在新增消費紀錄的交易內扣抵套票堂數與儲值金，visit 必須已寫入
+func redeemPrepaid(tx *gorm.DB, visit *models.Visit) error {
+	for _, item := range visit.Items {
+		if item.PackageID == nil {
+			continue
+		}
+		if err := redeemPackageSession(tx, *item.PackageID, visit); err != nil {
+			return err
+		}
+	}
+
+	if visit.WalletPaid > 0 {
+		return debitWallet(tx, visit)
+	}
+	return nil
+}
+
+// redeemPackageSession 扣抵套票一堂
+func redeemPackageSession(tx *gorm.DB, packageID uint, visit *models.Visit) error {
+	var pkg models.Package
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&pkg, packageID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPackageUnavailable
+		}
+		return fmt.Errorf("查詢套票失敗: %w", err)
+	}
+
+	if !packageUsable(&pkg, visit.CustomerName, time.Now()) {
+		return ErrPackageUnavailable
+	}
+
+	pkg.RemainingSessions--
+	if pkg.RemainingSessions == 0 {
+		pkg.Status = models.PackageStatusExhausted
+	}
+	if err := tx.Model(&pkg).Select("remaining_sessions", "status").Updates(&pkg).Error; err != nil {
+		return fmt.Errorf("更新套票失敗: %w", err)
+	}
+
+	entry := models.PackageLedgerEntry{
+		PackageID: pkg.ID,
+		Type:      models.LedgerRedeem,
+		Sessions:  -1,
+		Remaining: pkg.RemainingSessions,
+		VisitID:   &visit.ID,
+		CreatedBy: visit.CreatedBy,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("寫入套票帳本失敗: %w", err)
+	}
+	return nil
+}
+
+// packageUsable 套票是否屬於該客戶、仍在使用中、尚有剩餘堂數且未到期
+func packageUsable(pkg *models.Package, customerName string, now time.Time) bool {
+	return pkg.CustomerName == customerName && pkg.Status == models.PackageStatusActive &&
+		pkg.RemainingSessions > 0 && (pkg.ExpiresAt == nil || pkg.ExpiresAt.After(now))
+}
+
+// debitWallet 以儲值金支付消費紀錄的 WalletPaid 金額
+func debitWallet(tx *gorm.DB, visit *models.Visit) error {
+	var wallet models.Wallet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("customer_name = ?", visit.CustomerName).First(&wallet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInsufficientBalance
+		}
+		return fmt.Errorf("查詢儲值金失敗: %w", err)
+	}
+
+	if wallet.Balance < visit.WalletPaid || (wallet.ExpiresAt != nil && !wallet.ExpiresAt.After(time.Now())) {
+		return ErrInsufficientBalance
+	}
+
+	wallet.Balance -= visit.WalletPaid
+	if err := tx.Model(&wallet).Update("balance", wallet.Balance).Error; err != nil {
+		return fmt.Errorf("更新儲值金失敗: %w", err)
+	}
+
+	entry := models.WalletLedgerEntry{
+		WalletID:  wallet.ID,
+		Type:      models.LedgerRedeem,
+		Amount:    -visit.WalletPaid,
+		Balance:   wallet.Balance,
+		VisitID:   &visit.ID,
+		CreatedBy: visit.CreatedBy,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("寫入儲值金帳本失敗: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+)
+
+func TestPackageUsable(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	usable := models.Package{CustomerName: "王小明", Status: models.PackageStatusActive, RemainingSessions: 3}
+
+	tests := []struct {
+		name     string
+		modify   func(pkg *models.Package)
+		customer string
+		want     bool
+	}{
+		{"可使用", func(pkg *models.Package) {}, "王小明", true},
+		{"尚未到期", func(pkg *models.Package) { pkg.ExpiresAt = &future }, "王小明", true},
+		{"最後一堂", func(pkg *models.Package) { pkg.RemainingSessions = 1 }, "王小明", true},
+		{"屬於其他客戶", func(pkg *models.Package) {}, "陳美美", false},
+		{"已用完", func(pkg *models.Package) { pkg.RemainingSessions = 0 }, "王小明", false},
+		{"狀態不是使用中", func(pkg *models.Package) { pkg.Status = models.PackageStatusExhausted }, "王小明", false},
+		{"已到期", func(pkg *models.Package) { pkg.ExpiresAt = &past }, "王小明", false},
+		{"到期時間剛好是現在", func(pkg *models.Package) { pkg.ExpiresAt = &now }, "王小明", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := usable
+			tt.modify(&pkg)
+			if got := packageUsable(&pkg, tt.customer, now); got != tt.want {
+				t.Errorf("packageUsable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateVisitRedeemsPrepaidAtomically(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	visitRepo := NewVisitRepository(db)
+
+	tests := []struct {
+		name          string
+		customer      string
+		walletPaid    int64
+		wantErr       error
+		wantRemaining int
+		wantBalance   int64
+	}{
+		{"儲值金不足時套票扣抵一併還原", "王小明", 500, ErrInsufficientBalance, 5, 300},
+		{"儲值金足夠時同時扣抵", "陳美美", 300, nil, 4, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := tt.customer
+			pkg := models.Package{
+				CustomerName:      customer,
+				Name:              "臉部保養 5 堂",
+				TotalSessions:     5,
+				RemainingSessions: 5,
+				Price:             9000,
+				Status:            models.PackageStatusActive,
+				PurchasedAt:       time.Now(),
+			}
+			if err := db.Create(&pkg).Error; err != nil {
+				t.Fatalf("建立套票失敗: %v", err)
+			}
+			wallet := models.Wallet{CustomerName: customer, Balance: 300}
+			if err := db.Create(&wallet).Error; err != nil {
+				t.Fatalf("建立儲值金失敗: %v", err)
+			}
+
+			visit := &models.Visit{
+				CustomerName: customer,
+				VisitDate:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+				Total:        300,
+				WalletPaid:   tt.walletPaid,
+				Items: []models.VisitItem{
+					{Kind: models.VisitItemService, Name: "臉部保養", PackageID: &pkg.ID, ListPrice: 1800, Quantity: 1},
+					{Kind: models.VisitItemExtra, Name: "眼周護理", Price: 300, Quantity: 1},
+				},
+			}
+			err := visitRepo.CreateVisit(ctx, visit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateVisit error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err := db.First(&pkg, pkg.ID).Error; err != nil {
+				t.Fatalf("查詢套票失敗: %v", err)
+			}
+			if err := db.First(&wallet, wallet.ID).Error; err != nil {
+				t.Fatalf("查詢儲值金失敗: %v", err)
+			}
+			if pkg.RemainingSessions != tt.wantRemaining || wallet.Balance != tt.wantBalance {
+				t.Errorf("RemainingSessions = %d, Balance = %d, want %d, %d",
+					pkg.RemainingSessions, wallet.Balance, tt.wantRemaining, tt.wantBalance)
+			}
+
+			var visits, packageEntries int64
+			db.Model(&models.Visit{}).Where("customer_name = ?", customer).Count(&visits)
+			db.Model(&models.PackageLedgerEntry{}).Where("package_id = ?", pkg.ID).Count(&packageEntries)
+			wantRows := int64(0)
+			if tt.wantErr == nil {
+				wantRows = 1
+			}
+			if visits != wantRows || packageEntries != wantRows {
+				t.Errorf("visits = %d, package ledger entries = %d, want %d", visits, packageEntries, wantRows)
+			}
+		})
+	}
+}
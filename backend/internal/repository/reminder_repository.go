@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// ReminderFilter 提醒紀錄查詢條件，零值欄位代表不篩選
+type ReminderFilter struct {
+	Kind   string
+	Status string
+	Limit  int
+}
+
+// ReminderRepository 提供提醒紀錄的存取方法
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository 創建一個新的提醒資料存取層
+func NewReminderRepository(db *gorm.DB) *ReminderRepository {
+	return &ReminderRepository{
+		db: db,
+	}
+}
+
+// errAlreadyReminded 同一期間已提醒過，用於放棄 Queue 的交易
+var errAlreadyReminded = errors.New("同一期間已提醒過")
+
+// Queue 記錄一則提醒並在同一交易內將通知 msg 加入通知佇列，msg 為 nil 時記為 skipped 不發送
+// 同一期間已有紀錄時返回 false 且不加入佇列；先前因缺少聯絡方式而 skipped 的紀錄可在之後改為 queued
+func (r *ReminderRepository) Queue(ctx context.Context, reminder *models.Reminder, msg *models.OutboxMessage) (bool, error) {
+	reminder.Status = models.ReminderSkipped
+	if msg != nil {
+		reminder.Status = models.ReminderQueued
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if msg != nil {
+			if err := enqueue(tx, msg); err != nil {
+				return err
+			}
+			reminder.OutboxID = &msg.ID
+		}
+
+		onConflict := clause.OnConflict{DoNothing: true}
+		if msg != nil {
+			onConflict = clause.OnConflict{
+				Columns:   []clause.Column{{Name: "customer_name"}, {Name: "kind"}, {Name: "period_key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"channel", "status", "error", "outbox_id", "updated_at"}),
+				Where: clause.Where{Exprs: []clause.Expression{
+					clause.Eq{Column: clause.Column{Table: "reminders", Name: "status"}, Value: models.ReminderSkipped},
+				}},
+			}
+		}
+
+		result := tx.Clauses(onConflict).Create(reminder)
+		if result.Error != nil {
+			return fmt.Errorf("新增提醒紀錄失敗: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyReminded
+		}
+		return nil
+	})
+	if errors.Is(err, errAlreadyReminded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List 依條件查詢提醒紀錄，新的在前
+func (r *ReminderRepository) List(ctx context.Context, filter ReminderFilter) ([]models.Reminder, error) {
+	query := r.db.WithContext(ctx).Preload("Delivery")
+
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var reminders []models.Reminder
+	if err := query.Order("updated_at DESC, id DESC").Find(&reminders).Error; err != nil {
+		return nil, fmt.Errorf("查詢提醒紀錄失敗: %w", err)
+	}
+	return reminders, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/models"
+	"backend/pkg/notify"
+)
+
+func TestQueueUpgradesSkippedReminder(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewReminderRepository(db)
+
+	newReminder := func() *models.Reminder {
+		return &models.Reminder{
+			CustomerName: "王小明",
+			Kind:         models.ReminderBirthday,
+			PeriodKey:    "2024",
+			Channel:      notify.ChannelSMS,
+		}
+	}
+	newMessage := func() *models.OutboxMessage {
+		return &models.OutboxMessage{
+			Kind:      models.OutboxKindReminder,
+			Channel:   notify.ChannelSMS,
+			Recipient: notify.Recipient{Name: "王小明", Phone: "0912345678"},
+			Body:      "生日快樂",
+		}
+	}
+	countMessages := func() int64 {
+		var count int64
+		db.Model(&models.OutboxMessage{}).Count(&count)
+		return count
+	}
+
+	// 缺少聯絡方式時記為 skipped
+	skipped := newReminder()
+	queued, err := repo.Queue(ctx, skipped, nil)
+	if err != nil || !queued {
+		t.Fatalf("Queue(skipped) = %v, %v, want true", queued, err)
+	}
+
+	// 仍缺少聯絡方式時不重複記錄
+	queued, err = repo.Queue(ctx, newReminder(), nil)
+	if err != nil || queued {
+		t.Fatalf("Queue(skipped again) = %v, %v, want false", queued, err)
+	}
+
+	// 補上聯絡方式後同一期間改為 queued 並加入佇列
+	reminder := newReminder()
+	msg := newMessage()
+	queued, err = repo.Queue(ctx, reminder, msg)
+	if err != nil || !queued {
+		t.Fatalf("Queue(after skipped) = %v, %v, want true", queued, err)
+	}
+
+	var stored models.Reminder
+	if err := db.First(&stored, skipped.ID).Error; err != nil {
+		t.Fatalf("查詢提醒紀錄失敗: %v", err)
+	}
+	if stored.Status != models.ReminderQueued || stored.OutboxID == nil || *stored.OutboxID != msg.ID {
+		t.Errorf("reminder = %s outbox %v, want queued outbox %d", stored.Status, stored.OutboxID, msg.ID)
+	}
+	var total int64
+	db.Model(&models.Reminder{}).Count(&total)
+	if total != 1 {
+		t.Errorf("reminders = %d, want 1", total)
+	}
+
+	// 已加入佇列的提醒不會再次發送，本次的通知一併還原
+	queued, err = repo.Queue(ctx, newReminder(), newMessage())
+	if err != nil || queued {
+		t.Fatalf("Queue(already queued) = %v, %v, want false", queued, err)
+	}
+	if got := countMessages(); got != 1 {
+		t.Errorf("outbox messages = %d, want 1", got)
+	}
+
+	// 已加入佇列的提醒不會被改回 skipped
+	if _, err := repo.Queue(ctx, newReminder(), nil); err != nil {
+		t.Fatalf("Queue(skipped after queued) error = %v", err)
+	}
+	if err := db.First(&stored, skipped.ID).Error; err != nil {
+		t.Fatalf("查詢提醒紀錄失敗: %v", err)
+	}
+	if stored.Status != models.ReminderQueued {
+		t.Errorf("Status = %s, want queued", stored.Status)
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// WeeklySchedule 員工每週固定的上班時段與休息時間
+type WeeklySchedule struct {
+	WorkingHours []models.StaffWorkingHours `json:"working_hours"`
+	Breaks       []models.StaffBreak        `json:"breaks"`
+}
+
+// ScheduleRepository 提供員工排班、休假與店休日的存取方法
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository 創建一個新的排班資料存取層
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{
+		db: db,
+	}
+}
+
+// GetWeeklySchedule 取得員工的每週排班
+func (r *ScheduleRepository) GetWeeklySchedule(ctx context.Context, staffID uint) (*WeeklySchedule, error) {
+	schedule := &WeeklySchedule{
+		WorkingHours: []models.StaffWorkingHours{},
+		Breaks:       []models.StaffBreak{},
+	}
+
+	db := r.db.WithContext(ctx)
+	if err := db.Where("staff_id = ?", staffID).Order("weekday, start_time").Find(&schedule.WorkingHours).Error; err != nil {
+		return nil, fmt.Errorf("查詢上班時段失敗: %w", err)
+	}
+	if err := db.Where("staff_id = ?", staffID).Order("weekday, start_time").Find(&schedule.Breaks).Error; err != nil {
+		return nil, fmt.Errorf("查詢休息時間失敗: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// ReplaceWeeklySchedule 以新的排班取代員工原有的每週排班
+func (r *ScheduleRepository) ReplaceWeeklySchedule(ctx context.Context, staffID uint, schedule *WeeklySchedule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("staff_id = ?", staffID).Delete(&models.StaffWorkingHours{}).Error; err != nil {
+			return fmt.Errorf("清除上班時段失敗: %w", err)
+		}
+		if err := tx.Where("staff_id = ?", staffID).Delete(&models.StaffBreak{}).Error; err != nil {
+			return fmt.Errorf("清除休息時間失敗: %w", err)
+		}
+
+		for i := range schedule.WorkingHours {
+			schedule.WorkingHours[i].ID = 0
+			schedule.WorkingHours[i].StaffID = staffID
+		}
+		for i := range schedule.Breaks {
+			schedule.Breaks[i].ID = 0
+			schedule.Breaks[i].StaffID = staffID
+		}
+
+		if len(schedule.WorkingHours) > 0 {
+			if err := tx.Create(&schedule.WorkingHours).Error; err != nil {
+				return fmt.Errorf("新增上班時段失敗: %w", err)
+			}
+		}
+		if len(schedule.Breaks) > 0 {
+			if err := tx.Create(&schedule.Breaks).Error; err != nil {
+				return fmt.Errorf("新增休息時間失敗: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListWorkingHoursByWeekday 列出所有員工在某個星期幾的上班時段
+func (r *ScheduleRepository) ListWorkingHoursByWeekday(ctx context.Context, weekday time.Weekday) ([]models.StaffWorkingHours, error) {
+	var hours []models.StaffWorkingHours
+	if err := r.db.WithContext(ctx).Where("weekday = ?", int(weekday)).Order("staff_id, start_time").Find(&hours).Error; err != nil {
+		return nil, fmt.Errorf("查詢上班時段失敗: %w", err)
+	}
+	return hours, nil
+}
+
+// ListBreaksByWeekday 列出所有員工在某個星期幾的休息時間
+func (r *ScheduleRepository) ListBreaksByWeekday(ctx context.Context, weekday time.Weekday) ([]models.StaffBreak, error) {
+	var breaks []models.StaffBreak
+	if err := r.db.WithContext(ctx).Where("weekday = ?", int(weekday)).Order("staff_id, start_time").Find(&breaks).Error; err != nil {
+		return nil, fmt.Errorf("查詢休息時間失敗: %w", err)
+	}
+	return breaks, nil
+}
+
+// ListTimeOff 列出休假，staffID 為 0 時列出所有員工；from、to 為日期區間 [from, to)
+func (r *ScheduleRepository) ListTimeOff(ctx context.Context, staffID uint, from, to time.Time) ([]models.StaffTimeOff, error) {
+	query := r.db.WithContext(ctx)
+	if staffID != 0 {
+		query = query.Where("staff_id = ?", staffID)
+	}
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", to)
+	}
+
+	var timeOff []models.StaffTimeOff
+	if err := query.Order("date, staff_id, start_time").Find(&timeOff).Error; err != nil {
+		return nil, fmt.Errorf("查詢休假失敗: %w", err)
+	}
+	return timeOff, nil
+}
+
+// CreateTimeOff 新增休假
+func (r *ScheduleRepository) CreateTimeOff(ctx context.Context, timeOff *models.StaffTimeOff) error {
+	if err := r.db.WithContext(ctx).Create(timeOff).Error; err != nil {
+		return fmt.Errorf("新增休假失敗: %w", err)
+	}
+	return nil
+}
+
+// DeleteTimeOff 刪除員工的一筆休假，返回是否有資料被刪除
+func (r *ScheduleRepository) DeleteTimeOff(ctx context.Context, staffID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("staff_id = ?", staffID).Delete(&models.StaffTimeOff{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("刪除休假失敗: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListHolidays 列出日期區間 [from, to) 內的店休日
+func (r *ScheduleRepository) ListHolidays(ctx context.Context, from, to time.Time) ([]models.Holiday, error) {
+	query := r.db.WithContext(ctx)
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", to)
+	}
+
+	var holidays []models.Holiday
+	if err := query.Order("date").Find(&holidays).Error; err != nil {
+		return nil, fmt.Errorf("查詢店休日失敗: %w", err)
+	}
+	return holidays, nil
+}
+
+// GetHoliday 查詢某天是否為店休日，不是時返回 nil
+func (r *ScheduleRepository) GetHoliday(ctx context.Context, date time.Time) (*models.Holiday, error) {
+	var holiday models.Holiday
+
+	result := r.db.WithContext(ctx).Where("date = ?", date).First(&holiday)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢店休日失敗: %w", result.Error)
+	}
+	return &holiday, nil
+}
+
+// SaveHoliday 新增店休日，同一天已存在時更新名稱
+func (r *ScheduleRepository) SaveHoliday(ctx context.Context, holiday *models.Holiday) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name"}),
+	}).Create(holiday).Error
+	if err != nil {
+		return fmt.Errorf("儲存店休日失敗: %w", err)
+	}
+	return nil
+}
+
+// DeleteHoliday 刪除店休日，返回是否有資料被刪除
+func (r *ScheduleRepository) DeleteHoliday(ctx context.Context, date time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Where("date = ?", date).Delete(&models.Holiday{})
+	if result.Error != nil {
+		return false, fmt.Errorf("刪除店休日失敗: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
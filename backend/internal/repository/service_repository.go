@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+)
+
+// ServiceRepository 提供服務項目的存取方法
+type ServiceRepository struct {
+	db *gorm.DB
+}
+
+// NewServiceRepository 創建一個新的服務項目資料存取層
+func NewServiceRepository(db *gorm.DB) *ServiceRepository {
+	return &ServiceRepository{
+		db: db,
+	}
+}
+
+// ServiceFilter 服務項目的查詢條件
+type ServiceFilter struct {
+	Category   string
+	ActiveOnly bool
+}
+
+// ListServices 依條件列出服務項目，依分類與名稱排序
+func (r *ServiceRepository) ListServices(ctx context.Context, filter ServiceFilter) ([]models.Service, error) {
+	query := r.db.WithContext(ctx).Order("category, name, id")
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	var services []models.Service
+	if err := query.Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("查詢服務項目失敗: %w", err)
+	}
+
+	return services, nil
+}
+
+// GetServiceByID 透過 ID 查找服務項目，不存在時返回 nil
+func (r *ServiceRepository) GetServiceByID(ctx context.Context, id uint) (*models.Service, error) {
+	var svc models.Service
+
+	result := r.db.WithContext(ctx).First(&svc, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢服務項目失敗: %w", result.Error)
+	}
+
+	return &svc, nil
+}
+
+// GetServiceByName 透過名稱查找服務項目，不存在時返回 nil
+func (r *ServiceRepository) GetServiceByName(ctx context.Context, name string) (*models.Service, error) {
+	var svc models.Service
+
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&svc)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢服務項目失敗: %w", result.Error)
+	}
+
+	return &svc, nil
+}
+
+// GetServicesByIDs 一次查詢多個服務項目，以 ID 為鍵返回，不存在的 ID 不會出現在結果中
+func (r *ServiceRepository) GetServicesByIDs(ctx context.Context, ids []uint) (map[uint]models.Service, error) {
+	result := make(map[uint]models.Service, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var services []models.Service
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("查詢服務項目失敗: %w", err)
+	}
+	for _, svc := range services {
+		result[svc.ID] = svc
+	}
+	return result, nil
+}
+
+// CreateService 新增服務項目並寫入第一筆定價紀錄
+func (r *ServiceRepository) CreateService(ctx context.Context, svc *models.Service, changedBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(svc).Error; err != nil {
+			return fmt.Errorf("新增服務項目失敗: %w", err)
+		}
+		return createServicePrice(tx, svc, changedBy)
+	})
+}
+
+// UpdateService 更新服務項目，售價變動時同時寫入定價紀錄
+func (r *ServiceRepository) UpdateService(ctx context.Context, svc *models.Service, changedBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Service
+		if err := tx.Select("price").First(&current, svc.ID).Error; err != nil {
+			return fmt.Errorf("查詢服務項目失敗: %w", err)
+		}
+
+		err := tx.Model(svc).
+			Select("name", "category", "price", "duration_minutes", "active").
+			Updates(svc).Error
+		if err != nil {
+			return fmt.Errorf("更新服務項目失敗: %w", err)
+		}
+
+		if current.Price != svc.Price {
+			return createServicePrice(tx, svc, changedBy)
+		}
+		return nil
+	})
+}
+
+// ListPriceHistory 列出服務項目的定價紀錄，新的在前
+func (r *ServiceRepository) ListPriceHistory(ctx context.Context, serviceID uint) ([]models.ServicePrice, error) {
+	var prices []models.ServicePrice
+	if err := r.db.WithContext(ctx).Where("service_id = ?", serviceID).Order("effective_at DESC, id DESC").Find(&prices).Error; err != nil {
+		return nil, fmt.Errorf("查詢定價紀錄失敗: %w", err)
+	}
+	return prices, nil
+}
+
+// createServicePrice 寫入服務項目目前售價的定價紀錄
+func createServicePrice(tx *gorm.DB, svc *models.Service, changedBy string) error {
+	price := &models.ServicePrice{
+		ServiceID:   svc.ID,
+		Price:       svc.Price,
+		EffectiveAt: time.Now(),
+		ChangedBy:   changedBy,
+	}
+	if err := tx.Create(price).Error; err != nil {
+		return fmt.Errorf("新增定價紀錄失敗: %w", err)
+	}
+	return nil
+}
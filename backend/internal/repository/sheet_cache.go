@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
+)
+
+// SheetSnapshot 快取中的一份試算表資料
+type SheetSnapshot struct {
+	Values    [][]interface{}
+	Revision  string // Drive 檔案修改時間
+	FetchedAt time.Time
+}
+
+// SheetCache 客戶資料試算表的讀取快取
+// 每隔 checkInterval 以 Drive 修改時間確認資料是否變更，變更或超過 ttl 時重新下載；
+// 同時間的多個重新整理請求只會呼叫一次 Sheets API
+type SheetCache struct {
+	repo          *SheetRepository
+	ttl           time.Duration
+	checkInterval time.Duration
+	group         singleflight.Group
+	logger        *slog.Logger
+
+	mu        sync.RWMutex
+	snapshot  *SheetSnapshot
+	checkedAt time.Time
+}
+
+// NewSheetCache 創建一個新的試算表快取
+func NewSheetCache(repo *SheetRepository, ttl, checkInterval time.Duration) *SheetCache {
+	return &SheetCache{
+		repo:          repo,
+		ttl:           ttl,
+		checkInterval: checkInterval,
+		logger:        logging.Component("sheets"),
+	}
+}
+
+// GetValues 實作 CustomerSource，優先返回快取內容
+func (c *SheetCache) GetValues(ctx context.Context) ([][]interface{}, error) {
+	c.mu.RLock()
+	snapshot, checkedAt := c.snapshot, c.checkedAt
+	c.mu.RUnlock()
+
+	if snapshot != nil && time.Since(checkedAt) < c.checkInterval && time.Since(snapshot.FetchedAt) < c.ttl {
+		metrics.ObserveSheetsCache("hit")
+		return snapshot.Values, nil
+	}
+
+	snapshot, err := c.do(ctx, "get", func(ctx context.Context) (*SheetSnapshot, error) {
+		return c.revalidate(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Values, nil
+}
+
+// Refresh 忽略快取，強制重新下載試算表
+func (c *SheetCache) Refresh(ctx context.Context) (*SheetSnapshot, error) {
+	return c.do(ctx, "refresh", func(ctx context.Context) (*SheetSnapshot, error) {
+		revision, err := c.repo.Revision(ctx)
+		if err != nil {
+			c.logger.WarnContext(ctx, "讀取試算表版本失敗", "error", err)
+		}
+		return c.fetch(ctx, revision)
+	})
+}
+
+// Snapshot 返回目前的快取內容，尚未載入時為 nil
+func (c *SheetCache) Snapshot() *SheetSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot
+}
+
+// do 以 singleflight 合併相同種類的請求
+// 載入不應因單一請求取消而中斷，因此以不帶取消的 context 執行
+func (c *SheetCache) do(ctx context.Context, key string, fn func(ctx context.Context) (*SheetSnapshot, error)) (*SheetSnapshot, error) {
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*SheetSnapshot), nil
+	}
+}
+
+// revalidate 比對 Drive 修改時間，資料未變更且未超過 ttl 時沿用快取
+func (c *SheetCache) revalidate(ctx context.Context) (*SheetSnapshot, error) {
+	c.mu.RLock()
+	snapshot := c.snapshot
+	c.mu.RUnlock()
+
+	revision, err := c.repo.Revision(ctx)
+	if err != nil {
+		// 無法確認版本時，在 ttl 內繼續提供舊資料，並在 checkInterval 內不再重試，
+		// 避免 Drive 異常期間每個請求都再次呼叫失敗的 API
+		c.logger.WarnContext(ctx, "讀取試算表版本失敗", "error", err)
+		if snapshot != nil && time.Since(snapshot.FetchedAt) < c.ttl {
+			c.mu.Lock()
+			c.checkedAt = time.Now()
+			c.mu.Unlock()
+			metrics.ObserveSheetsCache("stale")
+			return snapshot, nil
+		}
+	}
+
+	if err == nil && snapshot != nil && snapshot.Revision == revision && time.Since(snapshot.FetchedAt) < c.ttl {
+		c.mu.Lock()
+		c.checkedAt = time.Now()
+		c.mu.Unlock()
+		metrics.ObserveSheetsCache("revalidated")
+		return snapshot, nil
+	}
+
+	metrics.ObserveSheetsCache("miss")
+	return c.fetch(ctx, revision)
+}
+
+// fetch 下載試算表並更新快取
+func (c *SheetCache) fetch(ctx context.Context, revision string) (*SheetSnapshot, error) {
+	values, err := c.repo.GetValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	snapshot := &SheetSnapshot{
+		Values:    values,
+		Revision:  revision,
+		FetchedAt: now,
+	}
+
+	c.mu.Lock()
+	c.snapshot = snapshot
+	c.checkedAt = now
+	c.mu.Unlock()
+
+	c.logger.InfoContext(ctx, "已更新試算表快取", "rows", len(values), "revision", revision)
+	return snapshot, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	"backend/pkg/configs"
+	"backend/pkg/metrics"
+)
+
+// CustomerSource 客戶資料來源，由試算表或其快取實作
+type CustomerSource interface {
+	GetValues(ctx context.Context) ([][]interface{}, error)
+}
+
+// SheetRepository 提供客戶資料試算表的存取方法
+type SheetRepository struct {
+	srv    *sheets.Service
+	drive  *drive.Service
+	config configs.SheetsConfig
+}
+
+// NewSheetRepository 以服務帳號建立 Sheets 與 Drive API 連線
+// Drive API 只用於讀取檔案的修改時間，判斷快取是否過期
+func NewSheetRepository(ctx context.Context, config configs.SheetsConfig) (*SheetRepository, error) {
+	srv, err := sheets.NewService(ctx, option.WithCredentialsFile(config.ServiceAccountPath))
+	if err != nil {
+		return nil, fmt.Errorf("建立 Sheets 服務失敗: %w", err)
+	}
+
+	driveSrv, err := drive.NewService(ctx,
+		option.WithCredentialsFile(config.ServiceAccountPath),
+		option.WithScopes(drive.DriveMetadataReadonlyScope),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("建立 Drive 服務失敗: %w", err)
+	}
+
+	return &SheetRepository{
+		srv:    srv,
+		drive:  driveSrv,
+		config: config,
+	}, nil
+}
+
+// GetValues 取得設定範圍內的所有儲存格，第一列為標題
+func (r *SheetRepository) GetValues(ctx context.Context) ([][]interface{}, error) {
+	ctx, span := otel.Tracer("backend/internal/repository").Start(ctx, "SheetRepository.GetValues")
+	defer span.End()
+
+	start := time.Now()
+	resp, err := r.srv.Spreadsheets.Values.Get(r.config.SpreadsheetID, r.config.ReadRange).Context(ctx).Do()
+	metrics.ObserveSheetsCall("values.get", start, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "讀取試算表失敗")
+		return nil, fmt.Errorf("讀取試算表失敗: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("sheets.rows", len(resp.Values)))
+	return resp.Values, nil
+}
+
+// Revision 取得試算表檔案在 Drive 上的修改時間，作為資料版本
+func (r *SheetRepository) Revision(ctx context.Context) (string, error) {
+	start := time.Now()
+	file, err := r.drive.Files.Get(r.config.SpreadsheetID).Fields("modifiedTime").SupportsAllDrives(true).Context(ctx).Do()
+	metrics.ObserveSheetsCall("drive.files.get", start, err)
+	if err != nil {
+		return "", fmt.Errorf("讀取試算表修改時間失敗: %w", err)
+	}
+
+	return file.ModifiedTime, nil
+}
+
+// Ping 確認服務帳號可以存取試算表，只讀取試算表 ID 以節省配額
+func (r *SheetRepository) Ping(ctx context.Context) error {
+	start := time.Now()
+	_, err := r.srv.Spreadsheets.Get(r.config.SpreadsheetID).Fields("spreadsheetId").Context(ctx).Do()
+	metrics.ObserveSheetsCall("spreadsheets.get", start, err)
+	if err != nil {
+		return fmt.Errorf("無法存取試算表: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+)
+
+// StaffRepository 提供員工資料的存取方法
+type StaffRepository struct {
+	db *gorm.DB
+}
+
+// NewStaffRepository 創建一個新的員工資料存取層
+func NewStaffRepository(db *gorm.DB) *StaffRepository {
+	return &StaffRepository{
+		db: db,
+	}
+}
+
+// ListStaff 列出員工，activeOnly 為 true 時只列出在職員工
+func (r *StaffRepository) ListStaff(ctx context.Context, activeOnly bool) ([]models.Staff, error) {
+	query := r.db.WithContext(ctx).Order("name, id")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	var staff []models.Staff
+	if err := query.Find(&staff).Error; err != nil {
+		return nil, fmt.Errorf("查詢員工失敗: %w", err)
+	}
+
+	return staff, nil
+}
+
+// GetStaffByID 透過 ID 查找員工，不存在時返回 nil
+func (r *StaffRepository) GetStaffByID(ctx context.Context, id uint) (*models.Staff, error) {
+	var staff models.Staff
+
+	result := r.db.WithContext(ctx).First(&staff, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢員工失敗: %w", result.Error)
+	}
+
+	return &staff, nil
+}
+
+// CreateStaff 新增員工
+func (r *StaffRepository) CreateStaff(ctx context.Context, staff *models.Staff) error {
+	if err := r.db.WithContext(ctx).Create(staff).Error; err != nil {
+		return fmt.Errorf("新增員工失敗: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStaff 更新員工的姓名、帳號與在職狀態
+func (r *StaffRepository) UpdateStaff(ctx context.Context, staff *models.Staff) error {
+	result := r.db.WithContext(ctx).Model(staff).
+		Select("name", "user_id", "active").
+		Updates(staff)
+	if result.Error != nil {
+		return fmt.Errorf("更新員工失敗: %w", result.Error)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -22,10 +23,10 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 // GetUserByEmail 透過電子郵件查找使用者
-func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	
-	result := r.db.Where("email = ?", email).First(&user)
+	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
 	if result.Error != nil {
 		// 如果記錄不存在，返回 nil 而非錯誤
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -38,10 +39,10 @@ func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 }
 
 // GetUserByID 透過 ID 查找使用者
-func (r *UserRepository) GetUserByID(id string) (*models.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	var user models.User
 	
-	result := r.db.First(&user, "id = ?", id)
+	result := r.db.WithContext(ctx).First(&user, "id = ?", id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -53,8 +54,8 @@ func (r *UserRepository) GetUserByID(id string) (*models.User, error) {
 }
 
 // CreateUser 創建新使用者
-func (r *UserRepository) CreateUser(user *models.User) error {
-	result := r.db.Create(user)
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
+	result := r.db.WithContext(ctx).Create(user)
 	if result.Error != nil {
 		return fmt.Errorf("創建使用者失敗: %w", result.Error)
 	}
@@ -63,9 +64,9 @@ func (r *UserRepository) CreateUser(user *models.User) error {
 }
 
 // UpdateUser 更新現有使用者
-func (r *UserRepository) UpdateUser(user *models.User) error {
+func (r *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
 	// 只更新特定欄位，避免覆蓋其他欄位
-	result := r.db.Model(user).
+	result := r.db.WithContext(ctx).Model(user).
 		Select("name", "picture", "last_login").
 		Updates(map[string]interface{}{
 			"name":       user.Name,
@@ -80,9 +81,22 @@ func (r *UserRepository) UpdateUser(user *models.User) error {
 	return nil
 }
 
+// UpdateUserRole 更新使用者角色
+func (r *UserRepository) UpdateUserRole(ctx context.Context, userID, role string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("role", role)
+
+	if result.Error != nil {
+		return fmt.Errorf("更新使用者角色失敗: %w", result.Error)
+	}
+
+	return nil
+}
+
 // CreateSession 創建新會話
-func (r *UserRepository) CreateSession(session *models.Session) error {
-	result := r.db.Create(session)
+func (r *UserRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	result := r.db.WithContext(ctx).Create(session)
 	if result.Error != nil {
 		return fmt.Errorf("創建會話失敗: %w", result.Error)
 	}
@@ -91,10 +105,10 @@ func (r *UserRepository) CreateSession(session *models.Session) error {
 }
 
 // GetSessionByID 透過 ID 查找會話
-func (r *UserRepository) GetSessionByID(id string) (*models.Session, error) {
+func (r *UserRepository) GetSessionByID(ctx context.Context, id string) (*models.Session, error) {
 	var session models.Session
 	
-	result := r.db.First(&session, "id = ?", id)
+	result := r.db.WithContext(ctx).First(&session, "id = ?", id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -106,8 +120,8 @@ func (r *UserRepository) GetSessionByID(id string) (*models.Session, error) {
 }
 
 // UpdateSessionExpiry 更新會話過期時間
-func (r *UserRepository) UpdateSessionExpiry(sessionID string, expiresAt time.Time) error {
-	result := r.db.Model(&models.Session{}).
+func (r *UserRepository) UpdateSessionExpiry(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
 		Where("id = ?", sessionID).
 		Update("expires_at", expiresAt)
 		
@@ -119,8 +133,8 @@ func (r *UserRepository) UpdateSessionExpiry(sessionID string, expiresAt time.Ti
 }
 
 // DeleteSession 刪除會話
-func (r *UserRepository) DeleteSession(sessionID string) error {
-	result := r.db.Where("id = ?", sessionID).Delete(&models.Session{})
+func (r *UserRepository) DeleteSession(ctx context.Context, sessionID string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", sessionID).Delete(&models.Session{})
 	if result.Error != nil {
 		return fmt.Errorf("刪除會話失敗: %w", result.Error)
 	}
@@ -129,8 +143,8 @@ func (r *UserRepository) DeleteSession(sessionID string) error {
 }
 
 // DeleteExpiredSessions 刪除所有過期會話
-func (r *UserRepository) DeleteExpiredSessions() error {
-	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.Session{})
+func (r *UserRepository) DeleteExpiredSessions(ctx context.Context) error {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.Session{})
 	if result.Error != nil {
 		return fmt.Errorf("刪除過期會話失敗: %w", result.Error)
 	}
@@ -139,10 +153,10 @@ func (r *UserRepository) DeleteExpiredSessions() error {
 }
 
 // GetUserWithSessions 獲取用戶及其所有會話
-func (r *UserRepository) GetUserWithSessions(userID string) (*models.User, error) {
+func (r *UserRepository) GetUserWithSessions(ctx context.Context, userID string) (*models.User, error) {
 	var user models.User
 	
-	result := r.db.Preload("Sessions").First(&user, "id = ?", userID)
+	result := r.db.WithContext(ctx).Preload("Sessions").First(&user, "id = ?", userID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -154,10 +168,10 @@ func (r *UserRepository) GetUserWithSessions(userID string) (*models.User, error
 }
 
 // CountUserSessions 計算使用者的活躍會話數
-func (r *UserRepository) CountUserSessions(userID string) (int64, error) {
+func (r *UserRepository) CountUserSessions(ctx context.Context, userID string) (int64, error) {
 	var count int64
 	
-	result := r.db.Model(&models.Session{}).
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
 		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
 		Count(&count)
 		
@@ -166,4 +180,19 @@ func (r *UserRepository) CountUserSessions(userID string) (int64, error) {
 	}
 	
 	return count, nil
-}
\ No newline at end of file
+}
+
+// CountActiveSessions 計算所有使用者的有效會話數
+func (r *UserRepository) CountActiveSessions(ctx context.Context) (int64, error) {
+	var count int64
+
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("expires_at > ?", time.Now()).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("計算有效會話失敗: %w", result.Error)
+	}
+
+	return count, nil
+}
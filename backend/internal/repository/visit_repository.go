@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/models"
+)
+
+// ErrAppointmentConverted 預約已轉為消費紀錄
+var ErrAppointmentConverted = errors.New("預約已轉為消費紀錄")
+
+// VisitFilter 消費紀錄查詢條件，零值欄位代表不篩選
+type VisitFilter struct {
+	CustomerName string
+	StaffID      uint
+	From         time.Time
+	To           time.Time
+}
+
+// VisitRepository 提供消費紀錄的資料存取方法
+type VisitRepository struct {
+	db *gorm.DB
+}
+
+// NewVisitRepository 創建一個新的消費紀錄資料存取層
+func NewVisitRepository(db *gorm.DB) *VisitRepository {
+	return &VisitRepository{
+		db: db,
+	}
+}
+
+// ListVisits 依條件查詢消費紀錄，新的在前
+func (r *VisitRepository) ListVisits(ctx context.Context, filter VisitFilter) ([]models.Visit, error) {
+	query := r.db.WithContext(ctx).Preload("Items")
+
+	if filter.CustomerName != "" {
+		query = query.Where("customer_name = ?", filter.CustomerName)
+	}
+	if filter.StaffID != 0 {
+		query = query.Where("staff_id = ?", filter.StaffID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("visit_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("visit_date < ?", filter.To)
+	}
+
+	var visits []models.Visit
+	if err := query.Order("visit_date DESC, id DESC").Find(&visits).Error; err != nil {
+		return nil, fmt.Errorf("查詢消費紀錄失敗: %w", err)
+	}
+
+	return visits, nil
+}
+
+// GetVisitByID 透過 ID 查找消費紀錄 (包含項目)，不存在時返回 nil
+func (r *VisitRepository) GetVisitByID(ctx context.Context, id uint) (*models.Visit, error) {
+	var visit models.Visit
+
+	result := r.db.WithContext(ctx).Preload("Items").First(&visit, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查詢消費紀錄失敗: %w", result.Error)
+	}
+
+	return &visit, nil
+}
+
+// CreateVisit 新增消費紀錄與其項目，並在同一交易內扣抵套票堂數與儲值金、扣減零售商品庫存
+// 消費日期所在月份已結算時返回 ErrMonthClosed，套票無法使用時返回 ErrPackageUnavailable，
+// 儲值金不足時返回 ErrInsufficientBalance，點數不足時返回 ErrInsufficientPoints，庫存不足時返回 ErrInsufficientStock
+func (r *VisitRepository) CreateVisit(ctx context.Context, visit *models.Visit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureMonthOpen(tx, visit.VisitDate); err != nil {
+			return err
+		}
+		if err := tx.Create(visit).Error; err != nil {
+			return fmt.Errorf("新增消費紀錄失敗: %w", err)
+		}
+		if err := redeemPrepaid(tx, visit); err != nil {
+			return err
+		}
+		if err := deductStock(tx, visit); err != nil {
+			return err
+		}
+		return postPoints(tx, visit)
+	})
+}
+
+// CreateVisitFromAppointment 以同一交易新增消費紀錄、標記預約已轉換，並與 CreateVisit 相同地扣抵套票與累積點數
+// 預約已轉換過時返回 ErrAppointmentConverted，消費日期所在月份已結算時返回 ErrMonthClosed，
+// 套票無法使用時返回 ErrPackageUnavailable
+func (r *VisitRepository) CreateVisitFromAppointment(ctx context.Context, visit *models.Visit, appointmentID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureMonthOpen(tx, visit.VisitDate); err != nil {
+			return err
+		}
+
+		var appointment models.Appointment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&appointment, appointmentID).Error
+		if err != nil {
+			return fmt.Errorf("查詢預約失敗: %w", err)
+		}
+		if appointment.VisitID != nil {
+			return ErrAppointmentConverted
+		}
+
+		visit.AppointmentID = &appointment.ID
+		if err := tx.Create(visit).Error; err != nil {
+			return fmt.Errorf("新增消費紀錄失敗: %w", err)
+		}
+
+		if err := tx.Model(&appointment).Update("visit_id", visit.ID).Error; err != nil {
+			return fmt.Errorf("更新預約失敗: %w", err)
+		}
+		if err := redeemPrepaid(tx, visit); err != nil {
+			return err
+		}
+		if err := deductStock(tx, visit); err != nil {
+			return err
+		}
+		return postPoints(tx, visit)
+	})
+}
+
+// SumCustomerTotals 加總客戶自 from 起的消費總額
+func (r *VisitRepository) SumCustomerTotals(ctx context.Context, customerName string, from time.Time) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Visit{}).
+		Where("customer_name = ? AND visit_date >= ?", customerName, from).
+		Select("COALESCE(SUM(total), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("加總消費金額失敗: %w", err)
+	}
+	return total, nil
+}
+
+// LastVisitDates 返回每位客戶最後一次消費的日期
+func (r *VisitRepository) LastVisitDates(ctx context.Context) (map[string]time.Time, error) {
+	var rows []struct {
+		CustomerName string
+		LastVisit    time.Time
+	}
+	err := r.db.WithContext(ctx).Model(&models.Visit{}).
+		Select("customer_name, MAX(visit_date) AS last_visit").
+		Group("customer_name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("查詢最後消費日期失敗: %w", err)
+	}
+
+	dates := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		dates[row.CustomerName] = row.LastVisit
+	}
+	return dates, nil
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/notify"
+)
+
+// appointmentTransitions 預約狀態允許的轉換，完成、取消與未到為最終狀態
+var appointmentTransitions = map[string][]string{
+	models.AppointmentStatusScheduled: {
+		models.AppointmentStatusConfirmed,
+		models.AppointmentStatusCompleted,
+		models.AppointmentStatusCancelled,
+		models.AppointmentStatusNoShow,
+	},
+	models.AppointmentStatusConfirmed: {
+		models.AppointmentStatusCompleted,
+		models.AppointmentStatusCancelled,
+		models.AppointmentStatusNoShow,
+	},
+}
+
+// AppointmentInput 新增或修改預約的內容
+// EndAt 為零值時依項目的服務時間計算結束時間
+type AppointmentInput struct {
+	CustomerName  string                 `json:"customer_name"`
+	CustomerPhone string                 `json:"customer_phone"`
+	StaffID       uint                   `json:"staff_id"`
+	StartAt       time.Time              `json:"start_at"`
+	EndAt         time.Time              `json:"end_at"`
+	Note          string                 `json:"note"`
+	Items         []AppointmentItemInput `json:"items"`
+}
+
+// AppointmentItemInput 預約的服務項目，名稱、金額與時間依服務項目目錄帶入
+type AppointmentItemInput struct {
+	ServiceID uint `json:"service_id"`
+}
+
+// ConversionInput 預約轉為消費紀錄時的付款方式，可省略
+type ConversionInput struct {
+	Packages []ConversionPackage `json:"packages"`
+}
+
+// ConversionPackage 以套票扣抵預約的一個項目
+type ConversionPackage struct {
+	ItemID    uint `json:"item_id"`
+	PackageID uint `json:"package_id"`
+}
+
+// AppointmentService 提供預約相關的業務邏輯
+type AppointmentService struct {
+	appointmentRepo *repository.AppointmentRepository
+	staffRepo       *repository.StaffRepository
+	scheduleRepo    *repository.ScheduleRepository
+	serviceRepo     *repository.ServiceRepository
+	visitRepo       *repository.VisitRepository
+	visits          *VisitService
+	config          configs.AppointmentsConfig
+	location        *time.Location
+	logger          *slog.Logger
+}
+
+// NewAppointmentService 創建一個新的預約服務，location 為營業時區
+func NewAppointmentService(appointmentRepo *repository.AppointmentRepository, staffRepo *repository.StaffRepository, scheduleRepo *repository.ScheduleRepository, serviceRepo *repository.ServiceRepository, visitRepo *repository.VisitRepository, visits *VisitService, config configs.AppointmentsConfig, location *time.Location) *AppointmentService {
+	return &AppointmentService{
+		appointmentRepo: appointmentRepo,
+		staffRepo:       staffRepo,
+		scheduleRepo:    scheduleRepo,
+		serviceRepo:     serviceRepo,
+		visitRepo:       visitRepo,
+		visits:          visits,
+		config:          config,
+		location:        location,
+		logger:          logging.Component("appointments"),
+	}
+}
+
+// List 依條件查詢預約
+func (s *AppointmentService) List(ctx context.Context, filter repository.AppointmentFilter) ([]models.Appointment, error) {
+	return s.appointmentRepo.ListAppointments(ctx, filter)
+}
+
+// Get 取得預約，不存在時返回 NOT_FOUND
+func (s *AppointmentService) Get(ctx context.Context, id uint) (*models.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetAppointmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "appointments.not_found")
+	}
+	return appointment, nil
+}
+
+// Create 新增預約，超出員工可接客時段或與其他預約重疊時返回 CONFLICT
+func (s *AppointmentService) Create(ctx context.Context, input AppointmentInput, createdBy string) (*models.Appointment, error) {
+	appointment := &models.Appointment{
+		Status:    models.AppointmentStatusScheduled,
+		CreatedBy: createdBy,
+	}
+	if err := s.apply(ctx, appointment, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.appointmentRepo.CreateAppointment(ctx, appointment, s.confirmation(appointment)); err != nil {
+		return nil, conflictError(err)
+	}
+
+	s.logger.InfoContext(ctx, "已新增預約", "appointment_id", appointment.ID, "staff_id", appointment.StaffID)
+	return s.Get(ctx, appointment.ID)
+}
+
+// Update 修改尚未結束的預約，寫入時在交易內重新確認預約仍可修改
+func (s *AppointmentService) Update(ctx context.Context, id uint, input AppointmentInput) (*models.Appointment, error) {
+	appointment, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.Open() {
+		return nil, apperror.New(apperror.CodeConflict, "appointments.closed")
+	}
+
+	if err := s.apply(ctx, appointment, input); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.UpdateAppointment(ctx, appointment); err != nil {
+		return nil, conflictError(err)
+	}
+
+	return s.Get(ctx, id)
+}
+
+// UpdateStatus 變更預約狀態，只允許 appointmentTransitions 中定義的轉換
+func (s *AppointmentService) UpdateStatus(ctx context.Context, id uint, status string) (*models.Appointment, error) {
+	appointment, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, next := range appointmentTransitions[appointment.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, apperror.New(apperror.CodeConflict, "appointments.invalid_transition", appointment.Status, status)
+	}
+
+	if err := s.appointmentRepo.UpdateAppointmentStatus(ctx, id, appointment.Status, status); err != nil {
+		if errors.Is(err, repository.ErrAppointmentStatusChanged) {
+			return nil, apperror.Wrap(err, apperror.CodeConflict, "appointments.status_changed")
+		}
+		return nil, err
+	}
+
+	appointment.Status = status
+	return appointment, nil
+}
+
+// Delete 刪除預約，已轉為消費紀錄的預約不可刪除
+func (s *AppointmentService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return conflictError(s.appointmentRepo.DeleteAppointment(ctx, id))
+}
+
+// ConvertToVisit 將已完成的預約轉為消費紀錄
+// 項目與金額與新增消費紀錄相同地依服務項目目錄建立，可指定以套票扣抵個別項目，並累積點數
+func (s *AppointmentService) ConvertToVisit(ctx context.Context, id uint, input ConversionInput, createdBy string) (*models.Visit, error) {
+	appointment, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != models.AppointmentStatusCompleted {
+		return nil, apperror.New(apperror.CodeConflict, "appointments.not_completed")
+	}
+
+	packages := make(map[uint]uint, len(input.Packages))
+	for _, p := range input.Packages {
+		packages[p.ItemID] = p.PackageID
+	}
+
+	start := appointment.StartAt.In(s.location)
+	visitInput := VisitInput{
+		CustomerName: appointment.CustomerName,
+		VisitDate:    start.Format(dateLayout),
+		StaffID:      &appointment.StaffID,
+		Note:         appointment.Note,
+	}
+	for _, item := range appointment.Items {
+		if item.ServiceID == nil {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "appointments.item_not_in_catalogue", item.Name)
+		}
+		visitItem := VisitItemInput{ServiceID: *item.ServiceID}
+		if packageID, ok := packages[item.ID]; ok {
+			visitItem.PackageID = &packageID
+			delete(packages, item.ID)
+		}
+		visitInput.Items = append(visitInput.Items, visitItem)
+	}
+	for _, p := range input.Packages {
+		if _, unused := packages[p.ItemID]; unused {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "appointments.unknown_item", p.ItemID)
+		}
+	}
+
+	visit, err := s.visits.build(ctx, visitInput, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.visitRepo.CreateVisitFromAppointment(ctx, visit, appointment.ID); err != nil {
+		return nil, balanceError(monthClosedError(conflictError(err)))
+	}
+
+	s.logger.InfoContext(ctx, "預約已轉為消費紀錄", "appointment_id", appointment.ID, "visit_id", visit.ID)
+	return visit, nil
+}
+
+// confirmation 組合預約確認通知，未設定確認管道或客戶缺少該管道的聯絡方式時返回 nil
+func (s *AppointmentService) confirmation(appointment *models.Appointment) *models.OutboxMessage {
+	recipient := notify.Recipient{
+		Name:  appointment.CustomerName,
+		Phone: appointment.CustomerPhone,
+	}
+	channel := s.config.ConfirmationChannel
+	if channel == "" || !recipient.HasAddress(channel) {
+		return nil
+	}
+
+	body := strings.NewReplacer(
+		"{name}", appointment.CustomerName,
+		"{time}", appointment.StartAt.In(s.location).Format("2006/01/02 15:04"),
+	).Replace(s.config.ConfirmationMessage)
+	return &models.OutboxMessage{
+		Kind:      models.OutboxKindAppointmentConfirmation,
+		Channel:   channel,
+		Recipient: recipient,
+		Subject:   "預約確認",
+		Body:      body,
+	}
+}
+
+// apply 驗證輸入並寫入預約，員工必須存在且在職，時段必須落在員工可接客的時間內
+func (s *AppointmentService) apply(ctx context.Context, appointment *models.Appointment, input AppointmentInput) error {
+	customerName := strings.TrimSpace(input.CustomerName)
+	if customerName == "" {
+		return apperror.New(apperror.CodeInvalidRequest, "appointments.customer_required")
+	}
+	if input.StartAt.IsZero() {
+		return apperror.New(apperror.CodeInvalidRequest, "appointments.start_required")
+	}
+
+	serviceIDs := make([]uint, 0, len(input.Items))
+	for _, item := range input.Items {
+		serviceIDs = append(serviceIDs, item.ServiceID)
+	}
+	catalogue, err := s.serviceRepo.GetServicesByIDs(ctx, serviceIDs)
+	if err != nil {
+		return err
+	}
+
+	items := make([]models.AppointmentItem, 0, len(input.Items))
+	duration := 0
+	for _, item := range input.Items {
+		svc, ok := catalogue[item.ServiceID]
+		if !ok || !svc.Active {
+			return apperror.New(apperror.CodeInvalidRequest, "appointments.unknown_service", item.ServiceID)
+		}
+		serviceID := svc.ID
+		items = append(items, models.AppointmentItem{
+			ServiceID:       &serviceID,
+			Name:            svc.Name,
+			Price:           svc.Price,
+			DurationMinutes: svc.DurationMinutes,
+		})
+		duration += svc.DurationMinutes
+	}
+
+	endAt := input.EndAt
+	if endAt.IsZero() {
+		endAt = input.StartAt.Add(time.Duration(duration) * time.Minute)
+	}
+	if !endAt.After(input.StartAt) {
+		return apperror.New(apperror.CodeInvalidRequest, "appointments.invalid_time")
+	}
+
+	staff, err := s.staffRepo.GetStaffByID(ctx, input.StaffID)
+	if err != nil {
+		return err
+	}
+	if staff == nil || !staff.Active {
+		return apperror.New(apperror.CodeInvalidRequest, "appointments.invalid_staff")
+	}
+	if err := s.checkSchedule(ctx, staff.ID, input.StartAt, endAt); err != nil {
+		return err
+	}
+
+	appointment.CustomerName = customerName
+	appointment.CustomerPhone = strings.TrimSpace(input.CustomerPhone)
+	appointment.StaffID = staff.ID
+	appointment.Staff = nil
+	appointment.StartAt = input.StartAt
+	appointment.EndAt = endAt
+	appointment.Note = input.Note
+	appointment.Items = items
+	return nil
+}
+
+// checkSchedule 確認 [startAt, endAt) 完整落在員工當天的上班時段內，
+// 且不在休息、休假或店休日；預約不可跨越營業時區的午夜
+func (s *AppointmentService) checkSchedule(ctx context.Context, staffID uint, startAt, endAt time.Time) error {
+	outside := apperror.New(apperror.CodeConflict, "appointments.outside_schedule")
+
+	start := startAt.In(s.location)
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, s.location)
+	if endAt.After(dayStart.AddDate(0, 0, 1)) {
+		return outside
+	}
+
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	holiday, err := s.scheduleRepo.GetHoliday(ctx, day)
+	if err != nil {
+		return err
+	}
+	if holiday != nil {
+		return outside
+	}
+
+	free, err := workingSpans(ctx, s.scheduleRepo, staffID, day)
+	if err != nil {
+		return err
+	}
+	booking := span{minutesSince(dayStart, startAt), minutesSince(dayStart, endAt)}
+	for _, sp := range free[staffID] {
+		if sp.contains(booking) {
+			return nil
+		}
+	}
+	return outside
+}
+
+// conflictError 將時段重疊、預約已結束與已轉為消費紀錄轉為 CONFLICT，其餘錯誤原樣返回
+func conflictError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAppointmentConflict):
+		return apperror.Wrap(err, apperror.CodeConflict, "appointments.staff_conflict")
+	case errors.Is(err, repository.ErrAppointmentClosed):
+		return apperror.Wrap(err, apperror.CodeConflict, "appointments.closed")
+	case errors.Is(err, repository.ErrAppointmentConverted):
+		return apperror.Wrap(err, apperror.CodeConflict, "appointments.converted")
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logging"
+)
+
+// 稽核紀錄查詢筆數限制
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+	maxAuditExportRows   = 50000
+)
+
+// AuditService 提供稽核紀錄相關的業務邏輯
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+	logger    *slog.Logger
+}
+
+// NewAuditService 創建一個新的稽核服務
+func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    logging.Component("audit"),
+	}
+}
+
+// Record 寫入一筆稽核紀錄，失敗時記錄日誌並回傳錯誤由呼叫端決定是否中斷
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditLog) error {
+	if entry.Action == "" {
+		return fmt.Errorf("稽核紀錄缺少動作類型")
+	}
+
+	if err := s.auditRepo.CreateAuditLog(ctx, entry); err != nil {
+		s.logger.ErrorContext(ctx, "寫入稽核紀錄失敗", "action", entry.Action, "actor_id", entry.ActorID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// List 依條件查詢稽核紀錄
+func (s *AuditService) List(ctx context.Context, filter repository.AuditFilter) ([]models.AuditLog, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	if filter.Limit > maxAuditPageSize {
+		filter.Limit = maxAuditPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	logs, total, err := s.auditRepo.ListAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查詢稽核紀錄失敗: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// Export 匯出符合條件的所有稽核紀錄 (上限為 maxAuditExportRows 筆)
+func (s *AuditService) Export(ctx context.Context, filter repository.AuditFilter) ([]models.AuditLog, error) {
+	filter.Limit = maxAuditExportRows
+	filter.Offset = 0
+
+	logs, _, err := s.auditRepo.ListAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("匯出稽核紀錄失敗: %w", err)
+	}
+
+	return logs, nil
+}
@@ -3,27 +3,53 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"backend/internal/repository"
 	"backend/internal/models"
 	"backend/internal/auth"
+	"backend/pkg/lifecycle"
+	"backend/pkg/logging"
 )
 
+// tracer 服務層的追蹤器
+var tracer = otel.Tracer("backend/internal/services")
+
 // AuthService 提供身份驗證相關的業務邏輯
 type AuthService struct {
-	googleAuth *auth.GoogleAuth
-	userRepo   *repository.UserRepository
+	googleAuth  *auth.GoogleAuth
+	userRepo    *repository.UserRepository
+	workers     *lifecycle.WorkerGroup
+	audit       *AuditService
+	adminEmails map[string]bool
+	logger      *slog.Logger
 }
 
 // NewAuthService 創建一個新的身份驗證服務
-func NewAuthService(googleAuth *auth.GoogleAuth, userRepo *repository.UserRepository) *AuthService {
+// adminEmails 是管理員名單，使用者每次登入時依名單同步角色
+func NewAuthService(googleAuth *auth.GoogleAuth, userRepo *repository.UserRepository, workers *lifecycle.WorkerGroup, audit *AuditService, adminEmails []string) *AuthService {
+	admins := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" {
+			admins[email] = true
+		}
+	}
+
 	return &AuthService{
-		googleAuth: googleAuth,
-		userRepo:   userRepo,
+		googleAuth:  googleAuth,
+		userRepo:    userRepo,
+		workers:     workers,
+		audit:       audit,
+		adminEmails: admins,
+		logger:      logging.Component("auth"),
 	}
 }
 
@@ -33,9 +59,9 @@ func (s *AuthService) ValidateGoogleToken(ctx context.Context, token string) (ma
 }
 
 // AuthenticateUser 處理使用者身份驗證，返回用戶 ID
-func (s *AuthService) AuthenticateUser(email, name, picture, subID string) (string, error) {
+func (s *AuthService) AuthenticateUser(ctx context.Context, email, name, picture, subID string) (string, error) {
 	// 查找現有使用者
-	user, err := s.userRepo.GetUserByEmail(email)
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return "", fmt.Errorf("查詢使用者失敗: %w", err)
 	}
@@ -47,14 +73,15 @@ func (s *AuthService) AuthenticateUser(email, name, picture, subID string) (stri
 			Email:     email,
 			Name:      name,
 			Picture:   picture,
+			Role:      s.roleFor(email),
 			LastLogin: time.Now(),
 		}
 		
-		if err := s.userRepo.CreateUser(newUser); err != nil {
+		if err := s.userRepo.CreateUser(ctx, newUser); err != nil {
 			return "", fmt.Errorf("創建使用者失敗: %w", err)
 		}
 		
-		log.Printf("新使用者註冊: %s (%s)", name, email)
+		s.logger.InfoContext(ctx, "新使用者註冊", "user_id", subID, "email", email)
 		return subID, nil
 	}
 	
@@ -63,16 +90,39 @@ func (s *AuthService) AuthenticateUser(email, name, picture, subID string) (stri
 	user.Picture = picture
 	user.LastLogin = time.Now()
 	
-	if err := s.userRepo.UpdateUser(user); err != nil {
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
 		return "", fmt.Errorf("更新使用者失敗: %w", err)
 	}
-	
-	log.Printf("使用者登入: %s (%s)", name, email)
+
+	// 角色以設定檔的管理員名單為準，移出名單的使用者降回一般員工
+	if role := s.roleFor(email); user.Role != role {
+		if err := s.userRepo.UpdateUserRole(ctx, user.ID, role); err != nil {
+			return "", fmt.Errorf("更新使用者角色失敗: %w", err)
+		}
+		s.logger.InfoContext(ctx, "使用者角色變更", "user_id", user.ID, "email", email, "from", user.Role, "to", role)
+		// 稽核寫入失敗已由 AuditService 記錄，不影響登入
+		_ = s.audit.Record(ctx, &models.AuditLog{
+			ActorID:    user.ID,
+			ActorEmail: email,
+			Action:     models.AuditActionRoleChange,
+			Detail:     fmt.Sprintf("%s -> %s", user.Role, role),
+		})
+	}
+
+	s.logger.InfoContext(ctx, "使用者登入", "user_id", user.ID, "email", email)
 	return user.ID, nil
 }
 
+// roleFor 依管理員名單決定使用者角色
+func (s *AuthService) roleFor(email string) string {
+	if s.adminEmails[strings.ToLower(email)] {
+		return models.RoleAdmin
+	}
+	return models.RoleStaff
+}
+
 // CreateUserSession 創建使用者會話
-func (s *AuthService) CreateUserSession(userID, ip, userAgent string, expTime float64) (string, time.Time, error) {
+func (s *AuthService) CreateUserSession(ctx context.Context, userID, ip, userAgent string, expTime float64) (string, time.Time, error) {
 	// 產生唯一會話 ID
 	sessionID := uuid.New().String()
 	
@@ -97,63 +147,80 @@ func (s *AuthService) CreateUserSession(userID, ip, userAgent string, expTime fl
 	}
 	
 	// 儲存到資料庫
-	if err := s.userRepo.CreateSession(session); err != nil {
+	if err := s.userRepo.CreateSession(ctx, session); err != nil {
 		return "", time.Time{}, fmt.Errorf("創建會話失敗: %w", err)
 	}
 	
 	// 清理過期會話 (非同步執行)
-	go func() {
-		if err := s.userRepo.DeleteExpiredSessions(); err != nil {
-			log.Printf("清理過期會話失敗: %v", err)
+	s.workers.Go(ctx, "delete-expired-sessions", func(ctx context.Context) {
+		if err := s.userRepo.DeleteExpiredSessions(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "清理過期會話失敗", "error", err)
 		}
-	}()
+	})
 	
 	return sessionID, expiresAt, nil
 }
 
 // ValidateSession 驗證會話有效性
-func (s *AuthService) ValidateSession(sessionID string) (bool, error) {
+func (s *AuthService) ValidateSession(ctx context.Context, sessionID string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ValidateSession")
+	defer span.End()
+
 	// 獲取會話
-	session, err := s.userRepo.GetSessionByID(sessionID)
+	session, err := s.userRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "查詢會話失敗")
 		return false, fmt.Errorf("查詢會話失敗: %w", err)
 	}
 	
 	// 會話不存在
 	if session == nil {
+		span.SetAttributes(attribute.String("session.result", "not_found"))
 		return false, nil
 	}
 	
 	// 檢查會話是否過期
 	if time.Now().After(session.ExpiresAt) {
 		// 刪除過期會話
-		if err := s.userRepo.DeleteSession(sessionID); err != nil {
-			log.Printf("刪除過期會話失敗: %v", err)
+		if err := s.userRepo.DeleteSession(ctx, sessionID); err != nil {
+			s.logger.ErrorContext(ctx, "刪除過期會話失敗", "error", err)
 		}
+		span.SetAttributes(attribute.String("session.result", "expired"))
 		return false, nil
 	}
 	
 	// 會話有效
+	span.SetAttributes(attribute.String("session.result", "valid"))
 	return true, nil
 }
 
 // RefreshSession 延長會話有效期
-func (s *AuthService) RefreshSession(sessionID string) error {
+func (s *AuthService) RefreshSession(ctx context.Context, sessionID string) error {
 	// 新的過期時間 (1 小時後)
 	newExpiryTime := time.Now().Add(30 * 24 * time.Hour)
 	
 	// 更新資料庫中的過期時間
-	if err := s.userRepo.UpdateSessionExpiry(sessionID, newExpiryTime); err != nil {
+	if err := s.userRepo.UpdateSessionExpiry(ctx, sessionID, newExpiryTime); err != nil {
 		return fmt.Errorf("更新會話失敗: %w", err)
 	}
 	
 	return nil
 }
 
+// RefreshSessionAsync 在背景延長會話有效期，服務關閉時會等待其完成
+func (s *AuthService) RefreshSessionAsync(ctx context.Context, sessionID string) {
+	s.workers.Go(ctx, "refresh-session", func(ctx context.Context) {
+		if err := s.RefreshSession(ctx, sessionID); err != nil {
+			s.logger.ErrorContext(ctx, "刷新會話失敗", "error", err)
+		}
+	})
+}
+
 // LogoutUser 登出使用者
-func (s *AuthService) LogoutUser(sessionID string) error {
+func (s *AuthService) LogoutUser(ctx context.Context, sessionID string) error {
 	// 從資料庫刪除會話
-	if err := s.userRepo.DeleteSession(sessionID); err != nil {
+	if err := s.userRepo.DeleteSession(ctx, sessionID); err != nil {
 		return fmt.Errorf("登出失敗: %w", err)
 	}
 	
@@ -161,11 +228,31 @@ func (s *AuthService) LogoutUser(sessionID string) error {
 }
 
 // GetUserActiveSessions 獲取使用者活躍會話數
-func (s *AuthService) GetUserActiveSessions(userID string) (int64, error) {
-	count, err := s.userRepo.CountUserSessions(userID)
+func (s *AuthService) GetUserActiveSessions(ctx context.Context, userID string) (int64, error) {
+	count, err := s.userRepo.CountUserSessions(ctx, userID)
 	if err != nil {
 		return 0, fmt.Errorf("查詢活躍會話失敗: %w", err)
 	}
 	
 	return count, nil
-}
\ No newline at end of file
+}
+
+// IsAdmin 檢查使用者是否具有管理員角色
+func (s *AuthService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("查詢使用者失敗: %w", err)
+	}
+
+	return user != nil && user.Role == models.RoleAdmin, nil
+}
+
+// CountActiveSessions 計算所有使用者的有效會話數
+func (s *AuthService) CountActiveSessions(ctx context.Context) (int64, error) {
+	count, err := s.userRepo.CountActiveSessions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("查詢有效會話失敗: %w", err)
+	}
+
+	return count, nil
+}
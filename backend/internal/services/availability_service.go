@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+)
+
+// Availability 某天某項服務的可預約時段
+type Availability struct {
+	Date    string          `json:"date"`
+	Service *models.Service `json:"service"`
+	// Holiday 當天為店休日時的名稱，此時沒有可預約時段
+	Holiday string `json:"holiday,omitempty"`
+	Slots   []Slot `json:"slots"`
+}
+
+// Slot 一個可預約時段
+type Slot struct {
+	StaffID   uint      `json:"staff_id"`
+	StaffName string    `json:"staff_name"`
+	StartAt   time.Time `json:"start_at"`
+	EndAt     time.Time `json:"end_at"`
+}
+
+// AvailabilityService 依員工排班、休假、店休日與既有預約計算可預約時段
+type AvailabilityService struct {
+	scheduleRepo    *repository.ScheduleRepository
+	staffRepo       *repository.StaffRepository
+	serviceRepo     *repository.ServiceRepository
+	appointmentRepo *repository.AppointmentRepository
+	location        *time.Location
+	interval        int
+	now             func() time.Time
+}
+
+// NewAvailabilityService 創建一個新的可預約時段服務
+// location 為營業時區，interval 為時段起始時間的間隔 (分鐘)
+func NewAvailabilityService(scheduleRepo *repository.ScheduleRepository, staffRepo *repository.StaffRepository, serviceRepo *repository.ServiceRepository, appointmentRepo *repository.AppointmentRepository, location *time.Location, interval int) *AvailabilityService {
+	return &AvailabilityService{
+		scheduleRepo:    scheduleRepo,
+		staffRepo:       staffRepo,
+		serviceRepo:     serviceRepo,
+		appointmentRepo: appointmentRepo,
+		location:        location,
+		interval:        interval,
+		now:             time.Now,
+	}
+}
+
+// Slots 返回 date (YYYY-MM-DD) 當天可預約 serviceID 的時段，staffID 為 0 時包含所有在職員工
+// 已經過去的時段不會列出
+func (s *AvailabilityService) Slots(ctx context.Context, serviceID, staffID uint, date string) (*Availability, error) {
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil || !svc.Active {
+		return nil, apperror.New(apperror.CodeNotFound, "services.not_found")
+	}
+
+	day, err := parseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	result := &Availability{Date: date, Service: svc, Slots: []Slot{}}
+
+	holiday, err := s.scheduleRepo.GetHoliday(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	if holiday != nil {
+		result.Holiday = holiday.Name
+		return result, nil
+	}
+
+	staff, err := s.staffRepo.ListStaff(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// 營業時區的當天零時，時段以距離零時的分鐘數計算
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.location)
+	free, err := s.freeSpans(ctx, staffID, day, dayStart)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	for _, member := range staff {
+		if staffID != 0 && member.ID != staffID {
+			continue
+		}
+		for _, start := range slotStarts(free[member.ID], svc.DurationMinutes, s.interval) {
+			startAt := models.ClockTime(start).On(dayStart)
+			if startAt.Before(now) {
+				continue
+			}
+			result.Slots = append(result.Slots, Slot{
+				StaffID:   member.ID,
+				StaffName: member.Name,
+				StartAt:   startAt,
+				EndAt:     models.ClockTime(start + svc.DurationMinutes).On(dayStart),
+			})
+		}
+	}
+
+	sort.SliceStable(result.Slots, func(i, j int) bool {
+		return result.Slots[i].StartAt.Before(result.Slots[j].StartAt)
+	})
+	return result, nil
+}
+
+// freeSpans 計算每位員工當天的空檔：可接客的時段再扣除既有預約
+func (s *AvailabilityService) freeSpans(ctx context.Context, staffID uint, day, dayStart time.Time) (map[uint][]span, error) {
+	free, err := workingSpans(ctx, s.scheduleRepo, staffID, day)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := s.appointmentRepo.ListAppointments(ctx, repository.AppointmentFilter{
+		StaffID: staffID,
+		From:    dayStart,
+		To:      dayStart.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range appointments {
+		if !a.OccupiesSlot() {
+			continue
+		}
+		busy := span{minutesSince(dayStart, a.StartAt), minutesSince(dayStart, a.EndAt)}
+		free[a.StaffID] = subtractSpan(free[a.StaffID], busy)
+	}
+
+	return free, nil
+}
+
+// slotStarts 列出空檔中可容納 duration 分鐘的起始時間，起始時間對齊 interval 的倍數
+func slotStarts(spans []span, duration, interval int) []int {
+	var starts []int
+	for _, sp := range spans {
+		start := (sp.start + interval - 1) / interval * interval
+		for ; start+duration <= sp.end; start += interval {
+			starts = append(starts, start)
+		}
+	}
+	sort.Ints(starts)
+	return starts
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSubtractSpan(t *testing.T) {
+	day := []span{{9 * 60, 18 * 60}}
+
+	tests := []struct {
+		name  string
+		spans []span
+		busy  []span
+		want  []span
+	}{
+		{
+			name:  "午休切開上班時段",
+			spans: day,
+			busy:  []span{{12 * 60, 13 * 60}},
+			want:  []span{{9 * 60, 12 * 60}, {13 * 60, 18 * 60}},
+		},
+		{
+			name:  "整天休假",
+			spans: day,
+			busy:  []span{{0, 24 * 60}},
+			want:  []span{},
+		},
+		{
+			name:  "上午半天休假",
+			spans: day,
+			busy:  []span{{0, 12 * 60}},
+			want:  []span{{12 * 60, 18 * 60}},
+		},
+		{
+			name:  "下午請假與午休",
+			spans: day,
+			busy:  []span{{12 * 60, 13 * 60}, {15 * 60, 18 * 60}},
+			want:  []span{{9 * 60, 12 * 60}, {13 * 60, 15 * 60}},
+		},
+		{
+			name:  "上班時間外的休假不影響",
+			spans: day,
+			busy:  []span{{18 * 60, 20 * 60}, {0, 9 * 60}},
+			want:  []span{{9 * 60, 18 * 60}},
+		},
+		{
+			name:  "分段班只扣除重疊的時段",
+			spans: []span{{9 * 60, 12 * 60}, {14 * 60, 20 * 60}},
+			busy:  []span{{11 * 60, 15 * 60}},
+			want:  []span{{9 * 60, 11 * 60}, {15 * 60, 20 * 60}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.spans
+			for _, busy := range tt.busy {
+				got = subtractSpan(got, busy)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subtractSpan = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinutesSince(t *testing.T) {
+	location := time.FixedZone("Asia/Taipei", 8*60*60)
+	dayStart := time.Date(2024, 3, 15, 0, 0, 0, 0, location)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want int
+	}{
+		{"當天時間", time.Date(2024, 3, 15, 10, 30, 0, 0, location), 10*60 + 30},
+		{"其他時區表示的同一時刻", time.Date(2024, 3, 15, 2, 30, 0, 0, time.UTC), 10*60 + 30},
+		{"前一天開始的跨夜預約", time.Date(2024, 3, 14, 22, 0, 0, 0, location), 0},
+		{"跨夜到隔天結束的預約", time.Date(2024, 3, 16, 1, 0, 0, 0, location), 24 * 60},
+		{"當天結束", dayStart.AddDate(0, 0, 1), 24 * 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := minutesSince(dayStart, tt.t); got != tt.want {
+				t.Errorf("minutesSince = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotStarts(t *testing.T) {
+	tests := []struct {
+		name     string
+		spans    []span
+		duration int
+		interval int
+		want     []int
+	}{
+		{
+			name:     "起始時間對齊間隔",
+			spans:    []span{{9*60 + 10, 11 * 60}},
+			duration: 60,
+			interval: 30,
+			want:     []int{9*60 + 30, 10 * 60},
+		},
+		{
+			name:     "剛好容納一個時段",
+			spans:    []span{{10 * 60, 11 * 60}},
+			duration: 60,
+			interval: 15,
+			want:     []int{10 * 60},
+		},
+		{
+			name:     "空檔不足",
+			spans:    []span{{10 * 60, 10*60 + 45}},
+			duration: 60,
+			interval: 15,
+			want:     nil,
+		},
+		{
+			name:     "多段空檔依時間排序",
+			spans:    []span{{14 * 60, 15 * 60}, {9 * 60, 10 * 60}},
+			duration: 30,
+			interval: 30,
+			want:     []int{9 * 60, 9*60 + 30, 14 * 60, 14*60 + 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slotStarts(tt.spans, tt.duration, tt.interval)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("slotStarts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpanContains(t *testing.T) {
+	shift := span{9 * 60, 18 * 60}
+
+	tests := []struct {
+		name    string
+		booking span
+		want    bool
+	}{
+		{"完整落在上班時間內", span{10 * 60, 11 * 60}, true},
+		{"與上班時間相同", shift, true},
+		{"早於上班時間開始", span{8*60 + 30, 9*60 + 30}, false},
+		{"超過下班時間", span{17*60 + 30, 18*60 + 30}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shift.contains(tt.booking); got != tt.want {
+				t.Errorf("contains(%v) = %v, want %v", tt.booking, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// ServiceInput 新增或修改服務項目的內容
+type ServiceInput struct {
+	Name            string `json:"name"`
+	Category        string `json:"category"`
+	Price           int64  `json:"price"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Active          *bool  `json:"active"`
+}
+
+// CatalogService 維護服務項目目錄
+type CatalogService struct {
+	serviceRepo *repository.ServiceRepository
+	logger      *slog.Logger
+}
+
+// NewCatalogService 創建一個新的服務項目目錄服務
+func NewCatalogService(serviceRepo *repository.ServiceRepository) *CatalogService {
+	return &CatalogService{
+		serviceRepo: serviceRepo,
+		logger:      logging.Component("catalog"),
+	}
+}
+
+// List 依條件列出服務項目
+func (s *CatalogService) List(ctx context.Context, filter repository.ServiceFilter) ([]models.Service, error) {
+	return s.serviceRepo.ListServices(ctx, filter)
+}
+
+// Get 取得服務項目，不存在時返回 NOT_FOUND
+func (s *CatalogService) Get(ctx context.Context, id uint) (*models.Service, error) {
+	svc, err := s.serviceRepo.GetServiceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "services.not_found")
+	}
+	return svc, nil
+}
+
+// Create 新增服務項目，未指定上架狀態時預設為上架
+func (s *CatalogService) Create(ctx context.Context, input ServiceInput, changedBy string) (*models.Service, error) {
+	svc := &models.Service{Active: input.Active == nil || *input.Active}
+	if err := s.apply(ctx, svc, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.serviceRepo.CreateService(ctx, svc, changedBy); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "已新增服務項目", "service_id", svc.ID, "price", svc.Price)
+	return svc, nil
+}
+
+// Update 修改服務項目，售價變動會留下定價紀錄；未提供上架狀態時維持不變
+func (s *CatalogService) Update(ctx context.Context, id uint, input ServiceInput, changedBy string) (*models.Service, error) {
+	svc, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousPrice := svc.Price
+	if err := s.apply(ctx, svc, input); err != nil {
+		return nil, err
+	}
+	if input.Active != nil {
+		svc.Active = *input.Active
+	}
+
+	if err := s.serviceRepo.UpdateService(ctx, svc, changedBy); err != nil {
+		return nil, err
+	}
+
+	if previousPrice != svc.Price {
+		s.logger.InfoContext(ctx, "服務項目調價", "service_id", svc.ID, "from", previousPrice, "to", svc.Price)
+	}
+	return svc, nil
+}
+
+// PriceHistory 列出服務項目的定價紀錄
+func (s *CatalogService) PriceHistory(ctx context.Context, id uint) ([]models.ServicePrice, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.serviceRepo.ListPriceHistory(ctx, id)
+}
+
+// apply 驗證輸入並寫入服務項目，名稱不可與其他項目重複
+func (s *CatalogService) apply(ctx context.Context, svc *models.Service, input ServiceInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return apperror.New(apperror.CodeInvalidRequest, "services.name_required")
+	}
+	if input.Price < 0 {
+		return apperror.New(apperror.CodeInvalidRequest, "services.invalid_price")
+	}
+	if input.DurationMinutes <= 0 {
+		return apperror.New(apperror.CodeInvalidRequest, "services.invalid_duration")
+	}
+
+	existing, err := s.serviceRepo.GetServiceByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != svc.ID {
+		return apperror.New(apperror.CodeConflict, "services.duplicate_name", name)
+	}
+
+	svc.Name = name
+	svc.Category = strings.TrimSpace(input.Category)
+	svc.Price = input.Price
+	svc.DurationMinutes = input.DurationMinutes
+	return nil
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// 抽成報表狀態
+const (
+	CommissionReportDraft     = "draft"     // 尚未結算，依目前的規則與消費紀錄計算
+	CommissionReportFinalized = "finalized" // 已結算，內容不再變動
+)
+
+// monthLayout 月份參數格式
+const monthLayout = "2006-01"
+
+// CommissionRuleInput 新增或修改抽成規則的內容
+type CommissionRuleInput struct {
+	StaffID   *uint                  `json:"staff_id"`
+	Scope     string                 `json:"scope"`
+	ServiceID *uint                  `json:"service_id"`
+	Type      string                 `json:"type"`
+	RateBP    int                    `json:"rate_bp"`
+	Amount    int64                  `json:"amount"`
+	Tiers     models.CommissionTiers `json:"tiers"`
+}
+
+// CommissionReport 月份抽成報表
+type CommissionReport struct {
+	Month             string            `json:"month"`
+	Status            string            `json:"status"`
+	FinalizedAt       *time.Time        `json:"finalized_at,omitempty"`
+	FinalizedBy       string            `json:"finalized_by,omitempty"`
+	Staff             []StaffCommission `json:"staff"`
+	UnassignedRevenue int64             `json:"unassigned_revenue"` // 未指定員工的消費金額
+	TotalRevenue      int64             `json:"total_revenue"`
+	TotalCommission   int64             `json:"total_commission"`
+}
+
+// StaffCommission 一位員工當月的業績與抽成
+type StaffCommission struct {
+	StaffID        uint             `json:"staff_id"`
+	StaffName      string           `json:"staff_name"`
+	ServiceRevenue int64            `json:"service_revenue"`
+	RetailRevenue  int64            `json:"retail_revenue"`
+	Commission     int64            `json:"commission"`
+	Lines          []CommissionLine `json:"lines"`
+}
+
+// CommissionLine 一個消費項目的抽成，RuleID 為空表示沒有適用的規則
+// Price 為計算抽成的金額，以套票扣抵的項目為目錄售價
+type CommissionLine struct {
+	VisitID      uint   `json:"visit_id"`
+	VisitDate    string `json:"visit_date"`
+	CustomerName string `json:"customer_name"`
+	ItemName     string `json:"item_name"`
+	Kind         string `json:"kind"`
+	Price        int64  `json:"price"`
+	RuleID       *uint  `json:"rule_id,omitempty"`
+	RateBP       int    `json:"rate_bp"`
+	Commission   int64  `json:"commission"`
+}
+
+// CommissionService 維護抽成規則並計算月份抽成
+type CommissionService struct {
+	commissionRepo *repository.CommissionRepository
+	visitRepo      *repository.VisitRepository
+	staffRepo      *repository.StaffRepository
+	serviceRepo    *repository.ServiceRepository
+	location       *time.Location
+	logger         *slog.Logger
+}
+
+// NewCommissionService 創建一個新的抽成服務，location 用於判斷月份是否已結束
+func NewCommissionService(commissionRepo *repository.CommissionRepository, visitRepo *repository.VisitRepository, staffRepo *repository.StaffRepository, serviceRepo *repository.ServiceRepository, location *time.Location) *CommissionService {
+	return &CommissionService{
+		commissionRepo: commissionRepo,
+		visitRepo:      visitRepo,
+		staffRepo:      staffRepo,
+		serviceRepo:    serviceRepo,
+		location:       location,
+		logger:         logging.Component("commissions"),
+	}
+}
+
+// ListRules 列出所有抽成規則
+func (s *CommissionService) ListRules(ctx context.Context) ([]models.CommissionRule, error) {
+	return s.commissionRepo.ListRules(ctx)
+}
+
+// CreateRule 新增抽成規則
+func (s *CommissionService) CreateRule(ctx context.Context, input CommissionRuleInput) (*models.CommissionRule, error) {
+	rule := &models.CommissionRule{}
+	if err := s.applyRule(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.commissionRepo.CreateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule 修改抽成規則，已結算的月份不受影響
+func (s *CommissionService) UpdateRule(ctx context.Context, id uint, input CommissionRuleInput) (*models.CommissionRule, error) {
+	rule, err := s.commissionRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "commissions.rule_not_found")
+	}
+
+	if err := s.applyRule(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.commissionRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule 刪除抽成規則
+func (s *CommissionService) DeleteRule(ctx context.Context, id uint) error {
+	deleted, err := s.commissionRepo.DeleteRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperror.New(apperror.CodeNotFound, "commissions.rule_not_found")
+	}
+	return nil
+}
+
+// Report 返回月份 (YYYY-MM) 的抽成報表，已結算的月份返回結算時的明細
+func (s *CommissionService) Report(ctx context.Context, month string) (*CommissionReport, error) {
+	start, err := parseMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := s.commissionRepo.GetStatement(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	if statement != nil {
+		var report CommissionReport
+		if err := json.Unmarshal(statement.Report, &report); err != nil {
+			return nil, fmt.Errorf("解析抽成明細失敗: %w", err)
+		}
+		return &report, nil
+	}
+
+	return s.calculate(ctx, start)
+}
+
+// Close 結算月份，產生不可修改的抽成明細；月份尚未結束或已結算時返回 CONFLICT
+func (s *CommissionService) Close(ctx context.Context, month, finalizedBy string) (*CommissionReport, error) {
+	start, err := parseMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, s.location)
+	if time.Now().Before(end) {
+		return nil, apperror.New(apperror.CodeConflict, "commissions.month_not_ended", month)
+	}
+
+	var report *CommissionReport
+	_, err = s.commissionRepo.FinalizeStatement(ctx, start, func(ctx context.Context) (*models.CommissionStatement, error) {
+		var err error
+		if report, err = s.calculate(ctx, start); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		report.Status = CommissionReportFinalized
+		report.FinalizedAt = &now
+		report.FinalizedBy = finalizedBy
+
+		data, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("序列化抽成明細失敗: %w", err)
+		}
+		return &models.CommissionStatement{
+			Month:           month,
+			TotalCommission: report.TotalCommission,
+			Report:          data,
+			FinalizedAt:     now,
+			FinalizedBy:     finalizedBy,
+		}, nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrStatementFinalized) {
+			return nil, apperror.Wrap(err, apperror.CodeConflict, "commissions.already_finalized", month)
+		}
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "已結算月份抽成", "month", month, "total_commission", report.TotalCommission)
+	return report, nil
+}
+
+// calculate 依目前的規則計算月份抽成
+func (s *CommissionService) calculate(ctx context.Context, start time.Time) (*CommissionReport, error) {
+	visits, err := s.visitRepo.ListVisits(ctx, repository.VisitFilter{
+		From: start,
+		To:   start.AddDate(0, 1, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.commissionRepo.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return commissionReport(start, visits, rules), nil
+}
+
+// commissionReport 依規則計算 visits (新的在前) 的抽成，級距規則依員工在該規則下的當月業績決定比例
+func commissionReport(start time.Time, visits []models.Visit, rules []models.CommissionRule) *CommissionReport {
+	report := &CommissionReport{
+		Month:  start.Format(monthLayout),
+		Status: CommissionReportDraft,
+		Staff:  []StaffCommission{},
+	}
+
+	type lineRule struct {
+		line *CommissionLine
+		rule *models.CommissionRule
+	}
+	byStaff := map[uint]*StaffCommission{}
+	pending := map[uint][]lineRule{}
+	// tieredRevenue 每位員工在每條級距規則下的業績，用於決定級距
+	tieredRevenue := map[uint]map[uint]int64{}
+
+	// 舊的在前，讓明細依日期排列
+	for i := len(visits) - 1; i >= 0; i-- {
+		visit := visits[i]
+		for _, item := range visit.Items {
+			report.TotalRevenue += item.Price
+			if visit.StaffID == nil {
+				report.UnassignedRevenue += item.Price
+				continue
+			}
+
+			staffID := *visit.StaffID
+			staff, ok := byStaff[staffID]
+			if !ok {
+				staff = &StaffCommission{StaffID: staffID, StaffName: visit.StaffName, Lines: []CommissionLine{}}
+				byStaff[staffID] = staff
+			}
+
+			scope := commissionScope(item.Kind)
+			if scope == models.CommissionScopeRetail {
+				staff.RetailRevenue += item.Price
+			} else {
+				staff.ServiceRevenue += item.Price
+			}
+
+			line := &CommissionLine{
+				VisitID:      visit.ID,
+				VisitDate:    visit.VisitDate.Format(dateLayout),
+				CustomerName: visit.CustomerName,
+				ItemName:     item.Name,
+				Kind:         item.Kind,
+				Price:        commissionBase(item),
+			}
+			rule := matchCommissionRule(rules, staffID, scope, item.ServiceID)
+			if rule != nil {
+				line.RuleID = &rule.ID
+				if rule.Type == models.CommissionTypeTiered {
+					if tieredRevenue[staffID] == nil {
+						tieredRevenue[staffID] = map[uint]int64{}
+					}
+					tieredRevenue[staffID][rule.ID] += line.Price
+				}
+			}
+			pending[staffID] = append(pending[staffID], lineRule{line: line, rule: rule})
+		}
+	}
+
+	for staffID, lines := range pending {
+		staff := byStaff[staffID]
+		for _, lr := range lines {
+			if lr.rule != nil {
+				switch lr.rule.Type {
+				case models.CommissionTypePercentage:
+					lr.line.RateBP = lr.rule.RateBP
+					lr.line.Commission = applyRate(lr.line.Price, lr.rule.RateBP)
+				case models.CommissionTypeFixed:
+					lr.line.Commission = lr.rule.Amount
+				case models.CommissionTypeTiered:
+					lr.line.RateBP = tierRate(lr.rule.Tiers, tieredRevenue[staffID][lr.rule.ID])
+					lr.line.Commission = applyRate(lr.line.Price, lr.line.RateBP)
+				}
+			}
+			staff.Commission += lr.line.Commission
+			staff.Lines = append(staff.Lines, *lr.line)
+		}
+		report.TotalCommission += staff.Commission
+		report.Staff = append(report.Staff, *staff)
+	}
+
+	sort.Slice(report.Staff, func(i, j int) bool {
+		if report.Staff[i].StaffName != report.Staff[j].StaffName {
+			return report.Staff[i].StaffName < report.Staff[j].StaffName
+		}
+		return report.Staff[i].StaffID < report.Staff[j].StaffID
+	})
+	return report
+}
+
+// applyRule 驗證輸入並寫入抽成規則，只保留計算方式需要的欄位
+func (s *CommissionService) applyRule(ctx context.Context, rule *models.CommissionRule, input CommissionRuleInput) error {
+	if input.Scope != models.CommissionScopeService && input.Scope != models.CommissionScopeRetail {
+		return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_scope")
+	}
+	if input.ServiceID != nil && input.Scope != models.CommissionScopeService {
+		return apperror.New(apperror.CodeInvalidRequest, "commissions.service_scope_only")
+	}
+
+	if input.StaffID != nil {
+		staff, err := s.staffRepo.GetStaffByID(ctx, *input.StaffID)
+		if err != nil {
+			return err
+		}
+		if staff == nil {
+			return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_staff")
+		}
+	}
+	if input.ServiceID != nil {
+		svc, err := s.serviceRepo.GetServiceByID(ctx, *input.ServiceID)
+		if err != nil {
+			return err
+		}
+		if svc == nil {
+			return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_service")
+		}
+	}
+
+	rule.StaffID = input.StaffID
+	rule.Scope = input.Scope
+	rule.ServiceID = input.ServiceID
+	rule.Type = input.Type
+	rule.RateBP, rule.Amount, rule.Tiers = 0, 0, nil
+
+	switch input.Type {
+	case models.CommissionTypePercentage:
+		if input.RateBP < 0 || input.RateBP > 10000 {
+			return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_rate")
+		}
+		rule.RateBP = input.RateBP
+	case models.CommissionTypeFixed:
+		if input.Amount < 0 {
+			return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_amount")
+		}
+		rule.Amount = input.Amount
+	case models.CommissionTypeTiered:
+		if len(input.Tiers) == 0 {
+			return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_tiers")
+		}
+		for i, tier := range input.Tiers {
+			if tier.Threshold < 0 || tier.RateBP < 0 || tier.RateBP > 10000 ||
+				(i > 0 && tier.Threshold <= input.Tiers[i-1].Threshold) {
+				return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_tiers")
+			}
+		}
+		rule.Tiers = input.Tiers
+	default:
+		return apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_type")
+	}
+
+	rules, err := s.commissionRepo.ListRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range rules {
+		if other.ID != rule.ID && other.Scope == rule.Scope &&
+			equalID(other.StaffID, rule.StaffID) && equalID(other.ServiceID, rule.ServiceID) {
+			return apperror.New(apperror.CodeConflict, "commissions.duplicate_rule")
+		}
+	}
+	return nil
+}
+
+// commissionBase 項目計算抽成的金額，以套票扣抵的項目金額為 0，改以扣抵時的目錄售價計算
+// 套票售出時的金額不計入業績，員工施作的每一堂仍依目錄售價計算抽成
+func commissionBase(item models.VisitItem) int64 {
+	if item.PackageID != nil {
+		return item.ListPrice
+	}
+	return item.Price
+}
+
+// commissionScope 消費項目類型對應的抽成範圍
+func commissionScope(kind string) string {
+	if kind == models.VisitItemRetail {
+		return models.CommissionScopeRetail
+	}
+	return models.CommissionScopeService
+}
+
+// matchCommissionRule 找出項目適用且最優先的規則，沒有適用規則時返回 nil
+func matchCommissionRule(rules []models.CommissionRule, staffID uint, scope string, serviceID *uint) *models.CommissionRule {
+	var best *models.CommissionRule
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Scope != scope {
+			continue
+		}
+		if rule.StaffID != nil && *rule.StaffID != staffID {
+			continue
+		}
+		if rule.ServiceID != nil && (serviceID == nil || *rule.ServiceID != *serviceID) {
+			continue
+		}
+		if best == nil || rule.Specificity() > best.Specificity() {
+			best = rule
+		}
+	}
+	return best
+}
+
+// tierRate 返回業績達到的最高級距比例，未達第一個級距時為 0
+func tierRate(tiers models.CommissionTiers, revenue int64) int {
+	rate := 0
+	for _, tier := range tiers {
+		if revenue >= tier.Threshold {
+			rate = tier.RateBP
+		}
+	}
+	return rate
+}
+
+// applyRate 以萬分比計算金額，四捨五入至元
+func applyRate(amount int64, rateBP int) int64 {
+	return (amount*int64(rateBP) + 5000) / 10000
+}
+
+// equalID 比較兩個可為空的 ID
+func equalID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// parseMonth 解析 YYYY-MM 月份，結果為該月第一天 UTC 零時，與消費日期欄位對應
+func parseMonth(value string) (time.Time, error) {
+	month, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, apperror.New(apperror.CodeInvalidRequest, "commissions.invalid_month")
+	}
+	return month, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/models"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestMatchCommissionRule(t *testing.T) {
+	rules := []models.CommissionRule{
+		{ID: 1, Scope: models.CommissionScopeService},
+		{ID: 2, Scope: models.CommissionScopeService, ServiceID: uintPtr(10)},
+		{ID: 3, Scope: models.CommissionScopeService, StaffID: uintPtr(7)},
+		{ID: 4, Scope: models.CommissionScopeService, StaffID: uintPtr(7), ServiceID: uintPtr(10)},
+		{ID: 5, Scope: models.CommissionScopeRetail},
+	}
+
+	tests := []struct {
+		name      string
+		rules     []models.CommissionRule
+		staffID   uint
+		scope     string
+		serviceID *uint
+		want      uint // 0 代表沒有適用規則
+	}{
+		{"員工加服務項目最優先", rules, 7, models.CommissionScopeService, uintPtr(10), 4},
+		{"指定員工優先於指定服務項目", rules, 7, models.CommissionScopeService, uintPtr(11), 3},
+		{"指定服務項目優先於全體規則", rules, 8, models.CommissionScopeService, uintPtr(10), 2},
+		{"只有全體規則適用", rules, 8, models.CommissionScopeService, uintPtr(11), 1},
+		{"沒有服務項目的舊項目不符合服務項目規則", rules, 8, models.CommissionScopeService, nil, 1},
+		{"零售只比對零售規則", rules, 7, models.CommissionScopeRetail, nil, 5},
+		{"規則順序不影響優先程度", []models.CommissionRule{rules[3], rules[2], rules[1], rules[0]}, 7, models.CommissionScopeService, uintPtr(10), 4},
+		{"沒有適用規則", rules[:4], 7, models.CommissionScopeRetail, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchCommissionRule(tt.rules, tt.staffID, tt.scope, tt.serviceID)
+			var gotID uint
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.want {
+				t.Errorf("matchCommissionRule = rule %d, want rule %d", gotID, tt.want)
+			}
+		})
+	}
+}
+
+func TestTierRate(t *testing.T) {
+	tiers := models.CommissionTiers{
+		{Threshold: 50000, RateBP: 1000},
+		{Threshold: 100000, RateBP: 1500},
+		{Threshold: 200000, RateBP: 2000},
+	}
+
+	tests := []struct {
+		revenue int64
+		want    int
+	}{
+		{0, 0},
+		{49999, 0},
+		{50000, 1000},
+		{50001, 1000},
+		{99999, 1000},
+		{100000, 1500},
+		{199999, 1500},
+		{200000, 2000},
+		{1000000, 2000},
+	}
+
+	for _, tt := range tests {
+		if got := tierRate(tiers, tt.revenue); got != tt.want {
+			t.Errorf("tierRate(%d) = %d, want %d", tt.revenue, got, tt.want)
+		}
+	}
+}
+
+func TestApplyRate(t *testing.T) {
+	tests := []struct {
+		amount int64
+		rateBP int
+		want   int64
+	}{
+		{1000, 1000, 100},
+		{1000, 0, 0},
+		{1000, 10000, 1000},
+		{1005, 1000, 101}, // 100.5 四捨五入
+		{1004, 1000, 100}, // 100.4 捨去
+		{333, 1250, 42},   // 41.625
+		{3, 1500, 0},      // 0.45
+		{0, 1500, 0},
+	}
+
+	for _, tt := range tests {
+		if got := applyRate(tt.amount, tt.rateBP); got != tt.want {
+			t.Errorf("applyRate(%d, %d) = %d, want %d", tt.amount, tt.rateBP, got, tt.want)
+		}
+	}
+}
+
+func TestCommissionReport(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return start.AddDate(0, 0, d-1) }
+	staffID := uint(7)
+
+	rules := []models.CommissionRule{
+		{ID: 1, Scope: models.CommissionScopeService, Type: models.CommissionTypeTiered, Tiers: models.CommissionTiers{
+			{Threshold: 3000, RateBP: 1000},
+			{Threshold: 5000, RateBP: 2000},
+		}},
+		{ID: 2, Scope: models.CommissionScopeService, ServiceID: uintPtr(10), Type: models.CommissionTypeFixed, Amount: 150},
+		{ID: 3, Scope: models.CommissionScopeRetail, Type: models.CommissionTypePercentage, RateBP: 500},
+	}
+	// 新的在前，與 ListVisits 相同
+	visits := []models.Visit{
+		{ID: 3, VisitDate: day(20), StaffID: &staffID, StaffName: "小美", Items: []models.VisitItem{
+			{Kind: models.VisitItemService, Name: "臉部保養", Price: 2500},
+			{Kind: models.VisitItemRetail, Name: "精華液", Price: 1290},
+		}},
+		{ID: 2, VisitDate: day(10), Items: []models.VisitItem{
+			{Kind: models.VisitItemService, Name: "未指定員工", Price: 800},
+		}},
+		{ID: 1, VisitDate: day(2), StaffID: &staffID, StaffName: "小美", Items: []models.VisitItem{
+			{Kind: models.VisitItemService, Name: "臉部保養", Price: 2500},
+			{Kind: models.VisitItemService, ServiceID: uintPtr(10), Name: "眉型設計", Price: 600},
+		}},
+	}
+
+	report := commissionReport(start, visits, rules)
+
+	if report.Month != "2024-05" {
+		t.Errorf("Month = %q", report.Month)
+	}
+	if report.TotalRevenue != 7690 || report.UnassignedRevenue != 800 {
+		t.Errorf("TotalRevenue = %d, UnassignedRevenue = %d", report.TotalRevenue, report.UnassignedRevenue)
+	}
+	if len(report.Staff) != 1 {
+		t.Fatalf("len(Staff) = %d, want 1", len(report.Staff))
+	}
+
+	staff := report.Staff[0]
+	if staff.ServiceRevenue != 5600 || staff.RetailRevenue != 1290 {
+		t.Errorf("ServiceRevenue = %d, RetailRevenue = %d", staff.ServiceRevenue, staff.RetailRevenue)
+	}
+
+	// 級距規則的業績為 5000 (眉型設計適用固定金額規則，不計入)，達到 20% 級距
+	wantLines := []struct {
+		item       string
+		rateBP     int
+		commission int64
+	}{
+		{"臉部保養", 2000, 500},
+		{"眉型設計", 0, 150},
+		{"臉部保養", 2000, 500},
+		{"精華液", 500, 65}, // 64.5 四捨五入
+	}
+	if len(staff.Lines) != len(wantLines) {
+		t.Fatalf("len(Lines) = %d, want %d", len(staff.Lines), len(wantLines))
+	}
+	for i, want := range wantLines {
+		line := staff.Lines[i]
+		if line.ItemName != want.item || line.RateBP != want.rateBP || line.Commission != want.commission {
+			t.Errorf("Lines[%d] = %s %d bp %d, want %s %d bp %d",
+				i, line.ItemName, line.RateBP, line.Commission, want.item, want.rateBP, want.commission)
+		}
+	}
+	if staff.Commission != 1215 || report.TotalCommission != 1215 {
+		t.Errorf("Commission = %d, TotalCommission = %d, want 1215", staff.Commission, report.TotalCommission)
+	}
+}
+
+func TestCommissionReportPackageRedemption(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	staffID := uint(7)
+
+	tests := []struct {
+		name           string
+		rule           models.CommissionRule
+		wantRateBP     int
+		wantCommission int64
+	}{
+		{"比例規則依目錄售價計算", models.CommissionRule{ID: 1, Scope: models.CommissionScopeService, Type: models.CommissionTypePercentage, RateBP: 1000}, 1000, 250},
+		{"固定金額規則與一般項目相同", models.CommissionRule{ID: 1, Scope: models.CommissionScopeService, Type: models.CommissionTypeFixed, Amount: 150}, 0, 150},
+		{"級距規則以目錄售價累計業績", models.CommissionRule{ID: 1, Scope: models.CommissionScopeService, Type: models.CommissionTypeTiered, Tiers: models.CommissionTiers{
+			{Threshold: 2000, RateBP: 1500},
+		}}, 1500, 375},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visits := []models.Visit{
+				{ID: 1, VisitDate: start, StaffID: &staffID, StaffName: "小美", Items: []models.VisitItem{
+					{Kind: models.VisitItemService, Name: "臉部保養", PackageID: uintPtr(3), ListPrice: 2500, Price: 0},
+				}},
+			}
+
+			report := commissionReport(start, visits, []models.CommissionRule{tt.rule})
+
+			// 套票售出時已計入業績，扣抵的這一堂不重複計入
+			if report.TotalRevenue != 0 {
+				t.Errorf("TotalRevenue = %d, want 0", report.TotalRevenue)
+			}
+			if len(report.Staff) != 1 || len(report.Staff[0].Lines) != 1 {
+				t.Fatalf("report = %+v", report)
+			}
+			staff := report.Staff[0]
+			if staff.ServiceRevenue != 0 {
+				t.Errorf("ServiceRevenue = %d, want 0", staff.ServiceRevenue)
+			}
+			line := staff.Lines[0]
+			if line.Price != 2500 || line.RateBP != tt.wantRateBP || line.Commission != tt.wantCommission {
+				t.Errorf("line = %d %d bp %d, want 2500 %d bp %d", line.Price, line.RateBP, line.Commission, tt.wantRateBP, tt.wantCommission)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 試算表的欄位名稱
+const (
+	columnCustomerName = "客戶名"
+	columnDate         = "日期"
+	columnPhone        = "電話"
+	columnBirthday     = "生日"
+	columnEmail        = "Email"
+	columnLINE         = "LINE"
+)
+
+// CustomerProfile 由試算表整理出的客戶聯絡資料與最後到店日
+type CustomerProfile struct {
+	Name       string
+	Phone      string
+	Email      string
+	LineUserID string
+	// BirthMonth 與 BirthDay 為生日的月與日，BirthMonth 為 0 表示未填寫或無法解析
+	BirthMonth time.Month
+	BirthDay   int
+	// LastVisit 最後一筆消費的日期 (UTC 零時)，沒有紀錄時為零值
+	LastVisit time.Time
+}
+
+// buildCustomerDirectory 依試算表內容整理每位客戶的資料，第一列為標題
+// 同一位客戶有多列時，較後面的列的非空白欄位覆蓋較前面的列
+func buildCustomerDirectory(values [][]interface{}) (map[string]*CustomerProfile, error) {
+	directory := map[string]*CustomerProfile{}
+	if len(values) == 0 {
+		return directory, nil
+	}
+
+	columns := map[string]int{}
+	for i, col := range values[0] {
+		if name, ok := col.(string); ok {
+			columns[strings.TrimSpace(name)] = i
+		}
+	}
+	nameIdx, ok := columns[columnCustomerName]
+	if !ok {
+		return nil, errors.New("試算表找不到 [客戶名] 欄位")
+	}
+
+	cell := func(row []interface{}, column string) string {
+		idx, ok := columns[column]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		value, _ := row[idx].(string)
+		return strings.TrimSpace(value)
+	}
+
+	for _, row := range values[1:] {
+		if nameIdx >= len(row) {
+			continue
+		}
+		name, _ := row[nameIdx].(string)
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		profile, ok := directory[name]
+		if !ok {
+			profile = &CustomerProfile{Name: name}
+			directory[name] = profile
+		}
+		if v := cell(row, columnPhone); v != "" {
+			profile.Phone = v
+		}
+		if v := cell(row, columnEmail); v != "" {
+			profile.Email = v
+		}
+		if v := cell(row, columnLINE); v != "" {
+			profile.LineUserID = v
+		}
+		if _, month, day, ok := parseSheetDate(cell(row, columnBirthday)); ok {
+			profile.BirthMonth, profile.BirthDay = month, day
+		}
+		if year, month, day, ok := parseSheetDate(cell(row, columnDate)); ok && year > 0 {
+			date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+			if date.After(profile.LastVisit) {
+				profile.LastVisit = date
+			}
+		}
+	}
+
+	return directory, nil
+}
+
+// parseSheetDate 解析試算表中的日期，接受以 /、- 或 . 分隔的「年/月/日」與「月/日」
+// 年份小於 1911 時視為民國年；只有月日時 year 為 0
+func parseSheetDate(value string) (year int, month time.Month, day int, ok bool) {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.'
+	})
+
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		numbers = append(numbers, n)
+	}
+
+	switch len(numbers) {
+	case 2:
+		month, day = time.Month(numbers[0]), numbers[1]
+	case 3:
+		year, month, day = numbers[0], time.Month(numbers[1]), numbers[2]
+		if year < 1911 {
+			year += 1911
+		}
+	default:
+		return 0, 0, 0, false
+	}
+
+	// 以閏年檢查月日是否存在，2 月 29 日也視為有效
+	checkYear := year
+	if checkYear == 0 {
+		checkYear = 2000
+	}
+	date := time.Date(checkYear, month, day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != month || date.Day() != day {
+		return 0, 0, 0, false
+	}
+	return year, month, day, true
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSheetDate(t *testing.T) {
+	tests := []struct {
+		value string
+		year  int
+		month time.Month
+		day   int
+		ok    bool
+	}{
+		{"1990/05/17", 1990, time.May, 17, true},
+		{"1990-5-7", 1990, time.May, 7, true},
+		{"1990.12.31", 1990, time.December, 31, true},
+		{"79/05/17", 1990, time.May, 17, true},   // 民國 79 年
+		{"112/1/2", 2023, time.January, 2, true}, // 民國 112 年
+		{"05/17", 0, time.May, 17, true},         // 只有月日
+		{" 5 / 17 ", 0, time.May, 17, true},      // 前後空白
+		{"02/29", 0, time.February, 29, true},    // 沒有年份時 2 月 29 日有效
+		{"2000/02/29", 2000, time.February, 29, true},
+		{"89/02/29", 2000, time.February, 29, true}, // 民國 89 年為閏年
+		{"2023/02/29", 0, 0, 0, false},              // 非閏年沒有 2 月 29 日
+		{"02/30", 0, 0, 0, false},
+		{"13/01", 0, 0, 0, false},
+		{"1990/04/31", 0, 0, 0, false},
+		{"1990", 0, 0, 0, false},
+		{"1990/05/17/01", 0, 0, 0, false},
+		{"五月十七日", 0, 0, 0, false},
+		{"", 0, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			year, month, day, ok := parseSheetDate(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("parseSheetDate(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+			}
+			if ok && (year != tt.year || month != tt.month || day != tt.day) {
+				t.Errorf("parseSheetDate(%q) = %d-%d-%d, want %d-%d-%d",
+					tt.value, year, month, day, tt.year, tt.month, tt.day)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+const (
+	// maxCustomerTags 每位客戶最多的標籤數
+	maxCustomerTags = 20
+	// maxTagLength 標籤最多的字數
+	maxTagLength = 32
+	// signedAtTolerance 簽署時間允許晚於伺服器時間的誤差
+	signedAtTolerance = 5 * time.Minute
+)
+
+// NoteInput 新增客戶備註的內容
+type NoteInput struct {
+	CustomerName string `json:"customer_name"`
+	Body         string `json:"body"`
+}
+
+// TagsInput 設定客戶標籤的內容，以 Tags 取代客戶目前的標籤
+type TagsInput struct {
+	CustomerName string   `json:"customer_name"`
+	Tags         []string `json:"tags"`
+}
+
+// FormInput 登錄一份客戶簽署的表單，依 Kind 提供 Health 或 Consent
+type FormInput struct {
+	CustomerName string                 `json:"customer_name"`
+	Kind         string                 `json:"kind"`
+	Health       *models.HealthAnswers  `json:"health"`
+	Consent      *models.ConsentAnswers `json:"consent"`
+	SignedName   string                 `json:"signed_name"`
+	SignedAt     time.Time              `json:"signed_at"`
+}
+
+// CustomerRecordService 維護客戶備註、標籤與簽署的表單
+type CustomerRecordService struct {
+	recordRepo *repository.CustomerRecordRepository
+	logger     *slog.Logger
+}
+
+// NewCustomerRecordService 創建一個新的客戶紀錄服務
+func NewCustomerRecordService(recordRepo *repository.CustomerRecordRepository) *CustomerRecordService {
+	return &CustomerRecordService{
+		recordRepo: recordRepo,
+		logger:     logging.Component("customer_records"),
+	}
+}
+
+// Notes 列出客戶的備註
+func (s *CustomerRecordService) Notes(ctx context.Context, customerName string) ([]models.CustomerNote, error) {
+	customerName, err := requireCustomer(customerName)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordRepo.ListNotes(ctx, customerName)
+}
+
+// AddNote 新增客戶備註，author 為登入者
+func (s *CustomerRecordService) AddNote(ctx context.Context, input NoteInput, author string) (*models.CustomerNote, error) {
+	customerName, err := requireCustomer(input.CustomerName)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "customer_notes.body_required")
+	}
+
+	note := &models.CustomerNote{
+		CustomerName: customerName,
+		Body:         body,
+		Author:       author,
+	}
+	if err := s.recordRepo.CreateNote(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// Tags 列出客戶的標籤
+func (s *CustomerRecordService) Tags(ctx context.Context, customerName string) ([]string, error) {
+	customerName, err := requireCustomer(customerName)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordRepo.ListTags(ctx, customerName)
+}
+
+// SetTags 以 input.Tags 取代客戶的標籤，返回設定後的標籤
+// 標籤去除前後空白並轉為小寫後去除重複
+func (s *CustomerRecordService) SetTags(ctx context.Context, input TagsInput, changedBy string) ([]string, error) {
+	customerName, err := requireCustomer(input.CustomerName)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordRepo.ReplaceTags(ctx, customerName, tags, changedBy); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// AllTags 列出所有標籤與使用它的客戶數，供篩選使用
+func (s *CustomerRecordService) AllTags(ctx context.Context) ([]repository.TagCount, error) {
+	return s.recordRepo.CountTags(ctx)
+}
+
+// CustomersWithTags 返回同時擁有所有 tags 的客戶名
+func (s *CustomerRecordService) CustomersWithTags(ctx context.Context, tags []string) (map[string]bool, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.recordRepo.CustomersWithTags(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	customers := make(map[string]bool, len(names))
+	for _, name := range names {
+		customers[name] = true
+	}
+	return customers, nil
+}
+
+// Forms 列出客戶簽署的表單，kind 為空時列出全部
+func (s *CustomerRecordService) Forms(ctx context.Context, customerName, kind string) ([]models.CustomerForm, error) {
+	customerName, err := requireCustomer(customerName)
+	if err != nil {
+		return nil, err
+	}
+	if kind != "" && kind != models.CustomerFormHealth && kind != models.CustomerFormConsent {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.invalid_kind", kind)
+	}
+	return s.recordRepo.ListForms(ctx, customerName, kind)
+}
+
+// GetForm 取得表單，不存在時返回 NOT_FOUND
+func (s *CustomerRecordService) GetForm(ctx context.Context, id uint) (*models.CustomerForm, error) {
+	form, err := s.recordRepo.GetFormByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if form == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "customer_forms.not_found")
+	}
+	return form, nil
+}
+
+// AddForm 登錄一份客戶簽署的表單，recordedBy 為登入者
+// 療程同意書必須填寫療程並勾選同意；簽署時間不可晚於現在
+func (s *CustomerRecordService) AddForm(ctx context.Context, input FormInput, recordedBy string) (*models.CustomerForm, error) {
+	customerName, err := requireCustomer(input.CustomerName)
+	if err != nil {
+		return nil, err
+	}
+
+	form := &models.CustomerForm{
+		CustomerName: customerName,
+		Kind:         input.Kind,
+		RecordedBy:   recordedBy,
+	}
+	switch input.Kind {
+	case models.CustomerFormHealth:
+		if input.Health == nil || input.Consent != nil {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.answers_mismatch", input.Kind)
+		}
+		form.Health = input.Health
+	case models.CustomerFormConsent:
+		if input.Consent == nil || input.Health != nil {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.answers_mismatch", input.Kind)
+		}
+		if strings.TrimSpace(input.Consent.Treatment) == "" || !input.Consent.Agreed {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.consent_incomplete")
+		}
+		form.Consent = input.Consent
+	default:
+		return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.invalid_kind", input.Kind)
+	}
+
+	form.SignedName = strings.TrimSpace(input.SignedName)
+	if form.SignedName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.signature_required")
+	}
+	if input.SignedAt.IsZero() || input.SignedAt.After(time.Now().Add(signedAtTolerance)) {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "customer_forms.invalid_signed_at")
+	}
+	form.SignedAt = input.SignedAt
+
+	if err := s.recordRepo.CreateForm(ctx, form); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "已登錄客戶表單", "form_id", form.ID, "kind", form.Kind)
+	return form, nil
+}
+
+// requireCustomer 去除客戶名前後空白，為空時返回 INVALID_REQUEST
+func requireCustomer(customerName string) (string, error) {
+	customerName = strings.TrimSpace(customerName)
+	if customerName == "" {
+		return "", apperror.New(apperror.CodeInvalidRequest, "customer_records.customer_required")
+	}
+	return customerName, nil
+}
+
+// normalizeTags 去除前後空白並轉為小寫，去除重複後依名稱排序
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "customer_tags.invalid_tag", maxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > maxCustomerTags {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "customer_tags.too_many", maxCustomerTags)
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
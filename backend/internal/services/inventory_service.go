@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/notify"
+)
+
+// ProductInput 新增或修改商品的內容
+// Cost 為單位成本，進貨時會以移動平均自動更新，只在需要修正時提供；
+// 未提供成本或上架狀態時，新增分別為 0 與上架，修改維持不變
+type ProductInput struct {
+	SKU          string `json:"sku"`
+	Name         string `json:"name"`
+	Category     string `json:"category"`
+	Price        int64  `json:"price"`
+	Cost         *int64 `json:"cost"`
+	ReorderLevel int    `json:"reorder_level"`
+	Active       *bool  `json:"active"`
+}
+
+// StockMovementInput 手動登錄的庫存異動，零售售出由消費紀錄自動扣減
+// Type 為 purchase (進貨，Quantity 為正數，UnitCost 為進貨單價) 或 adjustment (盤點調整，Quantity 增加為正、減少為負)
+type StockMovementInput struct {
+	Type     string `json:"type"`
+	Quantity int    `json:"quantity"`
+	UnitCost int64  `json:"unit_cost"`
+	Note     string `json:"note"`
+}
+
+// ProductValuation 一項商品的庫存價值
+type ProductValuation struct {
+	ProductID   uint   `json:"product_id"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Stock       int    `json:"stock"`
+	UnitCost    int64  `json:"unit_cost"`
+	Value       int64  `json:"value"`        // 庫存 × 單位成本
+	RetailValue int64  `json:"retail_value"` // 庫存 × 售價
+}
+
+// InventoryValuation 庫存價值報表，只包含有庫存的商品
+type InventoryValuation struct {
+	AsOf        time.Time          `json:"as_of"`
+	TotalUnits  int                `json:"total_units"`
+	TotalValue  int64              `json:"total_value"`
+	RetailValue int64              `json:"retail_value"`
+	Items       []ProductValuation `json:"items"`
+}
+
+// InventoryService 維護零售商品、庫存異動與低庫存通知
+type InventoryService struct {
+	inventoryRepo *repository.InventoryRepository
+	config        configs.InventoryConfig
+	logger        *slog.Logger
+}
+
+// NewInventoryService 創建一個新的庫存服務
+func NewInventoryService(inventoryRepo *repository.InventoryRepository, config configs.InventoryConfig) *InventoryService {
+	return &InventoryService{
+		inventoryRepo: inventoryRepo,
+		config:        config,
+		logger:        logging.Component("inventory"),
+	}
+}
+
+// List 依條件列出商品
+func (s *InventoryService) List(ctx context.Context, filter repository.ProductFilter) ([]models.Product, error) {
+	return s.inventoryRepo.ListProducts(ctx, filter)
+}
+
+// Get 取得商品，不存在時返回 NOT_FOUND
+func (s *InventoryService) Get(ctx context.Context, id uint) (*models.Product, error) {
+	product, err := s.inventoryRepo.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "products.not_found")
+	}
+	return product, nil
+}
+
+// Create 新增商品，初始庫存為 0，以進貨或盤點調整登錄現有庫存
+func (s *InventoryService) Create(ctx context.Context, input ProductInput) (*models.Product, error) {
+	product := &models.Product{Active: input.Active == nil || *input.Active}
+	if err := s.apply(ctx, product, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.inventoryRepo.CreateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "已新增商品", "product_id", product.ID, "sku", product.SKU)
+	return product, nil
+}
+
+// Update 在鎖定商品的交易內修改商品資料，庫存不會變動；未提供成本時維持目前的平均成本
+func (s *InventoryService) Update(ctx context.Context, id uint, input ProductInput) (*models.Product, error) {
+	product, err := s.inventoryRepo.UpdateProduct(ctx, id, func(product *models.Product) error {
+		if err := s.apply(ctx, product, input); err != nil {
+			return err
+		}
+		if input.Active != nil {
+			product.Active = *input.Active
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "products.not_found")
+	}
+	return product, nil
+}
+
+// Movements 列出商品的庫存異動
+func (s *InventoryService) Movements(ctx context.Context, id uint, limit int) ([]models.StockMovement, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.inventoryRepo.ListMovements(ctx, id, limit)
+}
+
+// RecordMovement 登錄一筆進貨或盤點調整，調整後庫存為負時返回 CONFLICT
+func (s *InventoryService) RecordMovement(ctx context.Context, id uint, input StockMovementInput, createdBy string) (*models.StockMovement, error) {
+	switch input.Type {
+	case models.StockPurchase:
+		if input.Quantity <= 0 || input.UnitCost < 0 {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "inventory.invalid_purchase")
+		}
+	case models.StockAdjustment:
+		if input.Quantity == 0 {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "inventory.invalid_quantity")
+		}
+		if strings.TrimSpace(input.Note) == "" {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "inventory.note_required")
+		}
+	default:
+		return nil, apperror.New(apperror.CodeInvalidRequest, "inventory.invalid_movement_type", input.Type)
+	}
+
+	movement := &models.StockMovement{
+		ProductID: id,
+		Type:      input.Type,
+		Quantity:  input.Quantity,
+		UnitCost:  input.UnitCost,
+		Note:      strings.TrimSpace(input.Note),
+		CreatedBy: createdBy,
+	}
+	product, err := s.inventoryRepo.RecordMovement(ctx, movement)
+	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, apperror.Wrap(err, apperror.CodeConflict, "inventory.insufficient_stock")
+		}
+		return nil, err
+	}
+	if product == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "products.not_found")
+	}
+
+	s.logger.InfoContext(ctx, "已登錄庫存異動",
+		"product_id", product.ID, "type", movement.Type, "quantity", movement.Quantity, "stock", product.Stock)
+	return movement, nil
+}
+
+// LowStock 列出上架中且庫存已到補貨點的商品
+func (s *InventoryService) LowStock(ctx context.Context) ([]models.Product, error) {
+	return s.inventoryRepo.ListProducts(ctx, repository.ProductFilter{ActiveOnly: true, LowStockOnly: true})
+}
+
+// Valuation 依目前庫存與單位成本計算庫存價值
+func (s *InventoryService) Valuation(ctx context.Context) (*InventoryValuation, error) {
+	products, err := s.inventoryRepo.ListProducts(ctx, repository.ProductFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	return inventoryValuation(products, time.Now()), nil
+}
+
+// inventoryValuation 計算商品的庫存價值，略過沒有庫存的商品
+func inventoryValuation(products []models.Product, asOf time.Time) *InventoryValuation {
+	valuation := &InventoryValuation{
+		AsOf:  asOf,
+		Items: []ProductValuation{},
+	}
+	for _, product := range products {
+		if product.Stock == 0 {
+			continue
+		}
+		item := ProductValuation{
+			ProductID:   product.ID,
+			SKU:         product.SKU,
+			Name:        product.Name,
+			Category:    product.Category,
+			Stock:       product.Stock,
+			UnitCost:    product.Cost,
+			Value:       int64(product.Stock) * product.Cost,
+			RetailValue: int64(product.Stock) * product.Price,
+		}
+		valuation.Items = append(valuation.Items, item)
+		valuation.TotalUnits += item.Stock
+		valuation.TotalValue += item.Value
+		valuation.RetailValue += item.RetailValue
+	}
+	return valuation
+}
+
+// AlertLowStock 將新到達補貨點的商品彙整為一則通知加入通知佇列，由背景工作定期執行
+func (s *InventoryService) AlertLowStock(ctx context.Context) {
+	products, err := s.inventoryRepo.AlertLowStock(ctx, s.lowStockMessage)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "產生低庫存通知失敗", "error", err)
+		return
+	}
+	if len(products) > 0 {
+		s.logger.InfoContext(ctx, "已產生低庫存通知", "count", len(products), "channel", s.config.LowStockChannel)
+	}
+}
+
+// lowStockMessage 組合低庫存通知，未設定通知管道時返回 nil
+func (s *InventoryService) lowStockMessage(products []models.Product) *models.OutboxMessage {
+	if s.config.LowStockChannel == "" {
+		return nil
+	}
+
+	var body strings.Builder
+	body.WriteString("以下商品庫存已到補貨點：\n")
+	for _, product := range products {
+		fmt.Fprintf(&body, "%s %s：庫存 %d (補貨點 %d)\n", product.SKU, product.Name, product.Stock, product.ReorderLevel)
+	}
+
+	recipient := s.config.LowStockRecipient
+	return &models.OutboxMessage{
+		Kind:    models.OutboxKindLowStock,
+		Channel: s.config.LowStockChannel,
+		Recipient: notify.Recipient{
+			Name:       recipient.Name,
+			Email:      recipient.Email,
+			Phone:      recipient.Phone,
+			LineUserID: recipient.LineUserID,
+		},
+		Subject: "低庫存提醒",
+		Body:    body.String(),
+	}
+}
+
+// apply 驗證輸入並寫入商品，SKU 不可與其他商品重複
+func (s *InventoryService) apply(ctx context.Context, product *models.Product, input ProductInput) error {
+	sku := strings.TrimSpace(input.SKU)
+	if sku == "" {
+		return apperror.New(apperror.CodeInvalidRequest, "products.sku_required")
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return apperror.New(apperror.CodeInvalidRequest, "products.name_required")
+	}
+	if input.Price < 0 || (input.Cost != nil && *input.Cost < 0) {
+		return apperror.New(apperror.CodeInvalidRequest, "products.invalid_price")
+	}
+	if input.ReorderLevel < 0 {
+		return apperror.New(apperror.CodeInvalidRequest, "products.invalid_reorder_level")
+	}
+
+	existing, err := s.inventoryRepo.GetProductBySKU(ctx, sku)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != product.ID {
+		return apperror.New(apperror.CodeConflict, "products.duplicate_sku", sku)
+	}
+
+	product.SKU = sku
+	product.Name = name
+	product.Category = strings.TrimSpace(input.Category)
+	product.Price = input.Price
+	if input.Cost != nil {
+		product.Cost = *input.Cost
+	}
+	product.ReorderLevel = input.ReorderLevel
+	if !product.LowStock() {
+		product.LowStockAlertedAt = nil
+	}
+	return nil
+}
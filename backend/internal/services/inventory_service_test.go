@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/models"
+)
+
+func TestInventoryValuation(t *testing.T) {
+	asOf := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	products := []models.Product{
+		{ID: 1, SKU: "SERUM-30", Name: "精華液", Category: "保養", Price: 1290, Cost: 600, Stock: 3},
+		{ID: 2, SKU: "MASK-5", Name: "面膜", Category: "保養", Price: 350, Cost: 120, Stock: 0},
+		{ID: 3, SKU: "OIL-50", Name: "按摩油", Category: "耗材", Price: 800, Cost: 0, Stock: 4},
+		{ID: 4, SKU: "OLD-1", Name: "停售商品", Price: 500, Cost: 200, Stock: 2, Active: false},
+	}
+
+	valuation := inventoryValuation(products, asOf)
+
+	if !valuation.AsOf.Equal(asOf) {
+		t.Errorf("AsOf = %s, want %s", valuation.AsOf, asOf)
+	}
+	// 沒有庫存的商品不列出；下架但仍有庫存的商品仍計入
+	wantItems := []struct {
+		productID   uint
+		value       int64
+		retailValue int64
+	}{
+		{1, 1800, 3870},
+		{3, 0, 3200},
+		{4, 400, 1000},
+	}
+	if len(valuation.Items) != len(wantItems) {
+		t.Fatalf("len(Items) = %d, want %d", len(valuation.Items), len(wantItems))
+	}
+	for i, want := range wantItems {
+		item := valuation.Items[i]
+		if item.ProductID != want.productID || item.Value != want.value || item.RetailValue != want.retailValue {
+			t.Errorf("Items[%d] = product %d value %d retail %d, want product %d value %d retail %d",
+				i, item.ProductID, item.Value, item.RetailValue, want.productID, want.value, want.retailValue)
+		}
+	}
+	if valuation.TotalUnits != 9 || valuation.TotalValue != 2200 || valuation.RetailValue != 8070 {
+		t.Errorf("TotalUnits = %d, TotalValue = %d, RetailValue = %d, want 9, 2200, 8070",
+			valuation.TotalUnits, valuation.TotalValue, valuation.RetailValue)
+	}
+
+	empty := inventoryValuation(nil, asOf)
+	if empty.Items == nil || len(empty.Items) != 0 || empty.TotalValue != 0 {
+		t.Errorf("empty valuation = %+v, want no items", empty)
+	}
+}
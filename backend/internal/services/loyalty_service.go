@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+)
+
+// LoyaltySummary 客戶的點數餘額與會員等級
+type LoyaltySummary struct {
+	CustomerName string `json:"customer_name"`
+	Balance      int64  `json:"balance"`
+	// BalanceValue 點數目前可折抵的金額
+	BalanceValue int64 `json:"balance_value"`
+	// Tier 依近 12 個月消費總額判定的會員等級，未達任何等級時為空字串
+	Tier        string `json:"tier"`
+	AnnualSpend int64  `json:"annual_spend"`
+	// NextTier 下一個等級與升級還需要的消費金額，已是最高等級時省略
+	NextTier      string `json:"next_tier,omitempty"`
+	NextTierSpend int64  `json:"next_tier_spend,omitempty"`
+}
+
+// PointsAdjustInput 人工調整點數的內容，Points 為正數時增加、負數時扣除
+type PointsAdjustInput struct {
+	CustomerName string `json:"customer_name"`
+	Points       int64  `json:"points"`
+	Note         string `json:"note"`
+}
+
+// LoyaltyService 提供會員點數與等級的業務邏輯
+type LoyaltyService struct {
+	loyaltyRepo *repository.LoyaltyRepository
+	visitRepo   *repository.VisitRepository
+	config      configs.LoyaltyConfig
+	location    *time.Location
+	logger      *slog.Logger
+}
+
+// NewLoyaltyService 創建一個新的會員點數服務，location 為營業時區
+func NewLoyaltyService(loyaltyRepo *repository.LoyaltyRepository, visitRepo *repository.VisitRepository, config configs.LoyaltyConfig, location *time.Location) *LoyaltyService {
+	return &LoyaltyService{
+		loyaltyRepo: loyaltyRepo,
+		visitRepo:   visitRepo,
+		config:      config,
+		location:    location,
+		logger:      logging.Component("loyalty"),
+	}
+}
+
+// Summary 取得客戶的點數餘額與會員等級，尚無點數帳戶時餘額為 0
+func (s *LoyaltyService) Summary(ctx context.Context, customerName string) (*LoyaltySummary, error) {
+	customerName = strings.TrimSpace(customerName)
+	if customerName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "loyalty.customer_required")
+	}
+
+	account, err := s.loyaltyRepo.GetAccount(ctx, customerName)
+	if err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, customerName, account)
+}
+
+// Ledger 取得客戶的點數餘額與異動紀錄 (新的在前)
+func (s *LoyaltyService) Ledger(ctx context.Context, customerName string) (*LoyaltySummary, []models.PointsLedgerEntry, error) {
+	customerName = strings.TrimSpace(customerName)
+	if customerName == "" {
+		return nil, nil, apperror.New(apperror.CodeInvalidRequest, "loyalty.customer_required")
+	}
+
+	account, err := s.loyaltyRepo.GetAccount(ctx, customerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary, err := s.summary(ctx, customerName, account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := []models.PointsLedgerEntry{}
+	if account != nil {
+		if entries, err = s.loyaltyRepo.ListLedger(ctx, account.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return summary, entries, nil
+}
+
+// Adjust 人工調整客戶點數，扣除後餘額不足時返回 CONFLICT
+func (s *LoyaltyService) Adjust(ctx context.Context, input PointsAdjustInput, createdBy string) (*LoyaltySummary, error) {
+	customerName := strings.TrimSpace(input.CustomerName)
+	if customerName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "loyalty.customer_required")
+	}
+	if input.Points == 0 {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "loyalty.invalid_points")
+	}
+
+	account, err := s.loyaltyRepo.Adjust(ctx, customerName, input.Points, input.Note, createdBy)
+	if err != nil {
+		return nil, balanceError(err)
+	}
+
+	s.logger.InfoContext(ctx, "已調整點數", "account_id", account.ID, "points", input.Points, "balance", account.Balance)
+	return s.summary(ctx, customerName, account)
+}
+
+// ExpireDue 將超過有效月數的點數記為失效，由背景工作定期執行
+func (s *LoyaltyService) ExpireDue(ctx context.Context) {
+	if s.config.ExpiryMonths == 0 {
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, -s.config.ExpiryMonths, 0)
+	count, err := s.loyaltyRepo.ExpirePoints(ctx, cutoff)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "處理到期點數失敗", "error", err)
+		return
+	}
+	if count > 0 {
+		s.logger.InfoContext(ctx, "已處理到期點數", "accounts", count)
+	}
+}
+
+// applyToVisit 計算消費紀錄的點數折抵與本次累積的點數，點數於寫入消費紀錄的交易內異動
+func (s *LoyaltyService) applyToVisit(ctx context.Context, visit *models.Visit, pointsRedeemed int64) error {
+	annualSpend, err := s.annualSpend(ctx, visit.CustomerName)
+	if err != nil {
+		return err
+	}
+	return s.applyPoints(visit, pointsRedeemed, annualSpend)
+}
+
+// applyPoints 依客戶近 12 個月消費總額計算點數折抵與累積點數
+// 折抵金額不可超過總額；累積點數以扣除折抵後的金額與客戶目前等級的倍率計算，不足一點的部分捨去
+func (s *LoyaltyService) applyPoints(visit *models.Visit, pointsRedeemed, annualSpend int64) error {
+	if pointsRedeemed < 0 || pointsRedeemed*s.config.PointValue > visit.Total {
+		return apperror.New(apperror.CodeInvalidRequest, "visits.invalid_points")
+	}
+
+	multiplier := 100
+	if tier := s.tier(annualSpend); tier != nil {
+		multiplier = tier.EarnMultiplier
+	}
+
+	visit.PointsRedeemed = pointsRedeemed
+	visit.PointsDiscount = pointsRedeemed * s.config.PointValue
+	visit.PointsEarned = (visit.Total - visit.PointsDiscount) / s.config.EarnSpend * int64(multiplier) / 100
+	return nil
+}
+
+// summary 組合點數餘額與會員等級，account 為 nil 時餘額為 0
+func (s *LoyaltyService) summary(ctx context.Context, customerName string, account *models.LoyaltyAccount) (*LoyaltySummary, error) {
+	annualSpend, err := s.annualSpend(ctx, customerName)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &LoyaltySummary{
+		CustomerName: customerName,
+		AnnualSpend:  annualSpend,
+	}
+	if account != nil {
+		summary.Balance = account.Balance
+		summary.BalanceValue = account.Balance * s.config.PointValue
+	}
+	if tier := s.tier(annualSpend); tier != nil {
+		summary.Tier = tier.Name
+	}
+	for _, tier := range s.config.Tiers {
+		if tier.MinAnnualSpend > annualSpend {
+			summary.NextTier = tier.Name
+			summary.NextTierSpend = tier.MinAnnualSpend - annualSpend
+			break
+		}
+	}
+	return summary, nil
+}
+
+// annualSpend 加總客戶近 12 個月 (含今天) 的消費總額
+func (s *LoyaltyService) annualSpend(ctx context.Context, customerName string) (int64, error) {
+	today, err := parseDate(time.Now().In(s.location).Format(dateLayout))
+	if err != nil {
+		return 0, err
+	}
+	return s.visitRepo.SumCustomerTotals(ctx, customerName, today.AddDate(-1, 0, 1))
+}
+
+// tier 返回消費總額達到的最高等級，未達任何等級時返回 nil
+func (s *LoyaltyService) tier(annualSpend int64) *configs.LoyaltyTier {
+	var reached *configs.LoyaltyTier
+	for i := range s.config.Tiers {
+		if s.config.Tiers[i].MinAnnualSpend <= annualSpend {
+			reached = &s.config.Tiers[i]
+		}
+	}
+	return reached
+}
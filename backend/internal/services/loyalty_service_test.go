@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"backend/internal/models"
+	"backend/pkg/configs"
+)
+
+func newTestLoyaltyService() *LoyaltyService {
+	return &LoyaltyService{config: configs.LoyaltyConfig{
+		EarnSpend:  100,
+		PointValue: 1,
+		Tiers: []configs.LoyaltyTier{
+			{Name: "silver", MinAnnualSpend: 30000, EarnMultiplier: 120},
+			{Name: "gold", MinAnnualSpend: 80000, EarnMultiplier: 150},
+		},
+	}}
+}
+
+func TestLoyaltyTier(t *testing.T) {
+	s := newTestLoyaltyService()
+
+	tests := []struct {
+		annualSpend int64
+		want        string // 空字串代表未達任何等級
+	}{
+		{0, ""},
+		{29999, ""},
+		{30000, "silver"},
+		{79999, "silver"},
+		{80000, "gold"},
+		{500000, "gold"},
+	}
+
+	for _, tt := range tests {
+		var got string
+		if tier := s.tier(tt.annualSpend); tier != nil {
+			got = tier.Name
+		}
+		if got != tt.want {
+			t.Errorf("tier(%d) = %q, want %q", tt.annualSpend, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int64
+		redeemed     int64
+		annualSpend  int64
+		wantDiscount int64
+		wantEarned   int64
+		wantErr      bool
+	}{
+		{"每 100 元一點", 2500, 0, 0, 0, 25, false},
+		{"不足 100 元的部分不計點", 2599, 0, 0, 0, 25, false},
+		{"以折抵後的金額計點", 2500, 600, 0, 600, 19, false},
+		{"剛達到銀卡倍率", 2500, 0, 30000, 0, 30, false},
+		{"差一元未達銀卡", 2500, 0, 29999, 0, 25, false},
+		{"剛達到金卡倍率", 2500, 0, 80000, 0, 37, false}, // 25 × 1.5 = 37.5 捨去
+		{"全額以點數折抵不累積點數", 2500, 2500, 80000, 2500, 0, false},
+		{"全額套票扣抵的消費不累積點數", 0, 0, 80000, 0, 0, false},
+		{"未滿 100 元的消費不累積點數", 99, 0, 0, 0, 0, false},
+		{"折抵超過總額", 2500, 2501, 0, 0, 0, true},
+		{"總額為 0 時不可折抵", 0, 1, 0, 0, 0, true},
+		{"折抵點數不可為負數", 2500, -1, 0, 0, 0, true},
+	}
+
+	s := newTestLoyaltyService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visit := &models.Visit{Total: tt.total}
+			err := s.applyPoints(visit, tt.redeemed, tt.annualSpend)
+			if tt.wantErr {
+				if key := errorKey(err); key != "visits.invalid_points" {
+					t.Fatalf("applyPoints error = %v, want visits.invalid_points", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyPoints error = %v", err)
+			}
+			if visit.PointsRedeemed != tt.redeemed || visit.PointsDiscount != tt.wantDiscount || visit.PointsEarned != tt.wantEarned {
+				t.Errorf("redeemed = %d, discount = %d, earned = %d, want %d, %d, %d",
+					visit.PointsRedeemed, visit.PointsDiscount, visit.PointsEarned, tt.redeemed, tt.wantDiscount, tt.wantEarned)
+			}
+		})
+	}
+
+	// 每點折抵多元時以折抵金額比較總額
+	s.config.PointValue = 10
+	visit := &models.Visit{Total: 2500}
+	if err := s.applyPoints(visit, 250, 0); err != nil || visit.PointsDiscount != 2500 || visit.PointsEarned != 0 {
+		t.Errorf("applyPoints(250 × 10) = %v, discount %d, earned %d", err, visit.PointsDiscount, visit.PointsEarned)
+	}
+	if err := s.applyPoints(&models.Visit{Total: 2500}, 251, 0); errorKey(err) != "visits.invalid_points" {
+		t.Errorf("applyPoints(251 × 10) error = %v, want visits.invalid_points", err)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
+	"backend/pkg/notify"
+)
+
+// outboxLease 取出的通知在這段時間內不會被其他執行個體重複取出，需長於發送一批通知所需的時間
+const outboxLease = 5 * time.Minute
+
+// OutboxService 發送通知佇列中的通知，失敗時以指數退避重試
+type OutboxService struct {
+	outboxRepo *repository.OutboxRepository
+	notifiers  map[string]notify.Notifier
+	config     configs.OutboxConfig
+	logger     *slog.Logger
+}
+
+// NewOutboxService 創建一個新的通知佇列服務，notifiers 的鍵為管道名稱
+func NewOutboxService(outboxRepo *repository.OutboxRepository, notifiers map[string]notify.Notifier, config configs.OutboxConfig) *OutboxService {
+	return &OutboxService{
+		outboxRepo: outboxRepo,
+		notifiers:  notifiers,
+		config:     config,
+		logger:     logging.Component("outbox"),
+	}
+}
+
+// List 依條件查詢通知
+func (s *OutboxService) List(ctx context.Context, filter repository.OutboxFilter) ([]models.OutboxMessage, error) {
+	return s.outboxRepo.List(ctx, filter)
+}
+
+// Retry 重新發送一則發送失敗 (dead) 的通知，通知不存在時返回 NOT_FOUND，不是 dead 狀態時返回 CONFLICT
+func (s *OutboxService) Retry(ctx context.Context, id uint) (*models.OutboxMessage, error) {
+	msg, err := s.outboxRepo.Retry(ctx, id)
+	if errors.Is(err, repository.ErrOutboxNotDead) {
+		return nil, apperror.New(apperror.CodeConflict, "outbox.not_dead")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "outbox.not_found")
+	}
+
+	s.logger.InfoContext(ctx, "已重新排入通知", "outbox_id", msg.ID, "channel", msg.Channel)
+	return msg, nil
+}
+
+// DispatchDue 發送到期的通知，由背景工作定期執行
+func (s *OutboxService) DispatchDue(ctx context.Context) {
+	messages, err := s.outboxRepo.ClaimDue(ctx, time.Now(), s.config.BatchSize, outboxLease)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "取出待發送通知失敗", "error", err)
+		return
+	}
+
+	for i := range messages {
+		if ctx.Err() != nil {
+			// 未發送的通知會在 lease 過後重新取出
+			return
+		}
+		s.deliver(ctx, &messages[i])
+	}
+}
+
+// 通知發送結果
+const (
+	deliverySent  = "sent"
+	deliveryRetry = "retry"
+	deliveryDead  = "dead"
+)
+
+// deliver 發送一則通知並記錄結果
+func (s *OutboxService) deliver(ctx context.Context, msg *models.OutboxMessage) {
+	result, delay, err := s.send(ctx, msg)
+
+	// 發送結果與背景工作的取消無關，避免已送出的通知因取消而重複發送
+	ctx = context.WithoutCancel(ctx)
+	attempts := msg.Attempts + 1
+
+	switch result {
+	case deliverySent:
+		err = s.outboxRepo.MarkSent(ctx, msg.ID)
+	case deliveryDead:
+		s.logger.WarnContext(ctx, "通知發送失敗，停止重試",
+			"outbox_id", msg.ID, "kind", msg.Kind, "channel", msg.Channel, "attempts", attempts, "error", err)
+		err = s.outboxRepo.MarkDead(ctx, msg.ID, err.Error())
+	default:
+		s.logger.InfoContext(ctx, "通知發送失敗，稍後重試",
+			"outbox_id", msg.ID, "kind", msg.Kind, "channel", msg.Channel, "attempts", attempts, "delay", delay, "error", err)
+		err = s.outboxRepo.MarkRetry(ctx, msg.ID, err.Error(), time.Now().Add(delay))
+	}
+	metrics.ObserveOutboxDelivery(msg.Channel, result)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "記錄通知發送結果失敗", "outbox_id", msg.ID, "error", err)
+	}
+}
+
+// send 以通知的管道發送，返回結果、重試間隔 (只有 retry 有) 與發送錯誤
+// 管道未設定或收件人缺少聯絡方式時重試也不會成功，與達到最多嘗試次數相同地直接為 dead
+func (s *OutboxService) send(ctx context.Context, msg *models.OutboxMessage) (string, time.Duration, error) {
+	notifier, ok := s.notifiers[msg.Channel]
+	if !ok {
+		return deliveryDead, 0, fmt.Errorf("通知管道 %s 未設定", msg.Channel)
+	}
+
+	err := notifier.Send(ctx, msg.Message())
+	attempts := msg.Attempts + 1
+	switch {
+	case err == nil:
+		return deliverySent, 0, nil
+	case errors.Is(err, notify.ErrNoAddress) || attempts >= s.config.MaxAttempts:
+		return deliveryDead, 0, err
+	default:
+		return deliveryRetry, s.backoff(attempts), err
+	}
+}
+
+// backoff 返回第 attempts 次失敗後的重試間隔：BaseBackoff×2^(attempts-1)，最多 MaxBackoff
+func (s *OutboxService) backoff(attempts int) time.Duration {
+	delay := time.Duration(s.config.BaseBackoff) * time.Second
+	limit := time.Duration(s.config.MaxBackoff) * time.Second
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/pkg/configs"
+	"backend/pkg/notify"
+)
+
+// fakeNotifier 記錄送出的通知並返回預先設定的錯誤
+type fakeNotifier struct {
+	channel string
+	err     error
+	sent    []notify.Message
+}
+
+func (n *fakeNotifier) Channel() string {
+	return n.channel
+}
+
+func (n *fakeNotifier) Send(_ context.Context, msg notify.Message) error {
+	n.sent = append(n.sent, msg)
+	return n.err
+}
+
+func newTestOutboxService(notifiers ...*fakeNotifier) *OutboxService {
+	byChannel := make(map[string]notify.Notifier, len(notifiers))
+	for _, n := range notifiers {
+		byChannel[n.channel] = n
+	}
+	return NewOutboxService(nil, byChannel, configs.OutboxConfig{
+		MaxAttempts: 5,
+		BaseBackoff: 30,
+		MaxBackoff:  600,
+	})
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	s := newTestOutboxService()
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, 60 * time.Second},
+		{3, 120 * time.Second},
+		{4, 240 * time.Second},
+		{5, 480 * time.Second},
+		{6, 600 * time.Second}, // 960 秒超過上限
+		{20, 600 * time.Second},
+		{100, 600 * time.Second}, // 次數很大時不會溢位
+	}
+
+	for _, tt := range tests {
+		if got := s.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOutboxSend(t *testing.T) {
+	errUpstream := errors.New("SMS 服務暫時無法使用")
+
+	tests := []struct {
+		name      string
+		notifier  *fakeNotifier
+		channel   string
+		attempts  int // 這次發送前已失敗的次數
+		want      string
+		wantDelay time.Duration
+		wantErr   error
+		wantSent  int
+	}{
+		{
+			name:     "發送成功",
+			notifier: &fakeNotifier{channel: notify.ChannelSMS},
+			channel:  notify.ChannelSMS,
+			want:     deliverySent,
+			wantSent: 1,
+		},
+		{
+			name:      "第一次失敗稍後重試",
+			notifier:  &fakeNotifier{channel: notify.ChannelSMS, err: errUpstream},
+			channel:   notify.ChannelSMS,
+			want:      deliveryRetry,
+			wantDelay: 30 * time.Second,
+			wantErr:   errUpstream,
+			wantSent:  1,
+		},
+		{
+			name:      "最後一次之前仍重試",
+			notifier:  &fakeNotifier{channel: notify.ChannelSMS, err: errUpstream},
+			channel:   notify.ChannelSMS,
+			attempts:  3,
+			want:      deliveryRetry,
+			wantDelay: 240 * time.Second,
+			wantErr:   errUpstream,
+			wantSent:  1,
+		},
+		{
+			name:     "達到最多嘗試次數",
+			notifier: &fakeNotifier{channel: notify.ChannelSMS, err: errUpstream},
+			channel:  notify.ChannelSMS,
+			attempts: 4,
+			want:     deliveryDead,
+			wantErr:  errUpstream,
+			wantSent: 1,
+		},
+		{
+			name:     "收件人缺少聯絡方式直接停止",
+			notifier: &fakeNotifier{channel: notify.ChannelSMS, err: fmt.Errorf("sms: %w", notify.ErrNoAddress)},
+			channel:  notify.ChannelSMS,
+			want:     deliveryDead,
+			wantErr:  notify.ErrNoAddress,
+			wantSent: 1,
+		},
+		{
+			name:     "未設定的管道不發送並直接停止",
+			notifier: &fakeNotifier{channel: notify.ChannelSMS},
+			channel:  notify.ChannelLINE,
+			want:     deliveryDead,
+			wantSent: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestOutboxService(tt.notifier)
+			msg := &models.OutboxMessage{
+				Channel:   tt.channel,
+				Recipient: notify.Recipient{Name: "王小明", Phone: "0912345678"},
+				Body:      "好久不見",
+				Attempts:  tt.attempts,
+			}
+
+			result, delay, err := s.send(context.Background(), msg)
+			if result != tt.want || delay != tt.wantDelay {
+				t.Errorf("send = %s after %s, want %s after %s", result, delay, tt.want, tt.wantDelay)
+			}
+			switch {
+			case tt.want == deliverySent && err != nil:
+				t.Errorf("send error = %v, want nil", err)
+			case tt.want != deliverySent && err == nil:
+				t.Error("send error = nil, want an error")
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("send error = %v, want %v", err, tt.wantErr)
+			}
+			if len(tt.notifier.sent) != tt.wantSent {
+				t.Errorf("notifier received %d messages, want %d", len(tt.notifier.sent), tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// PackageInput 購買課程套票的內容
+// ServiceID 為空時套票可扣抵任何服務項目；ExpiresAt 為最後可使用的日期，未提供時依預設有效月數計算
+type PackageInput struct {
+	CustomerName  string `json:"customer_name"`
+	Name          string `json:"name"`
+	ServiceID     *uint  `json:"service_id"`
+	TotalSessions int    `json:"total_sessions"`
+	Price         int64  `json:"price"`
+	ExpiresAt     string `json:"expires_at"`
+}
+
+// TopUpInput 儲值的內容
+type TopUpInput struct {
+	CustomerName string `json:"customer_name"`
+	Amount       int64  `json:"amount"`
+	Note         string `json:"note"`
+}
+
+// PrepaidService 提供課程套票與儲值金的業務邏輯
+type PrepaidService struct {
+	prepaidRepo           *repository.PrepaidRepository
+	serviceRepo           *repository.ServiceRepository
+	packageValidityMonths int
+	walletValidityMonths  int
+	location              *time.Location
+	logger                *slog.Logger
+}
+
+// NewPrepaidService 創建一個新的套票與儲值金服務
+// 有效月數為 0 時表示不會到期，location 為營業時區
+func NewPrepaidService(prepaidRepo *repository.PrepaidRepository, serviceRepo *repository.ServiceRepository, packageValidityMonths, walletValidityMonths int, location *time.Location) *PrepaidService {
+	return &PrepaidService{
+		prepaidRepo:           prepaidRepo,
+		serviceRepo:           serviceRepo,
+		packageValidityMonths: packageValidityMonths,
+		walletValidityMonths:  walletValidityMonths,
+		location:              location,
+		logger:                logging.Component("prepaid"),
+	}
+}
+
+// ListPackages 列出客戶的套票，customerName 為空時列出全部
+func (s *PrepaidService) ListPackages(ctx context.Context, customerName string) ([]models.Package, error) {
+	return s.prepaidRepo.ListPackages(ctx, customerName)
+}
+
+// GetPackage 取得套票，不存在時返回 NOT_FOUND
+func (s *PrepaidService) GetPackage(ctx context.Context, id uint) (*models.Package, error) {
+	pkg, err := s.prepaidRepo.GetPackageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "packages.not_found")
+	}
+	return pkg, nil
+}
+
+// CreatePackage 為客戶新增套票
+func (s *PrepaidService) CreatePackage(ctx context.Context, input PackageInput, createdBy string) (*models.Package, error) {
+	customerName := strings.TrimSpace(input.CustomerName)
+	if customerName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "packages.customer_required")
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "packages.name_required")
+	}
+	if input.TotalSessions <= 0 {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "packages.invalid_sessions")
+	}
+	if input.Price < 0 {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "packages.invalid_price")
+	}
+
+	now := time.Now()
+	pkg := &models.Package{
+		CustomerName:      customerName,
+		Name:              name,
+		TotalSessions:     input.TotalSessions,
+		RemainingSessions: input.TotalSessions,
+		Price:             input.Price,
+		Status:            models.PackageStatusActive,
+		PurchasedAt:       now,
+		CreatedBy:         createdBy,
+	}
+
+	if input.ServiceID != nil {
+		svc, err := s.serviceRepo.GetServiceByID(ctx, *input.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if svc == nil {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "packages.invalid_service")
+		}
+		pkg.ServiceID = &svc.ID
+	}
+
+	expiresAt, err := s.packageExpiry(input.ExpiresAt, now)
+	if err != nil {
+		return nil, err
+	}
+	pkg.ExpiresAt = expiresAt
+
+	if err := s.prepaidRepo.CreatePackage(ctx, pkg); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "已新增套票", "package_id", pkg.ID, "sessions", pkg.TotalSessions)
+	return pkg, nil
+}
+
+// packageExpiry 計算套票的到期時間，value 為到期日，當天仍可使用，於營業時區的隔天零時到期
+// 未提供到期日時依預設有效月數計算，有效月數為 0 時返回 nil 表示不會到期
+func (s *PrepaidService) packageExpiry(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		if s.packageValidityMonths <= 0 {
+			return nil, nil
+		}
+		expiresAt := now.AddDate(0, s.packageValidityMonths, 0)
+		return &expiresAt, nil
+	}
+
+	date, err := parseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, s.location)
+	if !expiresAt.After(now) {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "packages.invalid_expiry")
+	}
+	return &expiresAt, nil
+}
+
+// PackageLedger 取得套票與其堂數異動紀錄
+func (s *PrepaidService) PackageLedger(ctx context.Context, id uint) (*models.Package, []models.PackageLedgerEntry, error) {
+	pkg, err := s.GetPackage(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := s.prepaidRepo.ListPackageLedger(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg, entries, nil
+}
+
+// GetWallet 取得客戶的儲值金帳戶，尚未儲值過時返回 NOT_FOUND
+func (s *PrepaidService) GetWallet(ctx context.Context, customerName string) (*models.Wallet, error) {
+	customerName = strings.TrimSpace(customerName)
+	if customerName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "wallets.customer_required")
+	}
+
+	wallet, err := s.prepaidRepo.GetWalletByCustomer(ctx, customerName)
+	if err != nil {
+		return nil, err
+	}
+	if wallet == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "wallets.not_found")
+	}
+	return wallet, nil
+}
+
+// TopUp 為客戶儲值，有設定有效月數時到期日自本次儲值起重新計算
+func (s *PrepaidService) TopUp(ctx context.Context, input TopUpInput, createdBy string) (*models.Wallet, error) {
+	customerName := strings.TrimSpace(input.CustomerName)
+	if customerName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "wallets.customer_required")
+	}
+	if input.Amount <= 0 {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "wallets.invalid_amount")
+	}
+
+	var expiresAt *time.Time
+	if s.walletValidityMonths > 0 {
+		t := time.Now().AddDate(0, s.walletValidityMonths, 0)
+		expiresAt = &t
+	}
+
+	wallet, err := s.prepaidRepo.TopUpWallet(ctx, customerName, input.Amount, expiresAt, input.Note, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "已儲值", "wallet_id", wallet.ID, "amount", input.Amount, "balance", wallet.Balance)
+	return wallet, nil
+}
+
+// WalletLedger 取得儲值金帳戶與其異動紀錄
+func (s *PrepaidService) WalletLedger(ctx context.Context, id uint) (*models.Wallet, []models.WalletLedgerEntry, error) {
+	wallet, err := s.prepaidRepo.GetWalletByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if wallet == nil {
+		return nil, nil, apperror.New(apperror.CodeNotFound, "wallets.not_found")
+	}
+
+	entries, err := s.prepaidRepo.ListWalletLedger(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wallet, entries, nil
+}
+
+// ExpireDue 將已到期的套票與儲值金歸零，由背景工作定期執行
+func (s *PrepaidService) ExpireDue(ctx context.Context) {
+	now := time.Now()
+
+	packages, err := s.prepaidRepo.ExpirePackages(ctx, now)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "處理到期套票失敗", "error", err)
+	} else if packages > 0 {
+		s.logger.InfoContext(ctx, "已處理到期套票", "count", packages)
+	}
+
+	wallets, err := s.prepaidRepo.ExpireWallets(ctx, now)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "處理到期儲值金失敗", "error", err)
+	} else if wallets > 0 {
+		s.logger.InfoContext(ctx, "已處理到期儲值金", "count", wallets)
+	}
+}
+
+// balanceError 將套票無法使用、儲值金、點數或庫存不足轉為 CONFLICT，其餘錯誤原樣返回
+func balanceError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrPackageUnavailable):
+		return apperror.Wrap(err, apperror.CodeConflict, "packages.unavailable")
+	case errors.Is(err, repository.ErrInsufficientBalance):
+		return apperror.Wrap(err, apperror.CodeConflict, "wallets.insufficient_balance")
+	case errors.Is(err, repository.ErrInsufficientPoints):
+		return apperror.Wrap(err, apperror.CodeConflict, "loyalty.insufficient_points")
+	case errors.Is(err, repository.ErrInsufficientStock):
+		return apperror.Wrap(err, apperror.CodeConflict, "inventory.insufficient_stock")
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+)
+
+func TestPackageExpiry(t *testing.T) {
+	location := time.FixedZone("Asia/Taipei", 8*60*60)
+	// 台北時間 2024-03-15 10:00
+	now := time.Date(2024, 3, 15, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		value          string
+		validityMonths int
+		want           time.Time // 零值代表不會到期
+		wantKey        string
+	}{
+		{"到期日當天結束時到期", "2024-06-30", 0, time.Date(2024, 7, 1, 0, 0, 0, 0, location), ""},
+		{"到期日優先於預設有效月數", "2024-06-30", 12, time.Date(2024, 7, 1, 0, 0, 0, 0, location), ""},
+		{"到期日為今天仍可建立", "2024-03-15", 0, time.Date(2024, 3, 16, 0, 0, 0, 0, location), ""},
+		{"到期日為昨天", "2024-03-14", 0, time.Time{}, "packages.invalid_expiry"},
+		{"日期格式錯誤", "2024/06/30", 0, time.Time{}, "request.invalid_date"},
+		{"未提供到期日時依預設有效月數", "", 6, now.AddDate(0, 6, 0), ""},
+		{"未設定有效月數時不會到期", "", 0, time.Time{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PrepaidService{packageValidityMonths: tt.validityMonths, location: location}
+			got, err := s.packageExpiry(tt.value, now)
+			if tt.wantKey != "" {
+				if key := errorKey(err); key != tt.wantKey {
+					t.Fatalf("packageExpiry error = %v, want %s", err, tt.wantKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("packageExpiry error = %v", err)
+			}
+			if tt.want.IsZero() {
+				if got != nil {
+					t.Errorf("packageExpiry = %s, want nil", got)
+				}
+				return
+			}
+			if got == nil || !got.Equal(tt.want) {
+				t.Errorf("packageExpiry = %v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 營業時區的隔天零時，而非 UTC 的隔天零時
+	s := &PrepaidService{location: location}
+	got, err := s.packageExpiry("2024-06-30", now)
+	if err != nil || !got.Equal(time.Date(2024, 6, 30, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("packageExpiry = %v, %v, want 2024-06-30 16:00 UTC", got, err)
+	}
+}
+
+func TestBalanceError(t *testing.T) {
+	other := errors.New("連線中斷")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode apperror.Code
+		wantKey  string
+	}{
+		{"套票無法使用", repository.ErrPackageUnavailable, apperror.CodeConflict, "packages.unavailable"},
+		{"儲值金不足", repository.ErrInsufficientBalance, apperror.CodeConflict, "wallets.insufficient_balance"},
+		{"點數不足", repository.ErrInsufficientPoints, apperror.CodeConflict, "loyalty.insufficient_points"},
+		{"庫存不足", repository.ErrInsufficientStock, apperror.CodeConflict, "inventory.insufficient_stock"},
+		{"包裝過的錯誤", fmt.Errorf("扣抵失敗: %w", repository.ErrInsufficientBalance), apperror.CodeConflict, "wallets.insufficient_balance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var appErr *apperror.Error
+			if !errors.As(balanceError(tt.err), &appErr) {
+				t.Fatalf("balanceError(%v) is not an API error", tt.err)
+			}
+			if appErr.Code != tt.wantCode || appErr.MessageKey != tt.wantKey {
+				t.Errorf("balanceError = %s %s, want %s %s", appErr.Code, appErr.MessageKey, tt.wantCode, tt.wantKey)
+			}
+			if !errors.Is(appErr, tt.err) {
+				t.Errorf("balanceError dropped the cause %v", tt.err)
+			}
+		})
+	}
+
+	if got := balanceError(other); got != other {
+		t.Errorf("balanceError(%v) = %v, want the error unchanged", other, got)
+	}
+	if got := balanceError(nil); got != nil {
+		t.Errorf("balanceError(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/configs"
+	"backend/pkg/logging"
+	"backend/pkg/notify"
+)
+
+// 提醒的電子郵件主旨
+var reminderSubjects = map[string]string{
+	models.ReminderBirthday:     "生日快樂",
+	models.ReminderReactivation: "好久不見",
+}
+
+// ReminderCandidate 一位需要提醒的客戶
+type ReminderCandidate struct {
+	Kind         string `json:"kind"`
+	CustomerName string `json:"customer_name"`
+	Phone        string `json:"phone,omitempty"`
+	// Birthday 生日 (MM-DD) 與距今天數，只有生日提醒有
+	Birthday  string `json:"birthday,omitempty"`
+	DaysUntil int    `json:"days_until"`
+	// LastVisit 最後消費日期 (YYYY-MM-DD) 與距今天數，只有回流提醒有
+	LastVisit string `json:"last_visit,omitempty"`
+	DaysSince int    `json:"days_since"`
+
+	periodKey string
+	recipient notify.Recipient
+}
+
+// ReminderLists 某一天的提醒名單
+type ReminderLists struct {
+	Date         string              `json:"date"`
+	Birthdays    []ReminderCandidate `json:"birthdays"`
+	Reactivation []ReminderCandidate `json:"reactivation"`
+}
+
+// ReminderRunResult 一次執行的結果統計
+// Queued 為加入通知佇列的提醒數，實際發送結果見提醒紀錄的 delivery
+type ReminderRunResult struct {
+	Date        string `json:"date"`
+	Channel     string `json:"channel"`
+	Queued      int    `json:"queued"`
+	Skipped     int    `json:"skipped"`
+	AlreadySent int    `json:"already_sent"`
+}
+
+// ReminderService 產生生日與回流提醒名單並加入通知佇列
+type ReminderService struct {
+	source       repository.CustomerSource
+	visitRepo    *repository.VisitRepository
+	reminderRepo *repository.ReminderRepository
+	channel      string
+	config       configs.RemindersConfig
+	location     *time.Location
+	logger       *slog.Logger
+}
+
+// NewReminderService 創建一個新的提醒服務
+// channel 為已設定的通知管道，為空字串時只能預覽名單；location 為營業時區
+func NewReminderService(source repository.CustomerSource, visitRepo *repository.VisitRepository, reminderRepo *repository.ReminderRepository, channel string, config configs.RemindersConfig, location *time.Location) *ReminderService {
+	return &ReminderService{
+		source:       source,
+		visitRepo:    visitRepo,
+		reminderRepo: reminderRepo,
+		channel:      channel,
+		config:       config,
+		location:     location,
+		logger:       logging.Component("reminders"),
+	}
+}
+
+// Preview 產生今天的提醒名單，不發送
+func (s *ReminderService) Preview(ctx context.Context) (*ReminderLists, error) {
+	return s.lists(ctx, s.today())
+}
+
+// Run 產生今天的提醒名單並加入通知佇列，已提醒過的客戶會略過
+func (s *ReminderService) Run(ctx context.Context) (*ReminderRunResult, error) {
+	if s.channel == "" {
+		return nil, apperror.New(apperror.CodeConflict, "reminders.channel_unavailable", s.config.Channel)
+	}
+
+	today := s.today()
+	lists, err := s.lists(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReminderRunResult{Date: lists.Date, Channel: s.channel}
+	candidates := append(lists.Birthdays, lists.Reactivation...)
+	for _, candidate := range candidates {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := s.queue(ctx, candidate, result); err != nil {
+			return result, err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "已產生提醒",
+		"date", result.Date, "channel", result.Channel, "queued", result.Queued,
+		"skipped", result.Skipped, "already_sent", result.AlreadySent)
+	return result, nil
+}
+
+// RunScheduled 供背景工作定期執行的 Run，錯誤只記錄於日誌
+func (s *ReminderService) RunScheduled(ctx context.Context) {
+	if _, err := s.Run(ctx); err != nil {
+		s.logger.ErrorContext(ctx, "產生提醒失敗", "error", err)
+	}
+}
+
+// History 依條件查詢提醒紀錄 (包含通知的發送狀態)
+func (s *ReminderService) History(ctx context.Context, filter repository.ReminderFilter) ([]models.Reminder, error) {
+	return s.reminderRepo.List(ctx, filter)
+}
+
+// queue 記錄一則提醒並將通知加入通知佇列，同一期間已提醒過的客戶會略過
+// 缺少該管道聯絡方式的客戶記為 skipped，不加入佇列；之後補上聯絡方式時，同一期間仍會提醒
+func (s *ReminderService) queue(ctx context.Context, candidate ReminderCandidate, result *ReminderRunResult) error {
+	reminder := &models.Reminder{
+		CustomerName: candidate.CustomerName,
+		Kind:         candidate.Kind,
+		PeriodKey:    candidate.periodKey,
+		Channel:      s.channel,
+	}
+
+	var msg *models.OutboxMessage
+	if candidate.recipient.HasAddress(s.channel) {
+		msg = &models.OutboxMessage{
+			Kind:      models.OutboxKindReminder,
+			Channel:   s.channel,
+			Recipient: candidate.recipient,
+			Subject:   reminderSubjects[candidate.Kind],
+			Body:      s.message(candidate),
+		}
+	} else {
+		reminder.Error = notify.ErrNoAddress.Error()
+	}
+
+	queued, err := s.reminderRepo.Queue(ctx, reminder, msg)
+	switch {
+	case err != nil:
+		return err
+	case !queued:
+		result.AlreadySent++
+	case msg == nil:
+		result.Skipped++
+	default:
+		result.Queued++
+	}
+	return nil
+}
+
+// message 組合提醒內容
+func (s *ReminderService) message(candidate ReminderCandidate) string {
+	template := s.config.ReactivationMessage
+	if candidate.Kind == models.ReminderBirthday {
+		template = s.config.BirthdayMessage
+	}
+	return strings.ReplaceAll(template, "{name}", candidate.CustomerName)
+}
+
+// lists 依試算表與消費紀錄產生 today 的提醒名單
+func (s *ReminderService) lists(ctx context.Context, today time.Time) (*ReminderLists, error) {
+	values, err := s.source.GetValues(ctx)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.CodeUpstreamUnavailable, "sheets.read_failed")
+	}
+	directory, err := buildCustomerDirectory(values)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	// 系統內的消費紀錄與試算表的日期取較晚者
+	lastVisits, err := s.visitRepo.LastVisitDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for name, date := range lastVisits {
+		profile, ok := directory[name]
+		if !ok {
+			profile = &CustomerProfile{Name: name}
+			directory[name] = profile
+		}
+		if date.After(profile.LastVisit) {
+			profile.LastVisit = date
+		}
+	}
+
+	lists := &ReminderLists{
+		Date:         today.Format(dateLayout),
+		Birthdays:    []ReminderCandidate{},
+		Reactivation: []ReminderCandidate{},
+	}
+	for _, profile := range directory {
+		recipient := notify.Recipient{
+			Name:       profile.Name,
+			Phone:      profile.Phone,
+			Email:      profile.Email,
+			LineUserID: profile.LineUserID,
+		}
+
+		if profile.BirthMonth != 0 {
+			next := nextBirthday(today, profile.BirthMonth, profile.BirthDay)
+			if days := daysBetween(today, next); days < s.config.BirthdayDays {
+				lists.Birthdays = append(lists.Birthdays, ReminderCandidate{
+					Kind:         models.ReminderBirthday,
+					CustomerName: profile.Name,
+					Phone:        profile.Phone,
+					Birthday:     next.Format("01-02"),
+					DaysUntil:    days,
+					periodKey:    next.Format("2006"),
+					recipient:    recipient,
+				})
+			}
+		}
+
+		if !profile.LastVisit.IsZero() {
+			if days := daysBetween(profile.LastVisit, today); days >= s.config.LapsedDays && days < s.config.MaxLapsedDays {
+				lists.Reactivation = append(lists.Reactivation, ReminderCandidate{
+					Kind:         models.ReminderReactivation,
+					CustomerName: profile.Name,
+					Phone:        profile.Phone,
+					LastVisit:    profile.LastVisit.Format(dateLayout),
+					DaysSince:    days,
+					periodKey:    profile.LastVisit.Format(dateLayout),
+					recipient:    recipient,
+				})
+			}
+		}
+	}
+
+	sort.Slice(lists.Birthdays, func(i, j int) bool {
+		a, b := lists.Birthdays[i], lists.Birthdays[j]
+		if a.DaysUntil != b.DaysUntil {
+			return a.DaysUntil < b.DaysUntil
+		}
+		return a.CustomerName < b.CustomerName
+	})
+	sort.Slice(lists.Reactivation, func(i, j int) bool {
+		a, b := lists.Reactivation[i], lists.Reactivation[j]
+		if a.DaysSince != b.DaysSince {
+			return a.DaysSince < b.DaysSince
+		}
+		return a.CustomerName < b.CustomerName
+	})
+	return lists, nil
+}
+
+// today 返回營業時區的今天 (UTC 零時)
+func (s *ReminderService) today() time.Time {
+	now := time.Now().In(s.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextBirthday 返回 today 當天或之後的下一個生日，非閏年的 2 月 29 日以 2 月 28 日計算
+func nextBirthday(today time.Time, month time.Month, day int) time.Time {
+	for year := today.Year(); ; year++ {
+		d := day
+		if month == time.February && day == 29 && !isLeapYear(year) {
+			d = 28
+		}
+		birthday := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+		if !birthday.Before(today) {
+			return birthday
+		}
+	}
+}
+
+// isLeapYear 判斷是否為閏年
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// daysBetween 返回兩個 UTC 零時日期相差的天數
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
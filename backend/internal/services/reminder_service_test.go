@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBirthday(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		today time.Time
+		month time.Month
+		day   int
+		want  time.Time
+	}{
+		{"今年還沒到", date(2024, 3, 1), time.May, 17, date(2024, 5, 17)},
+		{"今天生日", date(2024, 5, 17), time.May, 17, date(2024, 5, 17)},
+		{"今年已過", date(2024, 5, 18), time.May, 17, date(2025, 5, 17)},
+		{"年底跨到明年", date(2024, 12, 28), time.January, 2, date(2025, 1, 2)},
+		{"年底當年最後一天", date(2024, 12, 31), time.December, 31, date(2024, 12, 31)},
+		{"閏年的 2 月 29 日", date(2024, 2, 1), time.February, 29, date(2024, 2, 29)},
+		{"非閏年以 2 月 28 日計算", date(2023, 2, 1), time.February, 29, date(2023, 2, 28)},
+		{"非閏年已過 2 月 28 日", date(2023, 3, 1), time.February, 29, date(2024, 2, 29)},
+		{"2100 年不是閏年", date(2100, 1, 1), time.February, 29, date(2100, 2, 28)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextBirthday(tt.today, tt.month, tt.day); !got.Equal(tt.want) {
+				t.Errorf("nextBirthday = %s, want %s", got.Format(dateLayout), tt.want.Format(dateLayout))
+			}
+		})
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{"同一天", date(2024, 5, 17), date(2024, 5, 17), 0},
+		{"跨年", date(2024, 12, 28), date(2025, 1, 2), 5},
+		{"跨閏日", date(2024, 2, 28), date(2024, 3, 1), 2},
+		{"非閏年跨月", date(2023, 2, 28), date(2023, 3, 1), 1},
+		{"一整年", date(2023, 5, 17), date(2024, 5, 17), 366},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysBetween(tt.from, tt.to); got != tt.want {
+				t.Errorf("daysBetween = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// 年底時生日提醒的天數須跨到明年計算
+func TestBirthdayWindowAcrossYearEnd(t *testing.T) {
+	today := time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC)
+	const birthdayDays = 7
+
+	tests := []struct {
+		month  time.Month
+		day    int
+		days   int
+		period string
+		inside bool
+	}{
+		{time.December, 29, 0, "2024", true},
+		{time.December, 31, 2, "2024", true},
+		{time.January, 1, 3, "2025", true},
+		{time.January, 4, 6, "2025", true},
+		{time.January, 5, 7, "2025", false},
+		{time.December, 28, 364, "2025", false},
+	}
+
+	for _, tt := range tests {
+		next := nextBirthday(today, tt.month, tt.day)
+		days := daysBetween(today, next)
+		if days != tt.days || next.Format("2006") != tt.period || (days < birthdayDays) != tt.inside {
+			t.Errorf("%02d-%02d: days = %d, period = %s, want days = %d, period = %s, inside = %v",
+				tt.month, tt.day, days, next.Format("2006"), tt.days, tt.period, tt.inside)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+)
+
+// dateLayout 日期參數與日期欄位使用的格式
+const dateLayout = "2006-01-02"
+
+// TimeOffInput 新增休假的內容，未提供起訖時間時為整天休假
+type TimeOffInput struct {
+	Date   string            `json:"date"`
+	Start  *models.ClockTime `json:"start"`
+	End    *models.ClockTime `json:"end"`
+	Reason string            `json:"reason"`
+}
+
+// ScheduleService 提供員工排班、休假與店休日的維護
+type ScheduleService struct {
+	scheduleRepo *repository.ScheduleRepository
+	staffRepo    *repository.StaffRepository
+}
+
+// NewScheduleService 創建一個新的排班服務
+func NewScheduleService(scheduleRepo *repository.ScheduleRepository, staffRepo *repository.StaffRepository) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo: scheduleRepo,
+		staffRepo:    staffRepo,
+	}
+}
+
+// GetWeeklySchedule 取得員工的每週排班
+func (s *ScheduleService) GetWeeklySchedule(ctx context.Context, staffID uint) (*repository.WeeklySchedule, error) {
+	if err := s.requireStaff(ctx, staffID); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetWeeklySchedule(ctx, staffID)
+}
+
+// ReplaceWeeklySchedule 以新的排班取代員工的每週排班
+// 同一天的上班時段不可重疊，休息時間不需落在上班時段內
+func (s *ScheduleService) ReplaceWeeklySchedule(ctx context.Context, staffID uint, schedule *repository.WeeklySchedule) (*repository.WeeklySchedule, error) {
+	if err := s.requireStaff(ctx, staffID); err != nil {
+		return nil, err
+	}
+
+	byWeekday := map[int][]span{}
+	for _, hours := range schedule.WorkingHours {
+		if err := validateWeeklySpan(hours.Weekday, hours.StartTime, hours.EndTime); err != nil {
+			return nil, err
+		}
+		byWeekday[hours.Weekday] = append(byWeekday[hours.Weekday], span{int(hours.StartTime), int(hours.EndTime)})
+	}
+	for _, spans := range byWeekday {
+		sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+		for i := 1; i < len(spans); i++ {
+			if spans[i].start < spans[i-1].end {
+				return nil, apperror.New(apperror.CodeInvalidRequest, "schedule.overlapping_hours")
+			}
+		}
+	}
+	for _, b := range schedule.Breaks {
+		if err := validateWeeklySpan(b.Weekday, b.StartTime, b.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.scheduleRepo.ReplaceWeeklySchedule(ctx, staffID, schedule); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetWeeklySchedule(ctx, staffID)
+}
+
+// ListTimeOff 列出員工在日期區間 [from, to) 內的休假
+func (s *ScheduleService) ListTimeOff(ctx context.Context, staffID uint, from, to time.Time) ([]models.StaffTimeOff, error) {
+	if err := s.requireStaff(ctx, staffID); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.ListTimeOff(ctx, staffID, from, to)
+}
+
+// CreateTimeOff 新增員工休假
+func (s *ScheduleService) CreateTimeOff(ctx context.Context, staffID uint, input TimeOffInput) (*models.StaffTimeOff, error) {
+	if err := s.requireStaff(ctx, staffID); err != nil {
+		return nil, err
+	}
+
+	date, err := parseDate(input.Date)
+	if err != nil {
+		return nil, err
+	}
+	if (input.Start == nil) != (input.End == nil) {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "schedule.invalid_time")
+	}
+	if input.Start != nil && *input.End <= *input.Start {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "schedule.invalid_time")
+	}
+
+	timeOff := &models.StaffTimeOff{
+		StaffID:   staffID,
+		Date:      date,
+		StartTime: input.Start,
+		EndTime:   input.End,
+		Reason:    strings.TrimSpace(input.Reason),
+	}
+	if err := s.scheduleRepo.CreateTimeOff(ctx, timeOff); err != nil {
+		return nil, err
+	}
+	return timeOff, nil
+}
+
+// DeleteTimeOff 刪除員工休假
+func (s *ScheduleService) DeleteTimeOff(ctx context.Context, staffID, id uint) error {
+	deleted, err := s.scheduleRepo.DeleteTimeOff(ctx, staffID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperror.New(apperror.CodeNotFound, "schedule.time_off_not_found")
+	}
+	return nil
+}
+
+// ListHolidays 列出日期區間 [from, to) 內的店休日
+func (s *ScheduleService) ListHolidays(ctx context.Context, from, to time.Time) ([]models.Holiday, error) {
+	return s.scheduleRepo.ListHolidays(ctx, from, to)
+}
+
+// SaveHoliday 新增或更新 value (YYYY-MM-DD) 的店休日
+func (s *ScheduleService) SaveHoliday(ctx context.Context, value, name string) (*models.Holiday, error) {
+	date, err := parseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "schedule.holiday_name_required")
+	}
+
+	holiday := &models.Holiday{Date: date, Name: name}
+	if err := s.scheduleRepo.SaveHoliday(ctx, holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// DeleteHoliday 刪除店休日
+func (s *ScheduleService) DeleteHoliday(ctx context.Context, value string) error {
+	date, err := parseDate(value)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.scheduleRepo.DeleteHoliday(ctx, date)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperror.New(apperror.CodeNotFound, "schedule.holiday_not_found")
+	}
+	return nil
+}
+
+// requireStaff 確認員工存在
+func (s *ScheduleService) requireStaff(ctx context.Context, staffID uint) error {
+	staff, err := s.staffRepo.GetStaffByID(ctx, staffID)
+	if err != nil {
+		return err
+	}
+	if staff == nil {
+		return apperror.New(apperror.CodeNotFound, "staff.not_found")
+	}
+	return nil
+}
+
+// validateWeeklySpan 驗證每週時段的星期與起訖時間
+func validateWeeklySpan(weekday int, start, end models.ClockTime) error {
+	if weekday < 0 || weekday > 6 {
+		return apperror.New(apperror.CodeInvalidRequest, "schedule.invalid_weekday")
+	}
+	if start < 0 || end > 24*60 || end <= start {
+		return apperror.New(apperror.CodeInvalidRequest, "schedule.invalid_time")
+	}
+	return nil
+}
+
+// parseDate 解析 YYYY-MM-DD 日期，結果為 UTC 零時，與資料庫 date 欄位對應
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, apperror.New(apperror.CodeInvalidRequest, "request.invalid_date")
+	}
+	return date, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/repository"
+)
+
+// span 一天中的時間區間 [start, end)，以分鐘計
+type span struct {
+	start, end int
+}
+
+// contains 區間是否完整包含 other
+func (sp span) contains(other span) bool {
+	return sp.start <= other.start && other.end <= sp.end
+}
+
+// workingSpans 計算員工 day 當天可接客的時段：上班時段扣除休息與休假，不含既有預約
+// staffID 為 0 時包含所有員工；店休日由呼叫端另行確認
+func workingSpans(ctx context.Context, scheduleRepo *repository.ScheduleRepository, staffID uint, day time.Time) (map[uint][]span, error) {
+	weekday := day.Weekday()
+
+	hours, err := scheduleRepo.ListWorkingHoursByWeekday(ctx, weekday)
+	if err != nil {
+		return nil, err
+	}
+	breaks, err := scheduleRepo.ListBreaksByWeekday(ctx, weekday)
+	if err != nil {
+		return nil, err
+	}
+	timeOff, err := scheduleRepo.ListTimeOff(ctx, staffID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	free := map[uint][]span{}
+	for _, h := range hours {
+		free[h.StaffID] = append(free[h.StaffID], span{int(h.StartTime), int(h.EndTime)})
+	}
+	for _, b := range breaks {
+		free[b.StaffID] = subtractSpan(free[b.StaffID], span{int(b.StartTime), int(b.EndTime)})
+	}
+	for _, off := range timeOff {
+		busy := span{0, 24 * 60}
+		if off.StartTime != nil && off.EndTime != nil {
+			busy = span{int(*off.StartTime), int(*off.EndTime)}
+		}
+		free[off.StaffID] = subtractSpan(free[off.StaffID], busy)
+	}
+
+	return free, nil
+}
+
+// subtractSpan 從 spans 中扣除 busy 區間
+func subtractSpan(spans []span, busy span) []span {
+	result := make([]span, 0, len(spans))
+	for _, sp := range spans {
+		if busy.end <= sp.start || busy.start >= sp.end {
+			result = append(result, sp)
+			continue
+		}
+		if busy.start > sp.start {
+			result = append(result, span{sp.start, busy.start})
+		}
+		if busy.end < sp.end {
+			result = append(result, span{busy.end, sp.end})
+		}
+	}
+	return result
+}
+
+// minutesSince 返回 t 距離 dayStart 的分鐘數，限制在當天範圍內
+func minutesSince(dayStart, t time.Time) int {
+	minutes := int(t.Sub(dayStart) / time.Minute)
+	if minutes < 0 {
+		return 0
+	}
+	if minutes > 24*60 {
+		return 24 * 60
+	}
+	return minutes
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+)
+
+// StaffInput 新增或修改員工的內容
+type StaffInput struct {
+	Name   string  `json:"name"`
+	UserID *string `json:"user_id"`
+	Active *bool   `json:"active"`
+}
+
+// StaffService 提供員工相關的業務邏輯
+type StaffService struct {
+	staffRepo *repository.StaffRepository
+}
+
+// NewStaffService 創建一個新的員工服務
+func NewStaffService(staffRepo *repository.StaffRepository) *StaffService {
+	return &StaffService{
+		staffRepo: staffRepo,
+	}
+}
+
+// List 列出員工，activeOnly 為 true 時只列出在職員工
+func (s *StaffService) List(ctx context.Context, activeOnly bool) ([]models.Staff, error) {
+	return s.staffRepo.ListStaff(ctx, activeOnly)
+}
+
+// Get 取得員工，不存在時返回 NOT_FOUND
+func (s *StaffService) Get(ctx context.Context, id uint) (*models.Staff, error) {
+	staff, err := s.staffRepo.GetStaffByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if staff == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "staff.not_found")
+	}
+	return staff, nil
+}
+
+// Create 新增員工，未指定在職狀態時預設為在職
+func (s *StaffService) Create(ctx context.Context, input StaffInput) (*models.Staff, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "staff.name_required")
+	}
+
+	staff := &models.Staff{
+		Name:   name,
+		UserID: input.UserID,
+		Active: input.Active == nil || *input.Active,
+	}
+	if err := s.staffRepo.CreateStaff(ctx, staff); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// Update 修改員工資料，未提供的在職狀態維持不變
+func (s *StaffService) Update(ctx context.Context, id uint, input StaffInput) (*models.Staff, error) {
+	staff, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "staff.name_required")
+	}
+	staff.Name = name
+	staff.UserID = input.UserID
+	if input.Active != nil {
+		staff.Active = *input.Active
+	}
+
+	if err := s.staffRepo.UpdateStaff(ctx, staff); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
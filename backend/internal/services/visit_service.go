@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/apperror"
+	"backend/pkg/logging"
+)
+
+// VisitInput 新增消費紀錄的內容，未提供日期時為營業時區的今天
+// PointsRedeemed 為折抵使用的點數，WalletAmount 為以儲值金支付的金額，兩者合計不可超過總額
+type VisitInput struct {
+	CustomerName   string           `json:"customer_name"`
+	VisitDate      string           `json:"visit_date"`
+	StaffID        *uint            `json:"staff_id"`
+	Note           string           `json:"note"`
+	Items          []VisitItemInput `json:"items"`
+	PointsRedeemed int64            `json:"points_redeemed"`
+	WalletAmount   int64            `json:"wallet_amount"`
+}
+
+// VisitItemInput 消費項目，名稱與金額依服務項目目錄或商品帶入
+// 服務項目的 Kind 為 service (服務項目) 或 extra (加購項目)，未提供時為 service；
+// 提供 PackageID 時以該套票扣抵一堂，項目金額為 0
+// 提供 ProductID 時為零售項目 (Kind 為 retail)，金額為售價 × Quantity (未提供時為 1)，並自動扣減庫存
+type VisitItemInput struct {
+	ServiceID uint   `json:"service_id"`
+	Kind      string `json:"kind"`
+	PackageID *uint  `json:"package_id"`
+	ProductID *uint  `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// VisitService 提供消費紀錄相關的業務邏輯
+type VisitService struct {
+	visitRepo     *repository.VisitRepository
+	serviceRepo   *repository.ServiceRepository
+	staffRepo     *repository.StaffRepository
+	prepaidRepo   *repository.PrepaidRepository
+	inventoryRepo *repository.InventoryRepository
+	loyalty       *LoyaltyService
+	location      *time.Location
+	logger        *slog.Logger
+}
+
+// NewVisitService 創建一個新的消費紀錄服務，location 為營業時區
+func NewVisitService(visitRepo *repository.VisitRepository, serviceRepo *repository.ServiceRepository, staffRepo *repository.StaffRepository, prepaidRepo *repository.PrepaidRepository, inventoryRepo *repository.InventoryRepository, loyalty *LoyaltyService, location *time.Location) *VisitService {
+	return &VisitService{
+		visitRepo:     visitRepo,
+		serviceRepo:   serviceRepo,
+		staffRepo:     staffRepo,
+		prepaidRepo:   prepaidRepo,
+		inventoryRepo: inventoryRepo,
+		loyalty:       loyalty,
+		location:      location,
+		logger:        logging.Component("visits"),
+	}
+}
+
+// List 依條件查詢消費紀錄
+func (s *VisitService) List(ctx context.Context, filter repository.VisitFilter) ([]models.Visit, error) {
+	return s.visitRepo.ListVisits(ctx, filter)
+}
+
+// Get 取得消費紀錄，不存在時返回 NOT_FOUND
+func (s *VisitService) Get(ctx context.Context, id uint) (*models.Visit, error) {
+	visit, err := s.visitRepo.GetVisitByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if visit == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "visits.not_found")
+	}
+	return visit, nil
+}
+
+// Create 新增消費紀錄，項目必須為上架中的服務項目或商品，總額依目前售價自動計算
+// 套票堂數、儲值金、點數與商品庫存在寫入消費紀錄的同一交易內扣抵與累積，不足時返回 CONFLICT
+func (s *VisitService) Create(ctx context.Context, input VisitInput, createdBy string) (*models.Visit, error) {
+	visit, err := s.build(ctx, input, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.visitRepo.CreateVisit(ctx, visit); err != nil {
+		return nil, balanceError(monthClosedError(err))
+	}
+
+	s.logger.InfoContext(ctx, "已新增消費紀錄", "visit_id", visit.ID, "total", visit.Total, "points_earned", visit.PointsEarned)
+	return visit, nil
+}
+
+// build 驗證輸入並依服務項目目錄與商品建立尚未寫入的消費紀錄，同時計算點數折抵與儲值金付款
+// 預約轉為消費紀錄時也經由此處建立，確保項目與金額一律來自目錄
+func (s *VisitService) build(ctx context.Context, input VisitInput, createdBy string) (*models.Visit, error) {
+	customerName := strings.TrimSpace(input.CustomerName)
+	if customerName == "" {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "visits.customer_required")
+	}
+	if len(input.Items) == 0 {
+		return nil, apperror.New(apperror.CodeInvalidRequest, "visits.items_required")
+	}
+
+	visitDate, err := s.visitDate(input.VisitDate)
+	if err != nil {
+		return nil, err
+	}
+
+	visit := &models.Visit{
+		CustomerName: customerName,
+		VisitDate:    visitDate,
+		Note:         input.Note,
+		CreatedBy:    createdBy,
+	}
+
+	if input.StaffID != nil {
+		staff, err := s.staffRepo.GetStaffByID(ctx, *input.StaffID)
+		if err != nil {
+			return nil, err
+		}
+		if staff == nil {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "visits.invalid_staff")
+		}
+		visit.StaffID = &staff.ID
+		visit.StaffName = staff.Name
+	}
+
+	serviceIDs := make([]uint, 0, len(input.Items))
+	productIDs := make([]uint, 0, len(input.Items))
+	for _, item := range input.Items {
+		if item.ProductID != nil {
+			productIDs = append(productIDs, *item.ProductID)
+		} else {
+			serviceIDs = append(serviceIDs, item.ServiceID)
+		}
+	}
+	catalogue, err := s.serviceRepo.GetServicesByIDs(ctx, serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.inventoryRepo.GetProductsByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range input.Items {
+		if item.ProductID != nil {
+			visitItem, err := retailItem(item, products)
+			if err != nil {
+				return nil, err
+			}
+			visit.Items = append(visit.Items, visitItem)
+			visit.Total += visitItem.Price
+			continue
+		}
+
+		svc, ok := catalogue[item.ServiceID]
+		if !ok || !svc.Active {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "visits.unknown_service", item.ServiceID)
+		}
+
+		kind := item.Kind
+		if kind == "" {
+			kind = models.VisitItemService
+		}
+		if kind != models.VisitItemService && kind != models.VisitItemExtra {
+			return nil, apperror.New(apperror.CodeInvalidRequest, "visits.invalid_kind", kind)
+		}
+
+		serviceID := svc.ID
+		visitItem := models.VisitItem{
+			ServiceID: &serviceID,
+			Kind:      kind,
+			Name:      svc.Name,
+			Quantity:  1,
+			Price:     svc.Price,
+		}
+		if item.PackageID != nil {
+			if err := s.checkPackage(ctx, *item.PackageID, customerName, svc.ID); err != nil {
+				return nil, err
+			}
+			visitItem.PackageID = item.PackageID
+			visitItem.ListPrice = svc.Price
+			visitItem.Price = 0
+		}
+
+		visit.Items = append(visit.Items, visitItem)
+		visit.Total += visitItem.Price
+	}
+
+	if err := s.loyalty.applyToVisit(ctx, visit, input.PointsRedeemed); err != nil {
+		return nil, err
+	}
+	if err := payWithWallet(visit, input.WalletAmount); err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// payWithWallet 設定以儲值金支付的金額，不可超過扣除點數折抵後的應付金額；餘額於寫入時在交易內扣除
+func payWithWallet(visit *models.Visit, amount int64) error {
+	if amount < 0 || amount > visit.Total-visit.PointsDiscount {
+		return apperror.New(apperror.CodeInvalidRequest, "visits.invalid_wallet_amount")
+	}
+	visit.WalletPaid = amount
+	return nil
+}
+
+// retailItem 依商品建立零售項目，商品必須存在且上架；庫存於寫入時在交易內扣減
+func retailItem(item VisitItemInput, products map[uint]models.Product) (models.VisitItem, error) {
+	product, ok := products[*item.ProductID]
+	if !ok || !product.Active {
+		return models.VisitItem{}, apperror.New(apperror.CodeInvalidRequest, "visits.unknown_product", *item.ProductID)
+	}
+	if item.Kind != "" && item.Kind != models.VisitItemRetail {
+		return models.VisitItem{}, apperror.New(apperror.CodeInvalidRequest, "visits.invalid_kind", item.Kind)
+	}
+	if item.PackageID != nil {
+		return models.VisitItem{}, apperror.New(apperror.CodeInvalidRequest, "visits.package_not_retail")
+	}
+
+	quantity := item.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return models.VisitItem{}, apperror.New(apperror.CodeInvalidRequest, "visits.invalid_quantity")
+	}
+
+	productID := product.ID
+	return models.VisitItem{
+		ProductID: &productID,
+		Kind:      models.VisitItemRetail,
+		Name:      product.Name,
+		Quantity:  quantity,
+		Price:     product.Price * int64(quantity),
+	}, nil
+}
+
+// checkPackage 確認套票屬於該客戶且可扣抵該服務項目
+// 剩餘堂數與到期日於寫入時在交易內確認
+func (s *VisitService) checkPackage(ctx context.Context, packageID uint, customerName string, serviceID uint) error {
+	pkg, err := s.prepaidRepo.GetPackageByID(ctx, packageID)
+	if err != nil {
+		return err
+	}
+	if pkg == nil || pkg.CustomerName != customerName {
+		return apperror.New(apperror.CodeInvalidRequest, "visits.invalid_package", packageID)
+	}
+	if pkg.ServiceID != nil && *pkg.ServiceID != serviceID {
+		return apperror.New(apperror.CodeInvalidRequest, "visits.package_service_mismatch", packageID)
+	}
+	return nil
+}
+
+// visitDate 解析消費日期，未提供時為營業時區的今天
+func (s *VisitService) visitDate(value string) (time.Time, error) {
+	if value != "" {
+		return parseDate(value)
+	}
+	return parseDate(time.Now().In(s.location).Format(dateLayout))
+}
+
+// monthClosedError 將月份已結算轉為 CONFLICT，其餘錯誤原樣返回
+func monthClosedError(err error) error {
+	if errors.Is(err, repository.ErrMonthClosed) {
+		return apperror.Wrap(err, apperror.CodeConflict, "visits.month_closed")
+	}
+	return err
+}
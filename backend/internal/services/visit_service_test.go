@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"backend/internal/models"
+	"backend/pkg/apperror"
+)
+
+// errorKey 返回 API 錯誤的訊息代號，不是 API 錯誤時返回空字串
+func errorKey(err error) string {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr.MessageKey
+	}
+	return ""
+}
+
+func TestRetailItem(t *testing.T) {
+	products := map[uint]models.Product{
+		1: {ID: 1, Name: "精華液", Price: 1290, Active: true},
+		2: {ID: 2, Name: "停售面膜", Price: 350, Active: false},
+	}
+
+	tests := []struct {
+		name      string
+		item      VisitItemInput
+		wantKey   string
+		wantQty   int
+		wantPrice int64
+	}{
+		{"未提供數量視為一件", VisitItemInput{ProductID: uintPtr(1)}, "", 1, 1290},
+		{"金額為售價乘以數量", VisitItemInput{ProductID: uintPtr(1), Quantity: 3}, "", 3, 3870},
+		{"可明確指定零售類型", VisitItemInput{ProductID: uintPtr(1), Kind: models.VisitItemRetail}, "", 1, 1290},
+		{"商品不存在", VisitItemInput{ProductID: uintPtr(9)}, "visits.unknown_product", 0, 0},
+		{"商品已下架", VisitItemInput{ProductID: uintPtr(2)}, "visits.unknown_product", 0, 0},
+		{"商品不可標為服務項目", VisitItemInput{ProductID: uintPtr(1), Kind: models.VisitItemService}, "visits.invalid_kind", 0, 0},
+		{"商品不可以套票扣抵", VisitItemInput{ProductID: uintPtr(1), PackageID: uintPtr(5)}, "visits.package_not_retail", 0, 0},
+		{"數量不可為負數", VisitItemInput{ProductID: uintPtr(1), Quantity: -1}, "visits.invalid_quantity", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := retailItem(tt.item, products)
+			if tt.wantKey != "" {
+				if key := errorKey(err); key != tt.wantKey {
+					t.Fatalf("retailItem error = %v, want %s", err, tt.wantKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("retailItem error = %v", err)
+			}
+			if got.Kind != models.VisitItemRetail || got.Name != "精華液" || got.ProductID == nil || *got.ProductID != 1 {
+				t.Errorf("retailItem = %+v", got)
+			}
+			if got.Quantity != tt.wantQty || got.Price != tt.wantPrice {
+				t.Errorf("Quantity = %d, Price = %d, want %d, %d", got.Quantity, got.Price, tt.wantQty, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestPayWithWallet(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int64
+		discount int64
+		amount   int64
+		wantErr  bool
+	}{
+		{"不使用儲值金", 2000, 0, 0, false},
+		{"部分以儲值金支付", 2000, 0, 500, false},
+		{"全額以儲值金支付", 2000, 0, 2000, false},
+		{"點數折抵後的餘額全以儲值金支付", 2000, 300, 1700, false},
+		{"超過點數折抵後的應付金額", 2000, 300, 1701, true},
+		{"超過總額", 2000, 0, 2001, true},
+		{"金額不可為負數", 2000, 0, -1, true},
+		{"全額套票扣抵時不可使用儲值金", 0, 0, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visit := &models.Visit{Total: tt.total, PointsDiscount: tt.discount}
+			err := payWithWallet(visit, tt.amount)
+			if tt.wantErr {
+				if key := errorKey(err); key != "visits.invalid_wallet_amount" {
+					t.Fatalf("payWithWallet error = %v, want visits.invalid_wallet_amount", err)
+				}
+				if visit.WalletPaid != 0 {
+					t.Errorf("WalletPaid = %d after rejection, want 0", visit.WalletPaid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("payWithWallet error = %v", err)
+			}
+			if visit.WalletPaid != tt.amount {
+				t.Errorf("WalletPaid = %d, want %d", visit.WalletPaid, tt.amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"backend/pkg/i18n"
+)
+
+// Code 穩定的錯誤代碼，供前端判斷錯誤種類，發佈後不應變更
+type Code string
+
+// 錯誤代碼
+const (
+	CodeInvalidRequest      Code = "INVALID_REQUEST"
+	CodeInvalidCredential   Code = "INVALID_CREDENTIAL"
+	CodeUnauthenticated     Code = "UNAUTHENTICATED"
+	CodeSessionExpired      Code = "SESSION_EXPIRED"
+	CodeForbidden           Code = "FORBIDDEN"
+	CodeNotFound            Code = "NOT_FOUND"
+	CodeCustomerNotFound    Code = "CUSTOMER_NOT_FOUND"
+	CodeConflict            Code = "CONFLICT"
+	CodeRateLimited         Code = "RATE_LIMITED"
+	CodeUpstreamUnavailable Code = "UPSTREAM_UNAVAILABLE"
+	CodeInternal            Code = "INTERNAL"
+)
+
+// codeStatus 錯誤代碼對應的 HTTP 狀態碼
+var codeStatus = map[Code]int{
+	CodeInvalidRequest:      http.StatusBadRequest,
+	CodeInvalidCredential:   http.StatusUnauthorized,
+	CodeUnauthenticated:     http.StatusUnauthorized,
+	CodeSessionExpired:      http.StatusUnauthorized,
+	CodeForbidden:           http.StatusForbidden,
+	CodeNotFound:            http.StatusNotFound,
+	CodeCustomerNotFound:    http.StatusNotFound,
+	CodeConflict:            http.StatusConflict,
+	CodeRateLimited:         http.StatusTooManyRequests,
+	CodeUpstreamUnavailable: http.StatusBadGateway,
+	CodeInternal:            http.StatusInternalServerError,
+}
+
+// Status 返回錯誤代碼對應的 HTTP 狀態碼，未知代碼視為 500
+func (c Code) Status() int {
+	if status, ok := codeStatus[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error API 錯誤
+// MessageKey 為訊息目錄中的代號，回應時依使用者語言轉為訊息；Err 為內部原因，只寫入日誌，不會出現在回應中
+type Error struct {
+	Code       Code
+	MessageKey string
+	Args       []any
+	Err        error
+}
+
+// New 建立一個錯誤，key 為空時使用錯誤代碼的通用訊息
+func New(code Code, key string, args ...any) *Error {
+	return &Error{Code: code, MessageKey: key, Args: args}
+}
+
+// Wrap 以錯誤代碼包裝內部錯誤，key 為空時使用錯誤代碼的通用訊息
+func Wrap(err error, code Code, key string, args ...any) *Error {
+	return &Error{Code: code, MessageKey: key, Args: args, Err: err}
+}
+
+// Internal 將未預期的錯誤包裝為 500
+func Internal(err error) *Error {
+	return Wrap(err, CodeInternal, "")
+}
+
+// Error 實作 error，包含內部原因，僅供日誌使用
+func (e *Error) Error() string {
+	message := e.Message(i18n.Default)
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, message)
+}
+
+// Unwrap 返回內部原因
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status 返回 HTTP 狀態碼
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+// Message 返回指定語言、可顯示給使用者的訊息
+func (e *Error) Message(lang string) string {
+	key := e.MessageKey
+	if key == "" {
+		key = "error." + strings.ToLower(string(e.Code))
+	}
+	return i18n.T(lang, key, e.Args...)
+}
+
+// From 將任意錯誤轉為 *Error，非 *Error 的錯誤一律視為內部錯誤
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
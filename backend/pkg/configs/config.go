@@ -0,0 +1,1026 @@
+package configs
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"backend/pkg/logging"
+	"backend/pkg/utils"
+)
+
+// Config 應用程式的完整設定，載入順序為：預設值 -> 設定檔 -> 環境變數
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DBConfig        `yaml:"database" toml:"database"`
+	Session   SessionConfig   `yaml:"session" toml:"session"`
+	Google    GoogleConfig    `yaml:"google" toml:"google"`
+	Sheets    SheetsConfig    `yaml:"sheets" toml:"sheets"`
+	Admin     AdminConfig     `yaml:"admin" toml:"admin"`
+	Logging   LoggingConfig   `yaml:"logging" toml:"logging"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	OpenAPI   OpenAPIConfig   `yaml:"openapi" toml:"openapi"`
+	API       APIConfig       `yaml:"api" toml:"api"`
+	Business  BusinessConfig  `yaml:"business" toml:"business"`
+	Prepaid   PrepaidConfig   `yaml:"prepaid" toml:"prepaid"`
+	Loyalty   LoyaltyConfig   `yaml:"loyalty" toml:"loyalty"`
+	Notify    NotifyConfig    `yaml:"notify" toml:"notify"`
+	Outbox    OutboxConfig    `yaml:"outbox" toml:"outbox"`
+	Reminders RemindersConfig `yaml:"reminders" toml:"reminders"`
+	// Appointments 預約確認通知設定
+	Appointments AppointmentsConfig `yaml:"appointments" toml:"appointments"`
+	Inventory    InventoryConfig    `yaml:"inventory" toml:"inventory"`
+}
+
+// ServerConfig HTTP 服務設定
+// Prometheus 監控指標在 MetricsAddr 另開監聽，不經由 API 埠對外公開
+type ServerConfig struct {
+	Port            int      `yaml:"port" toml:"port"`
+	CORSOrigins     []string `yaml:"cors_origins" toml:"cors_origins"`
+	TrustedProxies  []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+	ShutdownTimeout int      `yaml:"shutdown_timeout" toml:"shutdown_timeout"` // 秒
+	MetricsAddr     string   `yaml:"metrics_addr" toml:"metrics_addr"`         // 監控指標的獨立監聽位址，空字串代表不提供
+}
+
+// SessionConfig 會話 Cookie 設定
+// Keys 的第一組為目前使用中的金鑰，其餘為輪替中的舊金鑰，只用於驗證既有 Cookie
+type SessionConfig struct {
+	Keys   []SessionKeyConfig `yaml:"keys" toml:"keys"`
+	MaxAge int                `yaml:"max_age" toml:"max_age"` // 秒
+	Secure bool               `yaml:"secure" toml:"secure"`
+}
+
+// SessionKeyConfig 一組會話簽章與加密金鑰
+// 金鑰值可加上 base64: 前綴以提供二進位金鑰；ExpiresAt 為舊金鑰的寬限期截止時間
+type SessionKeyConfig struct {
+	HashKey           string    `yaml:"hash_key" toml:"hash_key"`
+	HashKeyFile       string    `yaml:"hash_key_file" toml:"hash_key_file"`
+	EncryptionKey     string    `yaml:"encryption_key" toml:"encryption_key"`
+	EncryptionKeyFile string    `yaml:"encryption_key_file" toml:"encryption_key_file"`
+	ExpiresAt         time.Time `yaml:"expires_at" toml:"expires_at"`
+}
+
+// GoogleConfig Google 登入設定
+type GoogleConfig struct {
+	ClientSecretPath string `yaml:"client_secret_path" toml:"client_secret_path"`
+}
+
+// SheetsConfig 客戶資料試算表設定
+type SheetsConfig struct {
+	SpreadsheetID      string `yaml:"spreadsheet_id" toml:"spreadsheet_id"`
+	ReadRange          string `yaml:"read_range" toml:"read_range"`
+	ServiceAccountPath string `yaml:"service_account_path" toml:"service_account_path"`
+	// CacheTTL 快取資料最長保留時間 (秒)，0 表示停用快取
+	CacheTTL int `yaml:"cache_ttl" toml:"cache_ttl"`
+	// RevisionCheckInterval 以 Drive 修改時間確認資料是否變更的間隔 (秒)
+	RevisionCheckInterval int `yaml:"revision_check_interval" toml:"revision_check_interval"`
+}
+
+// AdminConfig 管理員設定
+type AdminConfig struct {
+	Emails []string `yaml:"emails" toml:"emails"`
+}
+
+// LoggingConfig 日誌設定
+// Components 可個別設定元件的等級，例如 gorm: debug 會輸出所有 SQL
+type LoggingConfig struct {
+	Level      string            `yaml:"level" toml:"level"`
+	Format     string            `yaml:"format" toml:"format"` // json 或 text
+	Components map[string]string `yaml:"components" toml:"components"`
+}
+
+// TracingConfig OpenTelemetry 追蹤設定，透過 OTLP/HTTP 匯出到 collector
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled" toml:"enabled"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"` // 例如 localhost:4318
+	Insecure    bool    `yaml:"insecure" toml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// RateLimitConfig API 限流設定 (令牌桶)
+// PerIP 套用於所有請求，PerUser 套用於已登入的請求，Routes 依 "方法 路徑" 個別限制
+type RateLimitConfig struct {
+	Enabled  bool                     `yaml:"enabled" toml:"enabled"`
+	Backend  string                   `yaml:"backend" toml:"backend"`     // memory 或 redis
+	RedisURL string                   `yaml:"redis_url" toml:"redis_url"` // 例如 redis://localhost:6379/0
+	PerIP    RateLimitRule            `yaml:"per_ip" toml:"per_ip"`
+	PerUser  RateLimitRule            `yaml:"per_user" toml:"per_user"`
+	Routes   map[string]RateLimitRule `yaml:"routes" toml:"routes"` // 鍵例如 "POST /api/login/google"，不含版本前綴
+}
+
+// RateLimitRule 單一限流規則
+type RateLimitRule struct {
+	RequestsPerMinute int `yaml:"requests_per_minute" toml:"requests_per_minute"`
+	Burst             int `yaml:"burst" toml:"burst"`
+}
+
+// OpenAPIConfig API 規格驗證設定
+// 回應驗證需要暫存回應內容，建議只在開發與測試環境開啟
+type OpenAPIConfig struct {
+	ValidateRequests  bool `yaml:"validate_requests" toml:"validate_requests"`
+	ValidateResponses bool `yaml:"validate_responses" toml:"validate_responses"`
+}
+
+// APIConfig API 版本設定
+// 未加版本前綴的舊路由 (/api/...) 在遷移期間繼續提供，回應附帶 Deprecation 與 Sunset 標頭
+type APIConfig struct {
+	LegacyRoutes       bool      `yaml:"legacy_routes" toml:"legacy_routes"`
+	LegacyDeprecatedAt time.Time `yaml:"legacy_deprecated_at" toml:"legacy_deprecated_at"`
+	LegacySunset       time.Time `yaml:"legacy_sunset" toml:"legacy_sunset"`
+}
+
+// BusinessConfig 店家營業設定
+type BusinessConfig struct {
+	// Timezone 營業所在時區，用於判斷預約與消費紀錄的日期
+	Timezone string `yaml:"timezone" toml:"timezone"`
+	// SlotInterval 可預約時段的間隔 (分鐘)，例如 30 表示每半小時一個起始時間
+	SlotInterval int `yaml:"slot_interval" toml:"slot_interval"`
+}
+
+// Location 返回營業時區，設定錯誤時 (已由 Validate 檢查) 使用 UTC
+func (c BusinessConfig) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// PrepaidConfig 課程套票與儲值金設定
+type PrepaidConfig struct {
+	// PackageValidityMonths 套票購買時未指定到期日時的有效月數，0 表示不會到期
+	PackageValidityMonths int `yaml:"package_validity_months" toml:"package_validity_months"`
+	// WalletValidityMonths 儲值金自最後一次儲值起的有效月數，0 表示不會到期
+	WalletValidityMonths int `yaml:"wallet_validity_months" toml:"wallet_validity_months"`
+	// ExpiryCheckInterval 檢查到期套票與儲值金的間隔 (秒)
+	ExpiryCheckInterval int `yaml:"expiry_check_interval" toml:"expiry_check_interval"`
+}
+
+// LoyaltyConfig 會員點數設定
+type LoyaltyConfig struct {
+	// EarnSpend 每消費多少元累積 1 點 (以扣除點數折抵後的總額計算)
+	EarnSpend int64 `yaml:"earn_spend" toml:"earn_spend"`
+	// PointValue 每點可折抵的金額 (元)
+	PointValue int64 `yaml:"point_value" toml:"point_value"`
+	// ExpiryMonths 點數自取得起的有效月數，0 表示不會到期；兌換時先使用最早取得的點數
+	ExpiryMonths int `yaml:"expiry_months" toml:"expiry_months"`
+	// ExpiryCheckInterval 檢查到期點數的間隔 (秒)
+	ExpiryCheckInterval int `yaml:"expiry_check_interval" toml:"expiry_check_interval"`
+	// Tiers 會員等級，依近 12 個月消費總額由低到高排列
+	Tiers []LoyaltyTier `yaml:"tiers" toml:"tiers"`
+}
+
+// LoyaltyTier 會員等級，近 12 個月消費總額達 MinAnnualSpend 即符合
+type LoyaltyTier struct {
+	Name           string `yaml:"name" toml:"name"`
+	MinAnnualSpend int64  `yaml:"min_annual_spend" toml:"min_annual_spend"`
+	// EarnMultiplier 累積點數的倍率 (百分比)，例如 150 表示 1.5 倍
+	EarnMultiplier int `yaml:"earn_multiplier" toml:"earn_multiplier"`
+}
+
+// NotifyConfig 通知管道設定，log 管道永遠可用，其餘管道在設定後啟用
+type NotifyConfig struct {
+	// FilePath file 管道寫入的檔案 (JSON Lines)，用於測試
+	FilePath string      `yaml:"file_path" toml:"file_path"`
+	Email    EmailConfig `yaml:"email" toml:"email"`
+	SMS      SMSConfig   `yaml:"sms" toml:"sms"`
+	LINE     LINEConfig  `yaml:"line" toml:"line"`
+	// Timeout 呼叫簡訊與 LINE API 的逾時 (秒)
+	Timeout int `yaml:"timeout" toml:"timeout"`
+}
+
+// EmailConfig SMTP 寄件設定，Host 為空時停用
+type EmailConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	From     string `yaml:"from" toml:"from"`
+}
+
+// SMSConfig 簡訊業者 HTTP API 設定，Endpoint 為空時停用
+type SMSConfig struct {
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+}
+
+// LINEConfig LINE Messaging API 設定，ChannelAccessToken 為空時停用
+type LINEConfig struct {
+	ChannelAccessToken string `yaml:"channel_access_token" toml:"channel_access_token"`
+}
+
+// Enabled 返回已設定的通知管道
+func (c NotifyConfig) Enabled() []string {
+	channels := []string{"log"}
+	if c.FilePath != "" {
+		channels = append(channels, "file")
+	}
+	if c.Email.Host != "" {
+		channels = append(channels, "email")
+	}
+	if c.SMS.Endpoint != "" {
+		channels = append(channels, "sms")
+	}
+	if c.LINE.ChannelAccessToken != "" {
+		channels = append(channels, "line")
+	}
+	return channels
+}
+
+// OutboxConfig 通知佇列發送設定
+// 失敗的通知依 BaseBackoff×2^(次數-1) 延後重試 (上限 MaxBackoff)，達 MaxAttempts 次後標記為 dead 等待人工處理
+type OutboxConfig struct {
+	// PollInterval 掃描待發送通知的間隔 (秒)
+	PollInterval int `yaml:"poll_interval" toml:"poll_interval"`
+	// BatchSize 每次最多發送幾則通知
+	BatchSize   int `yaml:"batch_size" toml:"batch_size"`
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts"`
+	// BaseBackoff 與 MaxBackoff 為重試間隔 (秒)
+	BaseBackoff int `yaml:"base_backoff" toml:"base_backoff"`
+	MaxBackoff  int `yaml:"max_backoff" toml:"max_backoff"`
+}
+
+// RemindersConfig 生日與回流提醒設定
+type RemindersConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Channel 發送提醒使用的通知管道 (log、file、email、sms、line)
+	Channel string `yaml:"channel" toml:"channel"`
+	// BirthdayDays 提醒未來幾天內 (含今天) 生日的客戶
+	BirthdayDays int `yaml:"birthday_days" toml:"birthday_days"`
+	// LapsedDays 最後一次消費超過幾天的客戶視為需要回流提醒
+	LapsedDays int `yaml:"lapsed_days" toml:"lapsed_days"`
+	// MaxLapsedDays 最後一次消費超過幾天的客戶不再提醒，避免首次執行時通知多年未到店的客戶
+	MaxLapsedDays int `yaml:"max_lapsed_days" toml:"max_lapsed_days"`
+	// CheckInterval 產生與發送提醒的間隔 (秒)，同一位客戶同一次生日或同一段未到店期間只會提醒一次
+	CheckInterval int `yaml:"check_interval" toml:"check_interval"`
+	// BirthdayMessage 與 ReactivationMessage 為訊息內容，{name} 會替換為客戶名
+	BirthdayMessage     string `yaml:"birthday_message" toml:"birthday_message"`
+	ReactivationMessage string `yaml:"reactivation_message" toml:"reactivation_message"`
+}
+
+// AppointmentsConfig 預約確認通知設定
+type AppointmentsConfig struct {
+	// ConfirmationChannel 新增預約時發送確認通知的管道，空字串代表不發送
+	ConfirmationChannel string `yaml:"confirmation_channel" toml:"confirmation_channel"`
+	// ConfirmationMessage 確認通知內容，{name} 會替換為客戶名，{time} 為預約時間
+	ConfirmationMessage string `yaml:"confirmation_message" toml:"confirmation_message"`
+}
+
+// InventoryConfig 零售商品庫存設定
+type InventoryConfig struct {
+	// LowStockChannel 發送低庫存通知的管道，空字串代表不發送 (仍可在管理介面查看低庫存商品)
+	LowStockChannel string `yaml:"low_stock_channel" toml:"low_stock_channel"`
+	// LowStockRecipient 低庫存通知的收件人，依管道使用其中的電子郵件、電話或 LINE 使用者 ID
+	LowStockRecipient RecipientConfig `yaml:"low_stock_recipient" toml:"low_stock_recipient"`
+	// LowStockCheckInterval 檢查低庫存商品的間隔 (秒)，同一商品到達補貨點只通知一次，補貨後重新計算
+	LowStockCheckInterval int `yaml:"low_stock_check_interval" toml:"low_stock_check_interval"`
+}
+
+// RecipientConfig 店內人員的通知聯絡方式
+type RecipientConfig struct {
+	Name       string `yaml:"name" toml:"name"`
+	Email      string `yaml:"email" toml:"email"`
+	Phone      string `yaml:"phone" toml:"phone"`
+	LineUserID string `yaml:"line_user_id" toml:"line_user_id"`
+}
+
+// minSessionKeyLength 會話簽章金鑰最短長度 (位元組)
+const minSessionKeyLength = 32
+
+// DefaultConfig 返回預設設定，適用於本機開發環境
+func DefaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			CORSOrigins:     []string{"http://localhost:4200", "http://frontend:4200"},
+			TrustedProxies:  []string{"127.0.0.1", "172.16.0.0/12", "192.168.0.0/16"},
+			ShutdownTimeout: 20,
+			MetricsAddr:     "127.0.0.1:9090",
+		},
+		Database: DBConfig{
+			Host:    "localhost",
+			Port:    5432,
+			User:    "postgres",
+			DBName:  "userauth",
+			SSLMode: "disable",
+		},
+		Session: SessionConfig{
+			MaxAge: 3600 * 24 * 30,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "json",
+			Components: map[string]string{
+				"gorm": "warn",
+			},
+		},
+		Tracing: TracingConfig{
+			ServiceName: "little-sun-backend",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:  true,
+			Backend:  "memory",
+			RedisURL: "redis://localhost:6379/0",
+			PerIP:    RateLimitRule{RequestsPerMinute: 300, Burst: 60},
+			PerUser:  RateLimitRule{RequestsPerMinute: 120, Burst: 30},
+			Routes: map[string]RateLimitRule{
+				"POST /api/login/google": {RequestsPerMinute: 10, Burst: 5},
+				"GET /api/sheets":        {RequestsPerMinute: 30, Burst: 10},
+			},
+		},
+		OpenAPI: OpenAPIConfig{
+			ValidateRequests: true,
+		},
+		API: APIConfig{
+			LegacyRoutes:       true,
+			LegacyDeprecatedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			LegacySunset:       time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Business: BusinessConfig{
+			Timezone:     "Asia/Taipei",
+			SlotInterval: 30,
+		},
+		Prepaid: PrepaidConfig{
+			PackageValidityMonths: 12,
+			WalletValidityMonths:  0,
+			ExpiryCheckInterval:   3600,
+		},
+		Loyalty: LoyaltyConfig{
+			EarnSpend:           100,
+			PointValue:          1,
+			ExpiryMonths:        12,
+			ExpiryCheckInterval: 3600,
+			Tiers: []LoyaltyTier{
+				{Name: "silver", MinAnnualSpend: 30000, EarnMultiplier: 120},
+				{Name: "gold", MinAnnualSpend: 80000, EarnMultiplier: 150},
+			},
+		},
+		Notify: NotifyConfig{
+			Email:   EmailConfig{Port: 587},
+			Timeout: 10,
+		},
+		Outbox: OutboxConfig{
+			PollInterval: 10,
+			BatchSize:    50,
+			MaxAttempts:  8,
+			BaseBackoff:  30,
+			MaxBackoff:   3600,
+		},
+		Reminders: RemindersConfig{
+			Enabled:             false,
+			Channel:             "log",
+			BirthdayDays:        7,
+			LapsedDays:          90,
+			MaxLapsedDays:       180,
+			CheckInterval:       3600,
+			BirthdayMessage:     "親愛的 {name} 您好，祝您生日快樂！生日當月到店享有專屬優惠，期待您的光臨。",
+			ReactivationMessage: "親愛的 {name} 您好，好久不見！最近還好嗎？期待您再次光臨。",
+		},
+		Appointments: AppointmentsConfig{
+			ConfirmationMessage: "{name} 您好，已為您預約 {time}，如需更改請來電，期待您的光臨。",
+		},
+		Inventory: InventoryConfig{
+			LowStockCheckInterval: 3600,
+		},
+		Google: GoogleConfig{
+			ClientSecretPath: filepath.Join("pkg", "configs", "client_secret.json"),
+		},
+		Sheets: SheetsConfig{
+			SpreadsheetID:         "10IIJuGiur0HGpvjAippllfg1XhYq_wIHwR4_xWn-z_c",
+			ReadRange:             "客戶細項!A1:Q",
+			ServiceAccountPath:    filepath.Join("pkg", "configs", "little-sun-system-d5e3eda49d9f.json"),
+			CacheTTL:              600,
+			RevisionCheckInterval: 30,
+		},
+	}
+}
+
+// Load 讀取設定檔 (YAML 或 TOML，依副檔名判斷)、套用環境變數並驗證設定
+// path 為空字串時只使用預設值與環境變數
+func Load(path string) (*Config, error) {
+	config := DefaultConfig()
+
+	if path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := config.loadSecrets(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadDatabase 與 Load 相同方式載入設定，但只驗證資料庫相關欄位
+// 供 migrate 等只需要資料庫連線的指令使用
+func LoadDatabase(path string) (*DBConfig, error) {
+	config := DefaultConfig()
+
+	if path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if config.Database.PasswordFile != "" {
+		secret, err := readSecretFile(config.Database.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("database.password_file: %w", err)
+		}
+		config.Database.Password = secret
+	}
+
+	if errs := config.Database.validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("設定驗證失敗:\n%w", errors.Join(errs...))
+	}
+
+	return &config.Database, nil
+}
+
+// loadFile 解析設定檔內容覆蓋預設值
+func (c *Config) loadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("無法讀取設定檔 %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, c)
+	case ".toml":
+		err = toml.Unmarshal(b, c)
+	default:
+		return fmt.Errorf("不支援的設定檔格式 %s，請使用 .yaml、.yml 或 .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("無法解析設定檔 %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv 以環境變數覆蓋設定值
+func (c *Config) applyEnv() error {
+	var errs []error
+
+	setInt := func(key string, target *int) {
+		if v := os.Getenv(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("環境變數 %s 必須為整數: %q", key, v))
+				return
+			}
+			*target = n
+		}
+	}
+	setInt64 := func(key string, target *int64) {
+		if v := os.Getenv(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("環境變數 %s 必須為整數: %q", key, v))
+				return
+			}
+			*target = n
+		}
+	}
+	setBool := func(key string, target *bool) {
+		if v := os.Getenv(key); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("環境變數 %s 必須為布林值: %q", key, v))
+				return
+			}
+			*target = b
+		}
+	}
+	setList := func(key string, target *[]string) {
+		if v := os.Getenv(key); v != "" {
+			*target = splitList(v)
+		}
+	}
+
+	setInt("PORT", &c.Server.Port)
+	setList("CORS_ORIGINS", &c.Server.CORSOrigins)
+	setList("TRUSTED_PROXIES", &c.Server.TrustedProxies)
+	setInt("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	c.Server.MetricsAddr = utils.GetEnv("METRICS_ADDR", c.Server.MetricsAddr)
+	if v := os.Getenv("FRONTEND_URL"); v != "" {
+		c.Server.CORSOrigins = append(c.Server.CORSOrigins, v)
+	}
+
+	c.Database.Host = utils.GetEnv("DB_HOST", c.Database.Host)
+	setInt("DB_PORT", &c.Database.Port)
+	c.Database.User = utils.GetEnv("DB_USER", c.Database.User)
+	c.Database.Password = utils.GetEnv("DB_PASSWORD", c.Database.Password)
+	c.Database.PasswordFile = utils.GetEnv("DB_PASSWORD_FILE", c.Database.PasswordFile)
+	c.Database.DBName = utils.GetEnv("DB_NAME", c.Database.DBName)
+	c.Database.SSLMode = utils.GetEnv("DB_SSL_MODE", c.Database.SSLMode)
+
+	// SESSION_* 覆蓋目前金鑰，SESSION_PREVIOUS_* 覆蓋輪替中的舊金鑰
+	c.applySessionKeyEnv(0, "SESSION_KEY", "SESSION_ENCRYPTION_KEY")
+	c.applySessionKeyEnv(1, "SESSION_PREVIOUS_KEY", "SESSION_PREVIOUS_ENCRYPTION_KEY")
+	if v := os.Getenv("SESSION_PREVIOUS_KEY_EXPIRES_AT"); v != "" && len(c.Session.Keys) > 1 {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("環境變數 SESSION_PREVIOUS_KEY_EXPIRES_AT 必須為 RFC3339 時間: %q", v))
+		} else {
+			c.Session.Keys[1].ExpiresAt = t
+		}
+	}
+	setInt("SESSION_MAX_AGE", &c.Session.MaxAge)
+	setBool("SESSION_SECURE", &c.Session.Secure)
+
+	c.Google.ClientSecretPath = utils.GetEnv("GOOGLE_CLIENT_SECRET_PATH", c.Google.ClientSecretPath)
+
+	c.Sheets.SpreadsheetID = utils.GetEnv("SHEETS_SPREADSHEET_ID", c.Sheets.SpreadsheetID)
+	c.Sheets.ReadRange = utils.GetEnv("SHEETS_READ_RANGE", c.Sheets.ReadRange)
+	c.Sheets.ServiceAccountPath = utils.GetEnv("SHEETS_SERVICE_ACCOUNT_PATH", c.Sheets.ServiceAccountPath)
+	setInt("SHEETS_CACHE_TTL", &c.Sheets.CacheTTL)
+	setInt("SHEETS_REVISION_CHECK_INTERVAL", &c.Sheets.RevisionCheckInterval)
+
+	setList("ADMIN_EMAILS", &c.Admin.Emails)
+
+	setBool("TRACING_ENABLED", &c.Tracing.Enabled)
+	c.Tracing.Endpoint = utils.GetEnv("OTEL_EXPORTER_OTLP_ENDPOINT", c.Tracing.Endpoint)
+	c.Tracing.ServiceName = utils.GetEnv("OTEL_SERVICE_NAME", c.Tracing.ServiceName)
+	if v := os.Getenv("TRACING_SAMPLE_RATIO"); v != "" {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("環境變數 TRACING_SAMPLE_RATIO 必須為數字: %q", v))
+		} else {
+			c.Tracing.SampleRatio = ratio
+		}
+	}
+
+	setBool("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled)
+	c.RateLimit.Backend = utils.GetEnv("RATE_LIMIT_BACKEND", c.RateLimit.Backend)
+	c.RateLimit.RedisURL = utils.GetEnv("REDIS_URL", c.RateLimit.RedisURL)
+
+	setBool("OPENAPI_VALIDATE_REQUESTS", &c.OpenAPI.ValidateRequests)
+	setBool("OPENAPI_VALIDATE_RESPONSES", &c.OpenAPI.ValidateResponses)
+
+	setBool("API_LEGACY_ROUTES", &c.API.LegacyRoutes)
+	if v := os.Getenv("API_LEGACY_SUNSET"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("環境變數 API_LEGACY_SUNSET 必須為 RFC3339 時間: %q", v))
+		} else {
+			c.API.LegacySunset = t
+		}
+	}
+
+	c.Business.Timezone = utils.GetEnv("BUSINESS_TIMEZONE", c.Business.Timezone)
+	setInt("BUSINESS_SLOT_INTERVAL", &c.Business.SlotInterval)
+	setInt("PREPAID_PACKAGE_VALIDITY_MONTHS", &c.Prepaid.PackageValidityMonths)
+	setInt("PREPAID_WALLET_VALIDITY_MONTHS", &c.Prepaid.WalletValidityMonths)
+	setInt("PREPAID_EXPIRY_CHECK_INTERVAL", &c.Prepaid.ExpiryCheckInterval)
+	setInt64("LOYALTY_EARN_SPEND", &c.Loyalty.EarnSpend)
+	setInt64("LOYALTY_POINT_VALUE", &c.Loyalty.PointValue)
+	setInt("LOYALTY_EXPIRY_MONTHS", &c.Loyalty.ExpiryMonths)
+	setInt("LOYALTY_EXPIRY_CHECK_INTERVAL", &c.Loyalty.ExpiryCheckInterval)
+
+	c.Notify.FilePath = utils.GetEnv("NOTIFY_FILE_PATH", c.Notify.FilePath)
+	c.Notify.Email.Host = utils.GetEnv("NOTIFY_SMTP_HOST", c.Notify.Email.Host)
+	setInt("NOTIFY_SMTP_PORT", &c.Notify.Email.Port)
+	c.Notify.Email.Username = utils.GetEnv("NOTIFY_SMTP_USERNAME", c.Notify.Email.Username)
+	c.Notify.Email.Password = utils.GetEnv("NOTIFY_SMTP_PASSWORD", c.Notify.Email.Password)
+	c.Notify.Email.From = utils.GetEnv("NOTIFY_SMTP_FROM", c.Notify.Email.From)
+	c.Notify.SMS.Endpoint = utils.GetEnv("NOTIFY_SMS_ENDPOINT", c.Notify.SMS.Endpoint)
+	c.Notify.SMS.Username = utils.GetEnv("NOTIFY_SMS_USERNAME", c.Notify.SMS.Username)
+	c.Notify.SMS.Password = utils.GetEnv("NOTIFY_SMS_PASSWORD", c.Notify.SMS.Password)
+	c.Notify.LINE.ChannelAccessToken = utils.GetEnv("NOTIFY_LINE_CHANNEL_ACCESS_TOKEN", c.Notify.LINE.ChannelAccessToken)
+	setInt("NOTIFY_TIMEOUT", &c.Notify.Timeout)
+
+	setInt("OUTBOX_POLL_INTERVAL", &c.Outbox.PollInterval)
+	setInt("OUTBOX_BATCH_SIZE", &c.Outbox.BatchSize)
+	setInt("OUTBOX_MAX_ATTEMPTS", &c.Outbox.MaxAttempts)
+	setInt("OUTBOX_BASE_BACKOFF", &c.Outbox.BaseBackoff)
+	setInt("OUTBOX_MAX_BACKOFF", &c.Outbox.MaxBackoff)
+
+	setBool("REMINDERS_ENABLED", &c.Reminders.Enabled)
+	c.Reminders.Channel = utils.GetEnv("REMINDERS_CHANNEL", c.Reminders.Channel)
+	setInt("REMINDERS_BIRTHDAY_DAYS", &c.Reminders.BirthdayDays)
+	setInt("REMINDERS_LAPSED_DAYS", &c.Reminders.LapsedDays)
+	setInt("REMINDERS_MAX_LAPSED_DAYS", &c.Reminders.MaxLapsedDays)
+	setInt("REMINDERS_CHECK_INTERVAL", &c.Reminders.CheckInterval)
+
+	c.Appointments.ConfirmationChannel = utils.GetEnv("APPOINTMENTS_CONFIRMATION_CHANNEL", c.Appointments.ConfirmationChannel)
+
+	c.Inventory.LowStockChannel = utils.GetEnv("INVENTORY_LOW_STOCK_CHANNEL", c.Inventory.LowStockChannel)
+	c.Inventory.LowStockRecipient.Email = utils.GetEnv("INVENTORY_LOW_STOCK_EMAIL", c.Inventory.LowStockRecipient.Email)
+	c.Inventory.LowStockRecipient.Phone = utils.GetEnv("INVENTORY_LOW_STOCK_PHONE", c.Inventory.LowStockRecipient.Phone)
+	c.Inventory.LowStockRecipient.LineUserID = utils.GetEnv("INVENTORY_LOW_STOCK_LINE_USER_ID", c.Inventory.LowStockRecipient.LineUserID)
+	setInt("INVENTORY_LOW_STOCK_CHECK_INTERVAL", &c.Inventory.LowStockCheckInterval)
+
+	c.Logging.Level = utils.GetEnv("LOG_LEVEL", c.Logging.Level)
+	c.Logging.Format = utils.GetEnv("LOG_FORMAT", c.Logging.Format)
+	// LOG_LEVELS 格式為 元件=等級，以逗號分隔，例如 gorm=debug,http=warn
+	for _, item := range splitList(os.Getenv("LOG_LEVELS")) {
+		component, level, ok := strings.Cut(item, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("環境變數 LOG_LEVELS 格式錯誤: %q", item))
+			continue
+		}
+		if c.Logging.Components == nil {
+			c.Logging.Components = map[string]string{}
+		}
+		c.Logging.Components[strings.TrimSpace(component)] = strings.TrimSpace(level)
+	}
+
+	return errors.Join(errs...)
+}
+
+// applySessionKeyEnv 以環境變數覆蓋第 index 組會話金鑰，未設定任何變數時不做變更
+func (c *Config) applySessionKeyEnv(index int, hashEnv, encryptionEnv string) {
+	values := map[string]string{}
+	for _, key := range []string{hashEnv, hashEnv + "_FILE", encryptionEnv, encryptionEnv + "_FILE"} {
+		if v := os.Getenv(key); v != "" {
+			values[key] = v
+		}
+	}
+	if len(values) == 0 || index > len(c.Session.Keys) {
+		return
+	}
+
+	if index == len(c.Session.Keys) {
+		c.Session.Keys = append(c.Session.Keys, SessionKeyConfig{})
+	}
+	key := &c.Session.Keys[index]
+	if v, ok := values[hashEnv]; ok {
+		key.HashKey, key.HashKeyFile = v, ""
+	}
+	if v, ok := values[hashEnv+"_FILE"]; ok {
+		key.HashKeyFile = v
+	}
+	if v, ok := values[encryptionEnv]; ok {
+		key.EncryptionKey, key.EncryptionKeyFile = v, ""
+	}
+	if v, ok := values[encryptionEnv+"_FILE"]; ok {
+		key.EncryptionKeyFile = v
+	}
+}
+
+// loadSecrets 從檔案讀取機密設定，檔案優先於直接設定的值
+func (c *Config) loadSecrets() error {
+	if c.Database.PasswordFile != "" {
+		secret, err := readSecretFile(c.Database.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("database.password_file: %w", err)
+		}
+		c.Database.Password = secret
+	}
+
+	for i := range c.Session.Keys {
+		key := &c.Session.Keys[i]
+		if key.HashKeyFile != "" {
+			secret, err := readSecretFile(key.HashKeyFile)
+			if err != nil {
+				return fmt.Errorf("session.keys[%d].hash_key_file: %w", i, err)
+			}
+			key.HashKey = secret
+		}
+		if key.EncryptionKeyFile != "" {
+			secret, err := readSecretFile(key.EncryptionKeyFile)
+			if err != nil {
+				return fmt.Errorf("session.keys[%d].encryption_key_file: %w", i, err)
+			}
+			key.EncryptionKey = secret
+		}
+	}
+
+	return nil
+}
+
+// Validate 檢查設定是否完整且合理，回傳所有發現的問題
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port 必須介於 1-65535，目前為 %d", c.Server.Port))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout 必須大於 0"))
+	}
+	if len(c.Server.CORSOrigins) == 0 {
+		errs = append(errs, errors.New("server.cors_origins 至少需要一個來源"))
+	}
+	if c.Server.MetricsAddr != "" {
+		if _, port, err := net.SplitHostPort(c.Server.MetricsAddr); err != nil {
+			errs = append(errs, fmt.Errorf("server.metrics_addr 格式錯誤，應為 host:port: %w", err))
+		} else if port == strconv.Itoa(c.Server.Port) {
+			errs = append(errs, errors.New("server.metrics_addr 不可與 API 使用相同的埠"))
+		}
+	}
+
+	errs = append(errs, c.Database.validate()...)
+
+	if len(c.Session.Keys) == 0 {
+		errs = append(errs, errors.New("session.keys 至少需要一組金鑰 (請設定 SESSION_KEY 與 SESSION_ENCRYPTION_KEY)"))
+	}
+	for i, key := range c.Session.Keys {
+		if _, _, err := key.Decode(); err != nil {
+			errs = append(errs, fmt.Errorf("session.keys[%d]: %w", i, err))
+		}
+		if i == 0 && !key.ExpiresAt.IsZero() {
+			errs = append(errs, errors.New("session.keys[0] 為目前使用中的金鑰，不可設定 expires_at"))
+		}
+	}
+	if c.Session.MaxAge <= 0 {
+		errs = append(errs, errors.New("session.max_age 必須大於 0"))
+	}
+
+	if err := checkFileExists(c.Google.ClientSecretPath); err != nil {
+		errs = append(errs, fmt.Errorf("google.client_secret_path: %w", err))
+	}
+
+	if c.Sheets.SpreadsheetID == "" {
+		errs = append(errs, errors.New("sheets.spreadsheet_id 不可為空"))
+	}
+	if c.Sheets.ReadRange == "" {
+		errs = append(errs, errors.New("sheets.read_range 不可為空"))
+	}
+	if err := checkFileExists(c.Sheets.ServiceAccountPath); err != nil {
+		errs = append(errs, fmt.Errorf("sheets.service_account_path: %w", err))
+	}
+	if c.Sheets.CacheTTL < 0 {
+		errs = append(errs, errors.New("sheets.cache_ttl 不可為負數"))
+	}
+	if c.Sheets.CacheTTL > 0 && c.Sheets.RevisionCheckInterval <= 0 {
+		errs = append(errs, errors.New("sheets.revision_check_interval 必須大於 0"))
+	}
+
+	if c.Tracing.Enabled {
+		if c.Tracing.Endpoint == "" {
+			errs = append(errs, errors.New("tracing.endpoint 不可為空"))
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			errs = append(errs, fmt.Errorf("tracing.sample_ratio 必須介於 0-1，目前為 %v", c.Tracing.SampleRatio))
+		}
+	}
+
+	if c.RateLimit.Enabled {
+		errs = append(errs, c.RateLimit.validate()...)
+	}
+
+	if c.API.LegacyRoutes && !c.API.LegacySunset.IsZero() && c.API.LegacySunset.Before(c.API.LegacyDeprecatedAt) {
+		errs = append(errs, errors.New("api.legacy_sunset 不可早於 api.legacy_deprecated_at"))
+	}
+
+	if _, err := time.LoadLocation(c.Business.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("business.timezone: %w", err))
+	}
+	if c.Business.SlotInterval <= 0 || c.Business.SlotInterval > 24*60 {
+		errs = append(errs, fmt.Errorf("business.slot_interval 必須介於 1-1440 分鐘，目前為 %d", c.Business.SlotInterval))
+	}
+	if c.Prepaid.PackageValidityMonths < 0 {
+		errs = append(errs, errors.New("prepaid.package_validity_months 不可為負數"))
+	}
+	if c.Prepaid.WalletValidityMonths < 0 {
+		errs = append(errs, errors.New("prepaid.wallet_validity_months 不可為負數"))
+	}
+	if c.Prepaid.ExpiryCheckInterval <= 0 {
+		errs = append(errs, errors.New("prepaid.expiry_check_interval 必須大於 0"))
+	}
+	if c.Loyalty.EarnSpend <= 0 {
+		errs = append(errs, errors.New("loyalty.earn_spend 必須大於 0"))
+	}
+	if c.Loyalty.PointValue <= 0 {
+		errs = append(errs, errors.New("loyalty.point_value 必須大於 0"))
+	}
+	if c.Loyalty.ExpiryMonths < 0 {
+		errs = append(errs, errors.New("loyalty.expiry_months 不可為負數"))
+	}
+	if c.Loyalty.ExpiryCheckInterval <= 0 {
+		errs = append(errs, errors.New("loyalty.expiry_check_interval 必須大於 0"))
+	}
+	for i, tier := range c.Loyalty.Tiers {
+		if tier.Name == "" {
+			errs = append(errs, fmt.Errorf("loyalty.tiers[%d].name 不可為空", i))
+		}
+		if tier.EarnMultiplier < 0 {
+			errs = append(errs, fmt.Errorf("loyalty.tiers[%d].earn_multiplier 不可為負數", i))
+		}
+		if i > 0 && tier.MinAnnualSpend <= c.Loyalty.Tiers[i-1].MinAnnualSpend {
+			errs = append(errs, fmt.Errorf("loyalty.tiers[%d].min_annual_spend 必須大於前一個等級", i))
+		}
+	}
+
+	if c.Notify.Email.Host != "" && c.Notify.Email.From == "" {
+		errs = append(errs, errors.New("notify.email.from 不可為空"))
+	}
+	if c.Notify.Timeout <= 0 {
+		errs = append(errs, errors.New("notify.timeout 必須大於 0"))
+	}
+	if c.Outbox.PollInterval <= 0 {
+		errs = append(errs, errors.New("outbox.poll_interval 必須大於 0"))
+	}
+	if c.Outbox.BatchSize <= 0 {
+		errs = append(errs, errors.New("outbox.batch_size 必須大於 0"))
+	}
+	if c.Outbox.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("outbox.max_attempts 必須大於 0"))
+	}
+	if c.Outbox.BaseBackoff <= 0 || c.Outbox.MaxBackoff < c.Outbox.BaseBackoff {
+		errs = append(errs, errors.New("outbox.base_backoff 必須大於 0 且不可大於 outbox.max_backoff"))
+	}
+	if c.Reminders.Enabled {
+		if !slices.Contains(c.Notify.Enabled(), c.Reminders.Channel) {
+			errs = append(errs, fmt.Errorf("reminders.channel %q 未設定或不支援，可用的管道為 %s",
+				c.Reminders.Channel, strings.Join(c.Notify.Enabled(), ", ")))
+		}
+		if c.Reminders.BirthdayDays <= 0 {
+			errs = append(errs, errors.New("reminders.birthday_days 必須大於 0"))
+		}
+		if c.Reminders.LapsedDays <= 0 {
+			errs = append(errs, errors.New("reminders.lapsed_days 必須大於 0"))
+		}
+		if c.Reminders.MaxLapsedDays <= c.Reminders.LapsedDays {
+			errs = append(errs, errors.New("reminders.max_lapsed_days 必須大於 reminders.lapsed_days"))
+		}
+		if c.Reminders.CheckInterval <= 0 {
+			errs = append(errs, errors.New("reminders.check_interval 必須大於 0"))
+		}
+	}
+	if channel := c.Appointments.ConfirmationChannel; channel != "" && !slices.Contains(c.Notify.Enabled(), channel) {
+		errs = append(errs, fmt.Errorf("appointments.confirmation_channel %q 未設定或不支援，可用的管道為 %s",
+			channel, strings.Join(c.Notify.Enabled(), ", ")))
+	}
+	if channel := c.Inventory.LowStockChannel; channel != "" {
+		if !slices.Contains(c.Notify.Enabled(), channel) {
+			errs = append(errs, fmt.Errorf("inventory.low_stock_channel %q 未設定或不支援，可用的管道為 %s",
+				channel, strings.Join(c.Notify.Enabled(), ", ")))
+		}
+		if c.Inventory.LowStockCheckInterval <= 0 {
+			errs = append(errs, errors.New("inventory.low_stock_check_interval 必須大於 0"))
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level: %w", err))
+	}
+	for component, level := range c.Logging.Components {
+		if _, err := logging.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("logging.components.%s: %w", component, err))
+		}
+	}
+	switch c.Logging.Format {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("logging.format 必須為 json 或 text，目前為 %q", c.Logging.Format))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("設定驗證失敗:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validate 檢查限流設定
+func (c *RateLimitConfig) validate() []error {
+	var errs []error
+
+	switch c.Backend {
+	case "memory":
+	case "redis":
+		if c.RedisURL == "" {
+			errs = append(errs, errors.New("rate_limit.redis_url 不可為空"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("rate_limit.backend 必須為 memory 或 redis，目前為 %q", c.Backend))
+	}
+
+	errs = append(errs, c.PerIP.validate("rate_limit.per_ip")...)
+	errs = append(errs, c.PerUser.validate("rate_limit.per_user")...)
+	for route, rule := range c.Routes {
+		if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("rate_limit.routes 的鍵格式必須為 \"方法 路徑\"，目前為 %q", route))
+		}
+		errs = append(errs, rule.validate(fmt.Sprintf("rate_limit.routes[%q]", route))...)
+	}
+
+	return errs
+}
+
+// validate 檢查單一限流規則，field 為錯誤訊息中顯示的設定名稱
+func (r RateLimitRule) validate(field string) []error {
+	var errs []error
+	if r.RequestsPerMinute <= 0 {
+		errs = append(errs, fmt.Errorf("%s.requests_per_minute 必須大於 0", field))
+	}
+	if r.Burst <= 0 {
+		errs = append(errs, fmt.Errorf("%s.burst 必須大於 0", field))
+	}
+	return errs
+}
+
+// validate 檢查資料庫連線設定
+func (c *DBConfig) validate() []error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("database.host 不可為空"))
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("database.port 必須介於 1-65535，目前為 %d", c.Port))
+	}
+	if c.User == "" {
+		errs = append(errs, errors.New("database.user 不可為空"))
+	}
+	if c.DBName == "" {
+		errs = append(errs, errors.New("database.dbname 不可為空"))
+	}
+	return errs
+}
+
+// Decode 解析並檢查簽章與加密金鑰，回傳原始位元組
+func (k SessionKeyConfig) Decode() (hashKey, encryptionKey []byte, err error) {
+	if hashKey, err = decodeKey(k.HashKey); err != nil {
+		return nil, nil, fmt.Errorf("hash_key: %w", err)
+	}
+	if len(hashKey) < minSessionKeyLength {
+		return nil, nil, fmt.Errorf("hash_key 長度至少需要 %d 位元組", minSessionKeyLength)
+	}
+
+	if encryptionKey, err = decodeKey(k.EncryptionKey); err != nil {
+		return nil, nil, fmt.Errorf("encryption_key: %w", err)
+	}
+	switch len(encryptionKey) {
+	case 16, 24, 32:
+	default:
+		return nil, nil, fmt.Errorf("encryption_key 長度必須為 16、24 或 32 位元組 (AES)，目前為 %d", len(encryptionKey))
+	}
+
+	return hashKey, encryptionKey, nil
+}
+
+// decodeKey 解析金鑰字串，base64: 前綴代表以 base64 編碼的二進位金鑰
+func decodeKey(value string) ([]byte, error) {
+	if encoded, ok := strings.CutPrefix(value, "base64:"); ok {
+		b, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("無法解析 base64 金鑰: %w", err)
+		}
+		return b, nil
+	}
+	return []byte(value), nil
+}
+
+// readSecretFile 讀取機密檔案並去除前後空白
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("無法讀取機密檔案: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// checkFileExists 檢查檔案路徑是否存在
+func checkFileExists(path string) error {
+	if path == "" {
+		return errors.New("未設定檔案路徑")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("無法存取檔案 %s: %w", path, err)
+	}
+	return nil
+}
+
+// splitList 以逗號分隔字串並去除空白項目
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,67 +2,36 @@ package configs
 
 import (
 	"fmt"
-	"log"
 	"time"
 
-	"strconv"
-
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
-	"backend/pkg/utils"
-	"backend/internal/models"
+	"backend/pkg/logging"
+	"backend/pkg/tracing"
 )
 
 // DBConfig 資料庫連接配置
 type DBConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-}
-
-// DefaultDBConfig 返回預設資料庫配置
-func DefaultDBConfig() *DBConfig {
-	config := &DBConfig{
-		Host:     utils.GetEnv("DB_HOST", "localhost"),
-		User:     utils.GetEnv("DB_USER", "postgres"),
-		Password: utils.GetEnv("DB_PASSWORD", "password"),
-		DBName:   utils.GetEnv("DB_NAME", "userauth"),
-		SSLMode:  utils.GetEnv("DB_SSL_MODE", "disable"),
-	}
-
-	portStr := utils.GetEnv("DB_PORT", "5432")
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		log.Printf("警告: 無法解析 DB_PORT 環境變數，使用預設值 5432: %v", err)
-		port = 5432
-	}
-	config.Port = port
-
-	return config
+	Host         string `yaml:"host" toml:"host"`
+	Port         int    `yaml:"port" toml:"port"`
+	User         string `yaml:"user" toml:"user"`
+	Password     string `yaml:"password" toml:"password"`
+	PasswordFile string `yaml:"password_file" toml:"password_file"`
+	DBName       string `yaml:"dbname" toml:"dbname"`
+	SSLMode      string `yaml:"sslmode" toml:"sslmode"`
 }
 
 // SetupDB 初始化 GORM 資料庫連接
+// 資料表結構由 pkg/migrations 管理，此處不再自動遷移
 func SetupDB(config *DBConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode,
 	)
 
-	// 設定自訂 GORM 記錄器
-	gormLogger := logger.New(
-		log.New(log.Writer(), "\r\n", log.LstdFlags), // io writer
-		logger.Config{
-			SlowThreshold:             time.Second,     // 慢查詢閾值
-			LogLevel:                  logger.Info,     // 記錄等級，生產環境可改為 Warn
-			IgnoreRecordNotFoundError: true,            // 忽略 ErrRecordNotFound 錯誤
-			Colorful:                  true,            // 啟用彩色輸出
-		},
-	)
+	// 設定 GORM 記錄器，SQL 只在 gorm 元件為 debug 等級時輸出
+	gormLogger := logging.NewGormLogger(time.Second) // 慢查詢閾值
 
 	// 連接到資料庫
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
@@ -76,9 +45,9 @@ func SetupDB(config *DBConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("資料庫連接失敗: %w", err)
 	}
 
-	// 自動遷移結構到資料庫
-	if err := db.AutoMigrate(&models.User{}, &models.Session{}); err != nil {
-		return nil, fmt.Errorf("資料庫遷移失敗: %w", err)
+	// 為每個查詢建立追蹤 span
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("設定資料庫追蹤失敗: %w", err)
 	}
 
 	// 取得通用資料庫物件以設定連接池
@@ -92,6 +61,6 @@ func SetupDB(config *DBConfig) (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(100)    // 最大開啟連接數
 	sqlDB.SetConnMaxLifetime(time.Hour) // 連接最大生命週期
 
-	log.Println("資料庫連接成功")
+	logging.Component("database").Info("資料庫連接成功", "host", config.Host, "dbname", config.DBName)
 	return db, nil
 }
\ No newline at end of file
@@ -0,0 +1,160 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"backend/pkg/logging"
+)
+
+// 元件狀態
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc 檢查單一相依元件，回傳 nil 代表正常
+type CheckFunc func(ctx context.Context) error
+
+// 對外回報的檢查失敗原因，詳細錯誤只寫入日誌，避免在未驗證的端點洩漏內部資訊
+const (
+	ErrorTimeout     = "timeout"
+	ErrorUnavailable = "unavailable"
+)
+
+// ComponentStatus 單一元件的檢查結果
+type ComponentStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	CheckedAt string  `json:"checked_at"`
+}
+
+// Report 整體檢查結果
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Healthy 所有元件皆正常時回傳 true
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// Registry 管理就緒檢查項目
+type Registry struct {
+	mu      sync.RWMutex
+	checks  map[string]CheckFunc
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// NewRegistry 創建一個新的檢查登錄表，timeout 為單一檢查的逾時時間
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		checks:  make(map[string]CheckFunc),
+		timeout: timeout,
+		logger:  logging.Component("health"),
+	}
+}
+
+// Register 註冊一個元件檢查
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Names 返回已註冊的元件名稱
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run 並行執行所有檢查並彙整結果
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make(map[string]CheckFunc, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.RUnlock()
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]ComponentStatus, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			status := r.runOne(ctx, name, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = status
+			if status.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runOne 在逾時限制內執行單一檢查並計算延遲，失敗時記錄完整錯誤，結果只帶失敗原因
+func (r *Registry) runOne(ctx context.Context, name string, check CheckFunc) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	status := ComponentStatus{
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start.Format(time.RFC3339),
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = ErrorUnavailable
+		if errors.Is(err, context.DeadlineExceeded) {
+			status.Error = ErrorTimeout
+		}
+		r.logger.WarnContext(ctx, "就緒檢查失敗", "component", name, "error", err)
+	}
+	return status
+}
+
+// Cached 將檢查結果快取 ttl 時間，避免頻繁探測外部服務 (例如 Google API 配額)
+func Cached(ttl time.Duration, check CheckFunc) CheckFunc {
+	var mu sync.Mutex
+	var lastErr error
+	var lastChecked time.Time
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !lastChecked.IsZero() && time.Since(lastChecked) < ttl {
+			return lastErr
+		}
+		lastErr = check(ctx)
+		lastChecked = time.Now()
+		return lastErr
+	}
+}
@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// 支援的語言
+const (
+	ZhTW = "zh-TW"
+	En   = "en"
+)
+
+// Default 預設語言，無法判斷使用者語言或訊息缺少翻譯時使用
+const Default = ZhTW
+
+// locales 訊息目錄，每個語言一個 YAML 檔，鍵為訊息代號
+//
+//go:embed locales/*.yaml
+var locales embed.FS
+
+// catalogue 語言 -> 訊息代號 -> 訊息樣板
+var catalogue = mustLoad()
+
+// matcher 依 Accept-Language 選擇語言，第一個為預設語言
+var matcher = language.NewMatcher([]language.Tag{
+	language.MustParse(ZhTW),
+	language.English,
+})
+
+// supported 與 matcher 順序一致的語言代碼
+var supported = []string{ZhTW, En}
+
+// mustLoad 讀取內嵌的訊息目錄，格式錯誤時直接 panic (屬於建置錯誤)
+func mustLoad() map[string]map[string]string {
+	result := make(map[string]map[string]string, len(supported))
+	for _, lang := range supported {
+		data, err := locales.ReadFile(path.Join("locales", lang+".yaml"))
+		if err != nil {
+			panic(fmt.Sprintf("讀取訊息目錄 %s 失敗: %v", lang, err))
+		}
+
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("解析訊息目錄 %s 失敗: %v", lang, err))
+		}
+		result[lang] = messages
+	}
+	return result
+}
+
+// Negotiate 依 Accept-Language 標頭選出支援的語言，無法判斷時返回預設語言
+func Negotiate(acceptLanguage string) string {
+	if strings.TrimSpace(acceptLanguage) == "" {
+		return Default
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// T 返回指定語言的訊息，args 依 fmt 格式填入樣板
+// 缺少翻譯時改用預設語言，預設語言也缺少時返回訊息代號本身
+func T(lang, key string, args ...any) string {
+	template, ok := catalogue[lang][key]
+	if !ok {
+		template, ok = catalogue[Default][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// Missing 返回各語言相對於預設語言缺少的訊息代號，供啟動時檢查
+func Missing() map[string][]string {
+	missing := map[string][]string{}
+	for _, lang := range supported {
+		for key := range catalogue[Default] {
+			if _, ok := catalogue[lang][key]; !ok {
+				missing[lang] = append(missing[lang], key)
+			}
+		}
+	}
+	return missing
+}
+
+type contextKey struct{}
+
+// WithLanguage 將使用者語言寫入 context
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext 取得 context 中的使用者語言，未設定時返回預設語言
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,52 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// hook 關閉時執行的步驟
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Shutdown 依註冊的相反順序關閉資源 (後啟動的先關閉)
+type Shutdown struct {
+	mu    sync.Mutex
+	hooks []hook
+}
+
+// NewShutdown 創建一個新的關閉流程
+func NewShutdown() *Shutdown {
+	return &Shutdown{}
+}
+
+// Register 註冊關閉步驟，應在對應資源啟動後立即註冊
+func (s *Shutdown) Register(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook{name: name, fn: fn})
+}
+
+// Run 依相反順序執行所有關閉步驟，單一步驟失敗不影響後續步驟
+func (s *Shutdown) Run(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := s.hooks
+	s.hooks = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		slog.Info("關閉資源", "resource", h.name)
+		if err := h.fn(ctx); err != nil {
+			slog.Error("關閉資源失敗", "resource", h.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
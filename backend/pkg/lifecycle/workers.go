@@ -0,0 +1,112 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WorkerGroup 追蹤背景工作 (例如刷新會話、清理過期會話)，讓服務關閉時能等待其完成
+type WorkerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	closed bool
+	// stopping 在 Shutdown 開始時關閉，通知週期性工作停止排程
+	stopping chan struct{}
+}
+
+// NewWorkerGroup 創建一個新的背景工作群組
+func NewWorkerGroup() *WorkerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WorkerGroup{
+		ctx:      ctx,
+		cancel:   cancel,
+		stopping: make(chan struct{}),
+	}
+}
+
+// Go 在背景執行 fn；群組關閉後不再接受新的工作
+// fn 收到的 context 保留 parent 的值 (請求 ID、追蹤資訊) 但不隨請求結束而取消，
+// 只有在關閉逾時時才會被取消，讓進行中的寫入有機會完成
+func (g *WorkerGroup) Go(parent context.Context, name string, fn func(ctx context.Context)) {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		slog.Warn("服務關閉中，略過背景工作", "worker", name)
+		return
+	}
+	g.wg.Add(1)
+	g.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(g.ctx, cancel)
+
+	go func() {
+		defer g.wg.Done()
+		defer cancel()
+		defer stop()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("背景工作發生 panic", "worker", name, "panic", r)
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+// Every 每隔 interval 執行一次 fn (啟動時不立即執行)，直到群組關閉
+// 同一時間只會有一個 fn 在執行；關閉時等待進行中的 fn 完成
+func (g *WorkerGroup) Every(name string, interval time.Duration, fn func(ctx context.Context)) {
+	g.Go(context.Background(), name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-g.stopping:
+				return
+			case <-ticker.C:
+				g.runOnce(ctx, name, fn)
+			}
+		}
+	})
+}
+
+// runOnce 執行一次週期性工作，panic 不會中斷後續排程
+func (g *WorkerGroup) runOnce(ctx context.Context, name string, fn func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("背景工作發生 panic", "worker", name, "panic", r)
+		}
+	}()
+	fn(ctx)
+}
+
+// Shutdown 停止接受新工作並等待進行中的工作完成
+// ctx 逾時時取消所有工作的 context 並回傳 ctx 的錯誤
+func (g *WorkerGroup) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	if !g.closed {
+		g.closed = true
+		close(g.stopping)
+	}
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		return ctx.Err()
+	}
+}
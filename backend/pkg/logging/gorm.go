@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger 將 GORM 日誌導向 slog，SQL 只在 debug 等級輸出
+type GormLogger struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+// NewGormLogger 創建一個使用 "gorm" 元件等級的 GORM 日誌器
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{
+		logger:        Component("gorm"),
+		slowThreshold: slowThreshold,
+	}
+}
+
+// LogMode 等級由 slog 元件設定控制，此處不另外調整
+func (l *GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+// Info 記錄一般訊息
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+// Warn 記錄警告訊息
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+// Error 記錄錯誤訊息
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+// Trace 記錄每個 SQL 的耗時；錯誤與慢查詢會提高等級
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.logger.ErrorContext(ctx, "SQL 執行失敗", "error", err, "sql", sql, "rows", rows, "elapsed_ms", elapsed.Milliseconds())
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		sql, rows := fc()
+		l.logger.WarnContext(ctx, "慢查詢", "sql", sql, "rows", rows, "elapsed_ms", elapsed.Milliseconds())
+	case l.logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		l.logger.DebugContext(ctx, "SQL", "sql", sql, "rows", rows, "elapsed_ms", elapsed.Milliseconds())
+	}
+}
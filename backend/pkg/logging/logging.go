@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ComponentKey 元件名稱的屬性鍵，用於套用個別的日誌等級
+const ComponentKey = "component"
+
+// requestIDKey 請求 ID 在 context 中的鍵
+type requestIDKey struct{}
+
+// WithRequestID 將請求 ID 放入 context，之後以此 context 記錄的日誌都會帶上 request_id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID 從 context 取得請求 ID
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ParseLevel 解析日誌等級 (debug、info、warn、error)
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return 0, fmt.Errorf("無效的日誌等級 %q", value)
+	}
+	return level, nil
+}
+
+// Setup 設定全域 slog 日誌 (同時接管標準 log 套件的輸出)
+// format 為 json 或 text；componentLevels 為個別元件的日誌等級，例如 {"gorm": "warn"}
+func Setup(w io.Writer, format, level string, componentLevels map[string]string) error {
+	defaultLevel, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	levels := make(map[string]slog.Level, len(componentLevels))
+	for component, value := range componentLevels {
+		l, err := ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("元件 %s: %w", component, err)
+		}
+		levels[component] = l
+	}
+
+	// 底層 handler 接受所有等級，實際過濾由 handler 依元件判斷
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var inner slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		inner = slog.NewJSONHandler(w, opts)
+	case "text":
+		inner = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("不支援的日誌格式 %q，請使用 json 或 text", format)
+	}
+
+	slog.SetDefault(slog.New(&handler{
+		inner:        inner,
+		defaultLevel: defaultLevel,
+		levels:       levels,
+	}))
+
+	// 標準 log 套件的輸出已由 slog 處理，不需要額外的時間與檔名前綴
+	log.SetFlags(0)
+	return nil
+}
+
+// Component 返回帶有元件名稱的 logger，應在 Setup 之後呼叫
+func Component(name string) *slog.Logger {
+	return slog.Default().With(ComponentKey, name)
+}
+
+// Fatal 記錄錯誤後結束程式
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// handler 加上請求 ID、遮蔽敏感資料並依元件套用日誌等級
+type handler struct {
+	inner        slog.Handler
+	defaultLevel slog.Level
+	levels       map[string]slog.Level
+	component    string
+}
+
+// Enabled 依元件等級判斷是否記錄
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level()
+}
+
+// Handle 加上 request_id 並遮蔽訊息與屬性中的敏感資料
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, RedactString(r.Message), r.PC)
+	if id := RequestID(ctx); id != "" {
+		redacted.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		redacted.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, redacted)
+}
+
+// WithAttrs 記錄元件名稱並遮蔽預先加入的屬性
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Key == ComponentKey {
+			clone.component = a.Value.String()
+		}
+		redacted = append(redacted, redactAttr(a))
+	}
+	clone.inner = h.inner.WithAttrs(redacted)
+	return &clone
+}
+
+// WithGroup 建立屬性群組
+func (h *handler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.inner = h.inner.WithGroup(name)
+	return &clone
+}
+
+// level 返回目前元件適用的日誌等級
+func (h *handler) level() slog.Level {
+	if l, ok := h.levels[h.component]; ok {
+		return l
+	}
+	return h.defaultLevel
+}
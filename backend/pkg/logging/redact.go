@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// redactedValue 取代敏感值的字串
+const redactedValue = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	// JWT (例如 Google ID Token) 與 Bearer 權杖
+	tokenPattern  = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`)
+)
+
+// secretKeys 屬性鍵包含這些字詞時，整個值會被移除
+var secretKeys = []string{"token", "credential", "password", "secret", "authorization", "cookie"}
+
+// personNameKeys 屬性鍵為這些名稱時，值視為人名並部分遮蔽
+var personNameKeys = map[string]bool{
+	"customer":        true,
+	"customer_name":   true,
+	"target_customer": true,
+	"name":            true,
+	"staff_name":      true,
+}
+
+// RedactString 遮蔽字串中的電子郵件與權杖
+func RedactString(s string) string {
+	s = tokenPattern.ReplaceAllString(s, redactedValue)
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redactedValue)
+	s = emailPattern.ReplaceAllString(s, "$1***@$2")
+	return s
+}
+
+// MaskName 保留姓名第一個字，其餘以 * 取代，例如「王小明」成為「王**」
+func MaskName(name string) string {
+	if name == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return string(first) + strings.Repeat("*", utf8.RuneCountInString(name[size:]))
+}
+
+// redactAttr 依屬性鍵與內容遮蔽敏感資料，群組屬性會遞迴處理
+func redactAttr(a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		redacted := make([]any, 0, len(attrs))
+		for _, inner := range attrs {
+			redacted = append(redacted, redactAttr(inner))
+		}
+		return slog.Group(a.Key, redacted...)
+	}
+
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(a.Key, redactedValue)
+		}
+	}
+
+	if personNameKeys[key] {
+		return slog.String(a.Key, MaskName(a.Value.String()))
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
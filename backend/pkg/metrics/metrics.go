@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 登入結果
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP 請求數，依方法、路由與狀態碼分類",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP 請求處理時間 (秒)",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	loginAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_login_attempts_total",
+		Help: "登入嘗試次數，依結果與原因分類",
+	}, []string{"result", "reason"})
+
+	sheetsRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sheets_api_requests_total",
+		Help: "Google Sheets API 呼叫次數，依操作與結果分類",
+	}, []string{"operation", "result"})
+
+	sheetsDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sheets_api_request_duration_seconds",
+		Help:    "Google Sheets API 呼叫時間 (秒)",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"operation"})
+
+	sheetsCache = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sheets_cache_requests_total",
+		Help: "試算表快取查詢次數，依結果 (hit、revalidated、stale、miss) 分類",
+	}, []string{"result"})
+
+	deprecatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_deprecated_requests_total",
+		Help: "呼叫已淘汰路由的次數，用於確認舊路由何時可以移除",
+	}, []string{"method", "route"})
+
+	rateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_rate_limited_total",
+		Help: "因限流被拒絕的請求數，依限制範圍與路由分類",
+	}, []string{"scope", "route"})
+
+	outboxDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbox_deliveries_total",
+		Help: "通知佇列的發送次數，依管道與結果 (sent、retry、dead) 分類",
+	}, []string{"channel", "result"})
+)
+
+// GinMiddleware 記錄每個請求的次數與處理時間
+// 以路由樣板 (例如 /api/admin/audit) 作為標籤，避免查詢參數造成標籤爆量
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RegisterDBStats 匯出資料庫連接池統計 (sqlDB.Stats())
+func RegisterDBStats(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// RegisterActiveSessions 以 count 函式提供目前有效會話數，於每次抓取時計算
+func RegisterActiveSessions(count func() float64) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "auth_active_sessions",
+		Help: "目前未過期的使用者會話數",
+	}, count)
+}
+
+// ObserveLogin 記錄一次登入結果，reason 為成功或失敗原因
+func ObserveLogin(result, reason string) {
+	loginAttempts.WithLabelValues(result, reason).Inc()
+}
+
+// ObserveSheetsCall 記錄一次 Sheets API 呼叫的結果與耗時
+func ObserveSheetsCall(operation string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	sheetsRequests.WithLabelValues(operation, result).Inc()
+	sheetsDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveSheetsCache 記錄一次試算表快取查詢的結果
+func ObserveSheetsCache(result string) {
+	sheetsCache.WithLabelValues(result).Inc()
+}
+
+// ObserveDeprecatedRequest 記錄一次對已淘汰路由的呼叫
+func ObserveDeprecatedRequest(method, route string) {
+	deprecatedRequests.WithLabelValues(method, route).Inc()
+}
+
+// ObserveRateLimited 記錄一次被限流拒絕的請求，scope 為 ip、user 或 route
+func ObserveRateLimited(scope, route string) {
+	rateLimited.WithLabelValues(scope, route).Inc()
+}
+
+// ObserveOutboxDelivery 記錄一次通知發送的結果，result 為 sent、retry 或 dead
+func ObserveOutboxDelivery(channel, result string) {
+	outboxDeliveries.WithLabelValues(channel, result).Inc()
+}
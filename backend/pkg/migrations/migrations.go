@@ -0,0 +1,366 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var embedded embed.FS
+
+// DefaultDir 新增遷移檔案時的預設目錄 (相對於 backend 根目錄)
+const DefaultDir = "pkg/migrations/sql"
+
+// advisoryLockID 避免多個實例同時執行遷移的 PostgreSQL advisory lock 編號
+const advisoryLockID = 7_236_501
+
+// 結構版本檢查錯誤
+var (
+	ErrPendingMigrations = errors.New("資料庫結構尚未遷移到最新版本")
+	ErrSchemaTooNew      = errors.New("資料庫結構版本比程式支援的版本新")
+)
+
+// migrationFilePattern 遷移檔名格式：<版本>_<名稱>.<up|down>.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 一個版本的結構變更
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 遷移版本的套用狀態
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator 執行與查詢資料庫結構遷移
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New 創建一個使用內嵌 SQL 檔案的遷移器
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := load(embedded, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// LatestVersion 返回程式內最新的遷移版本
+func (m *Migrator) LatestVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// CurrentVersion 返回資料庫目前的結構版本，未曾遷移時為 0
+func (m *Migrator) CurrentVersion(ctx context.Context) (int64, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return 0, err
+	}
+
+	var version sql.NullInt64
+	if err := m.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("查詢結構版本失敗: %w", err)
+	}
+	return version.Int64, nil
+}
+
+// Check 確認資料庫結構與程式版本一致，服務啟動前呼叫
+// 比對所有已套用的版本，中間缺少的版本也視為尚未遷移
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int64]bool, len(m.migrations))
+	var pending []string
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+	}
+
+	var unknown []int64
+	for version := range applied {
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i] < unknown[j] })
+
+	switch {
+	case len(unknown) > 0:
+		return fmt.Errorf("%w (程式不認得的版本 %v，程式支援 %d)", ErrSchemaTooNew, unknown, m.LatestVersion())
+	case len(pending) > 0:
+		return fmt.Errorf("%w (尚未套用 %s)，請先執行 migrate up", ErrPendingMigrations, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// Status 返回所有遷移版本的套用狀態
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up 依序套用所有尚未套用的遷移，返回本次套用的版本
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 依序回復最近套用的 steps 個遷移，返回本次回復的版本
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("遷移 %04d_%s 沒有 down 腳本，無法回復", migration.Version, migration.Name)
+			}
+			if err := m.apply(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// apply 在交易中執行單一遷移並更新版本表
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("開始交易失敗: %w", err)
+	}
+	defer tx.Rollback()
+
+	script, direction := migration.Up, "up"
+	if !up {
+		script, direction = migration.Down, "down"
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("執行遷移 %04d_%s (%s) 失敗: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, time.Now())
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("更新結構版本失敗: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交遷移 %04d_%s 失敗: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// withLock 取得 advisory lock 後執行 fn，避免多個實例同時遷移
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return err
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("取得資料庫連接失敗: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return fmt.Errorf("取得遷移鎖失敗: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+
+	return fn(conn)
+}
+
+// ensureVersionTable 建立結構版本表
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("建立結構版本表失敗: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions 返回已套用的版本與套用時間
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("查詢結構版本失敗: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("讀取結構版本失敗: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// load 從檔案系統讀取並排序遷移檔案
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("讀取遷移目錄失敗: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("讀取遷移檔案 %s 失敗: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("遷移版本 %d 有多個名稱: %s、%s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(b)
+		} else {
+			migration.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("遷移 %04d_%s 缺少 up 腳本", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Create 在 dir 中建立下一個版本的空白 up/down 遷移檔案，返回檔案路徑
+func Create(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", errors.New("遷移名稱不可為空")
+	}
+
+	existing, err := load(os.DirFS(dir), ".")
+	if err != nil {
+		return "", "", err
+	}
+	next := int64(1)
+	if len(existing) > 0 {
+		next = existing[len(existing)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%04d_%s", next, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(upPath, []byte("-- "+base+" up\n"), 0o644); err != nil {
+		return "", "", fmt.Errorf("建立遷移檔案失敗: %w", err)
+	}
+	if err := os.WriteFile(downPath, []byte("-- "+base+" down\n"), 0o644); err != nil {
+		return "", "", fmt.Errorf("建立遷移檔案失敗: %w", err)
+	}
+	return upPath, downPath, nil
+}
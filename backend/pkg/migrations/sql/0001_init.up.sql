@@ -0,0 +1,57 @@
+-- 初始結構：使用者、會話與稽核紀錄
+-- 使用 IF NOT EXISTS，讓先前以 AutoMigrate 建立的資料庫可以直接納入版本管理
+
+CREATE TABLE IF NOT EXISTS users (
+	id         text PRIMARY KEY,
+	email      varchar(255) NOT NULL,
+	name       varchar(255) NOT NULL,
+	picture    text,
+	role       varchar(32) NOT NULL DEFAULT 'staff',
+	created_at timestamptz,
+	last_login timestamptz,
+	deleted_at timestamptz
+);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(32) NOT NULL DEFAULT 'staff';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS sessions (
+	id         uuid PRIMARY KEY,
+	user_id    varchar(255) NOT NULL,
+	expires_at timestamptz NOT NULL,
+	ip         varchar(45),
+	user_agent text,
+	created_at timestamptz,
+	deleted_at timestamptz,
+	CONSTRAINT fk_users_sessions FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_deleted_at ON sessions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+	id              bigserial PRIMARY KEY,
+	actor_id        varchar(255),
+	actor_email     varchar(255),
+	action          varchar(32) NOT NULL,
+	target_customer varchar(255),
+	ip              varchar(45),
+	user_agent      text,
+	detail          text,
+	created_at      timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_customer ON audit_logs (target_customer);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- 稽核紀錄表禁止更新與刪除
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+	BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
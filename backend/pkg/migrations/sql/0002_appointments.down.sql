@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS appointment_items;
+ALTER TABLE IF EXISTS visits DROP CONSTRAINT IF EXISTS fk_visits_appointment;
+DROP TABLE IF EXISTS appointments;
+DROP TABLE IF EXISTS visit_items;
+DROP TABLE IF EXISTS visits;
+DROP TABLE IF EXISTS staff;
@@ -0,0 +1,70 @@
+-- 員工、預約與消費紀錄
+
+CREATE TABLE staff (
+	id         bigserial PRIMARY KEY,
+	name       varchar(255) NOT NULL,
+	user_id    varchar(255) REFERENCES users (id),
+	active     boolean NOT NULL DEFAULT true,
+	created_at timestamptz,
+	updated_at timestamptz
+);
+CREATE INDEX idx_staff_user_id ON staff (user_id);
+
+CREATE TABLE visits (
+	id             bigserial PRIMARY KEY,
+	customer_name  varchar(255) NOT NULL,
+	visit_date     date NOT NULL,
+	staff_id       bigint REFERENCES staff (id),
+	staff_name     varchar(255),
+	appointment_id bigint,
+	note           text,
+	total          bigint NOT NULL,
+	created_by     varchar(255),
+	created_at     timestamptz
+);
+CREATE INDEX idx_visits_customer_name ON visits (customer_name);
+CREATE INDEX idx_visits_visit_date ON visits (visit_date);
+CREATE INDEX idx_visits_staff_id ON visits (staff_id);
+CREATE UNIQUE INDEX idx_visits_appointment_id ON visits (appointment_id);
+
+CREATE TABLE visit_items (
+	id       bigserial PRIMARY KEY,
+	visit_id bigint NOT NULL REFERENCES visits (id) ON DELETE CASCADE,
+	kind     varchar(16) NOT NULL,
+	name     varchar(255) NOT NULL,
+	price    bigint NOT NULL
+);
+CREATE INDEX idx_visit_items_visit_id ON visit_items (visit_id);
+
+CREATE TABLE appointments (
+	id             bigserial PRIMARY KEY,
+	customer_name  varchar(255) NOT NULL,
+	customer_phone varchar(32),
+	staff_id       bigint NOT NULL REFERENCES staff (id),
+	start_at       timestamptz NOT NULL,
+	end_at         timestamptz NOT NULL,
+	status         varchar(16) NOT NULL DEFAULT 'scheduled',
+	note           text,
+	visit_id       bigint REFERENCES visits (id),
+	created_by     varchar(255),
+	created_at     timestamptz,
+	updated_at     timestamptz,
+	CONSTRAINT chk_appointments_time CHECK (end_at > start_at)
+);
+CREATE INDEX idx_appointments_customer_name ON appointments (customer_name);
+CREATE INDEX idx_appointments_staff_id ON appointments (staff_id);
+CREATE INDEX idx_appointments_start_at ON appointments (start_at);
+CREATE INDEX idx_appointments_status ON appointments (status);
+CREATE UNIQUE INDEX idx_appointments_visit_id ON appointments (visit_id);
+
+ALTER TABLE visits ADD CONSTRAINT fk_visits_appointment
+	FOREIGN KEY (appointment_id) REFERENCES appointments (id);
+
+CREATE TABLE appointment_items (
+	id               bigserial PRIMARY KEY,
+	appointment_id   bigint NOT NULL REFERENCES appointments (id) ON DELETE CASCADE,
+	name             varchar(255) NOT NULL,
+	price            bigint NOT NULL,
+	duration_minutes integer NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_appointment_items_appointment_id ON appointment_items (appointment_id);
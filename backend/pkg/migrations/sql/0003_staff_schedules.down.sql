@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS holidays;
+DROP TABLE IF EXISTS staff_time_off;
+DROP TABLE IF EXISTS staff_breaks;
+DROP TABLE IF EXISTS staff_working_hours;
+DROP TABLE IF EXISTS services;
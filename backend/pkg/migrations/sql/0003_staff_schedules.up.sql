@@ -0,0 +1,57 @@
+-- 服務項目、員工排班、休假與店休日
+
+CREATE TABLE services (
+	id               bigserial PRIMARY KEY,
+	name             varchar(255) NOT NULL,
+	duration_minutes integer NOT NULL,
+	active           boolean NOT NULL DEFAULT true,
+	created_at       timestamptz,
+	updated_at       timestamptz,
+	CONSTRAINT chk_services_duration CHECK (duration_minutes > 0)
+);
+CREATE UNIQUE INDEX idx_services_name ON services (name);
+
+-- 時間以距離午夜的分鐘數儲存，weekday 0 為星期日
+CREATE TABLE staff_working_hours (
+	id         bigserial PRIMARY KEY,
+	staff_id   bigint NOT NULL REFERENCES staff (id) ON DELETE CASCADE,
+	weekday    smallint NOT NULL,
+	start_time integer NOT NULL,
+	end_time   integer NOT NULL,
+	CONSTRAINT chk_staff_working_hours_weekday CHECK (weekday BETWEEN 0 AND 6),
+	CONSTRAINT chk_staff_working_hours_time CHECK (start_time >= 0 AND end_time <= 1440 AND end_time > start_time)
+);
+CREATE INDEX idx_staff_working_hours_staff_id ON staff_working_hours (staff_id);
+
+CREATE TABLE staff_breaks (
+	id         bigserial PRIMARY KEY,
+	staff_id   bigint NOT NULL REFERENCES staff (id) ON DELETE CASCADE,
+	weekday    smallint NOT NULL,
+	start_time integer NOT NULL,
+	end_time   integer NOT NULL,
+	CONSTRAINT chk_staff_breaks_weekday CHECK (weekday BETWEEN 0 AND 6),
+	CONSTRAINT chk_staff_breaks_time CHECK (start_time >= 0 AND end_time <= 1440 AND end_time > start_time)
+);
+CREATE INDEX idx_staff_breaks_staff_id ON staff_breaks (staff_id);
+
+CREATE TABLE staff_time_off (
+	id         bigserial PRIMARY KEY,
+	staff_id   bigint NOT NULL REFERENCES staff (id) ON DELETE CASCADE,
+	date       date NOT NULL,
+	start_time integer,
+	end_time   integer,
+	reason     varchar(255),
+	created_at timestamptz,
+	CONSTRAINT chk_staff_time_off_time CHECK (
+		(start_time IS NULL AND end_time IS NULL)
+		OR (start_time >= 0 AND end_time <= 1440 AND end_time > start_time)
+	)
+);
+CREATE INDEX idx_staff_time_off_staff_id ON staff_time_off (staff_id);
+CREATE INDEX idx_staff_time_off_date ON staff_time_off (date);
+
+CREATE TABLE holidays (
+	date       date PRIMARY KEY,
+	name       varchar(255) NOT NULL,
+	created_at timestamptz
+);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_appointment_items_service_id;
+ALTER TABLE appointment_items DROP COLUMN IF EXISTS service_id;
+DROP INDEX IF EXISTS idx_visit_items_service_id;
+ALTER TABLE visit_items DROP COLUMN IF EXISTS service_id;
+DROP TABLE IF EXISTS service_prices;
+DROP INDEX IF EXISTS idx_services_category;
+ALTER TABLE services DROP CONSTRAINT IF EXISTS chk_services_price;
+ALTER TABLE services DROP COLUMN IF EXISTS price;
+ALTER TABLE services DROP COLUMN IF EXISTS category;
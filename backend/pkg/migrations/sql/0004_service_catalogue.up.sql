@@ -0,0 +1,21 @@
+-- 服務項目目錄：分類、售價與調價紀錄；消費項目與預約項目可對應服務項目
+
+ALTER TABLE services ADD COLUMN category varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN price bigint NOT NULL DEFAULT 0;
+ALTER TABLE services ADD CONSTRAINT chk_services_price CHECK (price >= 0);
+CREATE INDEX idx_services_category ON services (category);
+
+CREATE TABLE service_prices (
+	id           bigserial PRIMARY KEY,
+	service_id   bigint NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+	price        bigint NOT NULL,
+	effective_at timestamptz NOT NULL,
+	changed_by   varchar(255)
+);
+CREATE INDEX idx_service_prices_service_id ON service_prices (service_id);
+
+ALTER TABLE visit_items ADD COLUMN service_id bigint REFERENCES services (id);
+CREATE INDEX idx_visit_items_service_id ON visit_items (service_id);
+
+ALTER TABLE appointment_items ADD COLUMN service_id bigint REFERENCES services (id);
+CREATE INDEX idx_appointment_items_service_id ON appointment_items (service_id);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS commission_statements_immutable ON commission_statements;
+DROP FUNCTION IF EXISTS commission_statements_immutable();
+DROP TABLE IF EXISTS commission_statements;
+DROP TABLE IF EXISTS commission_rules;
@@ -0,0 +1,40 @@
+-- 員工抽成規則與月結明細
+
+CREATE TABLE commission_rules (
+	id         bigserial PRIMARY KEY,
+	staff_id   bigint REFERENCES staff (id) ON DELETE CASCADE,
+	scope      varchar(16) NOT NULL,
+	service_id bigint REFERENCES services (id) ON DELETE CASCADE,
+	type       varchar(16) NOT NULL,
+	rate_bp    integer NOT NULL DEFAULT 0,
+	amount     bigint NOT NULL DEFAULT 0,
+	tiers      jsonb,
+	created_at timestamptz,
+	updated_at timestamptz,
+	CONSTRAINT chk_commission_rules_scope CHECK (scope IN ('service', 'retail')),
+	CONSTRAINT chk_commission_rules_type CHECK (type IN ('percentage', 'fixed', 'tiered'))
+);
+CREATE INDEX idx_commission_rules_staff_id ON commission_rules (staff_id);
+CREATE INDEX idx_commission_rules_service_id ON commission_rules (service_id);
+-- 同一員工、範圍與服務項目只能有一條規則 (空值視為「全部」)
+CREATE UNIQUE INDEX idx_commission_rules_target
+	ON commission_rules (COALESCE(staff_id, 0), scope, COALESCE(service_id, 0));
+
+CREATE TABLE commission_statements (
+	month            varchar(7) PRIMARY KEY,
+	total_commission bigint NOT NULL,
+	report           jsonb NOT NULL,
+	finalized_at     timestamptz NOT NULL,
+	finalized_by     varchar(255)
+);
+
+-- 已結算的明細禁止更新與刪除
+CREATE OR REPLACE FUNCTION commission_statements_immutable() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'commission_statements is immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER commission_statements_immutable
+	BEFORE UPDATE OR DELETE ON commission_statements
+	FOR EACH ROW EXECUTE FUNCTION commission_statements_immutable();
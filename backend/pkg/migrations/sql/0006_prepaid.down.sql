@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_visit_items_package_id;
+ALTER TABLE visit_items DROP COLUMN IF EXISTS list_price;
+ALTER TABLE visit_items DROP COLUMN IF EXISTS package_id;
+ALTER TABLE visits DROP COLUMN IF EXISTS wallet_paid;
+DROP TABLE IF EXISTS wallet_ledger;
+DROP TABLE IF EXISTS wallets;
+DROP TABLE IF EXISTS package_ledger;
+DROP TABLE IF EXISTS packages;
+DROP FUNCTION IF EXISTS ledger_append_only();
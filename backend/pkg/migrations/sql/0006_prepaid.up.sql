@@ -0,0 +1,83 @@
+-- 課程套票與儲值金，餘額只透過帳本異動變更
+
+CREATE TABLE packages (
+	id                 bigserial PRIMARY KEY,
+	customer_name      varchar(255) NOT NULL,
+	name               varchar(255) NOT NULL,
+	service_id         bigint REFERENCES services (id),
+	total_sessions     integer NOT NULL,
+	remaining_sessions integer NOT NULL,
+	price              bigint NOT NULL,
+	status             varchar(16) NOT NULL DEFAULT 'active',
+	purchased_at       timestamptz NOT NULL,
+	expires_at         timestamptz,
+	created_by         varchar(255),
+	created_at         timestamptz,
+	updated_at         timestamptz,
+	CONSTRAINT chk_packages_sessions CHECK (total_sessions > 0 AND remaining_sessions BETWEEN 0 AND total_sessions),
+	CONSTRAINT chk_packages_price CHECK (price >= 0)
+);
+CREATE INDEX idx_packages_customer_name ON packages (customer_name);
+CREATE INDEX idx_packages_service_id ON packages (service_id);
+CREATE INDEX idx_packages_status ON packages (status);
+CREATE INDEX idx_packages_expires_at ON packages (expires_at);
+
+CREATE TABLE package_ledger (
+	id         bigserial PRIMARY KEY,
+	package_id bigint NOT NULL REFERENCES packages (id),
+	type       varchar(16) NOT NULL,
+	sessions   integer NOT NULL,
+	remaining  integer NOT NULL,
+	visit_id   bigint REFERENCES visits (id),
+	note       varchar(255),
+	created_by varchar(255),
+	created_at timestamptz
+);
+CREATE INDEX idx_package_ledger_package_id ON package_ledger (package_id);
+CREATE INDEX idx_package_ledger_visit_id ON package_ledger (visit_id);
+
+CREATE TABLE wallets (
+	id            bigserial PRIMARY KEY,
+	customer_name varchar(255) NOT NULL,
+	balance       bigint NOT NULL DEFAULT 0,
+	expires_at    timestamptz,
+	created_at    timestamptz,
+	updated_at    timestamptz,
+	CONSTRAINT chk_wallets_balance CHECK (balance >= 0)
+);
+CREATE UNIQUE INDEX idx_wallets_customer_name ON wallets (customer_name);
+CREATE INDEX idx_wallets_expires_at ON wallets (expires_at);
+
+CREATE TABLE wallet_ledger (
+	id         bigserial PRIMARY KEY,
+	wallet_id  bigint NOT NULL REFERENCES wallets (id),
+	type       varchar(16) NOT NULL,
+	amount     bigint NOT NULL,
+	balance    bigint NOT NULL,
+	visit_id   bigint REFERENCES visits (id),
+	note       varchar(255),
+	created_by varchar(255),
+	created_at timestamptz
+);
+CREATE INDEX idx_wallet_ledger_wallet_id ON wallet_ledger (wallet_id);
+CREATE INDEX idx_wallet_ledger_visit_id ON wallet_ledger (visit_id);
+
+ALTER TABLE visits ADD COLUMN wallet_paid bigint NOT NULL DEFAULT 0;
+ALTER TABLE visit_items ADD COLUMN package_id bigint REFERENCES packages (id);
+ALTER TABLE visit_items ADD COLUMN list_price bigint NOT NULL DEFAULT 0;
+CREATE INDEX idx_visit_items_package_id ON visit_items (package_id);
+
+-- 帳本只能新增，禁止更新與刪除
+CREATE OR REPLACE FUNCTION ledger_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER package_ledger_append_only
+	BEFORE UPDATE OR DELETE ON package_ledger
+	FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+CREATE TRIGGER wallet_ledger_append_only
+	BEFORE UPDATE OR DELETE ON wallet_ledger
+	FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
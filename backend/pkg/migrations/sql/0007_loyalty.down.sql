@@ -0,0 +1,5 @@
+ALTER TABLE visits DROP COLUMN IF EXISTS points_earned;
+ALTER TABLE visits DROP COLUMN IF EXISTS points_discount;
+ALTER TABLE visits DROP COLUMN IF EXISTS points_redeemed;
+DROP TABLE IF EXISTS points_ledger;
+DROP TABLE IF EXISTS loyalty_accounts;
@@ -0,0 +1,34 @@
+-- 會員點數，餘額只透過帳本異動變更
+
+CREATE TABLE loyalty_accounts (
+	id            bigserial PRIMARY KEY,
+	customer_name varchar(255) NOT NULL,
+	balance       bigint NOT NULL DEFAULT 0,
+	created_at    timestamptz,
+	updated_at    timestamptz,
+	CONSTRAINT chk_loyalty_accounts_balance CHECK (balance >= 0)
+);
+CREATE UNIQUE INDEX idx_loyalty_accounts_customer_name ON loyalty_accounts (customer_name);
+
+CREATE TABLE points_ledger (
+	id         bigserial PRIMARY KEY,
+	account_id bigint NOT NULL REFERENCES loyalty_accounts (id),
+	type       varchar(16) NOT NULL,
+	points     bigint NOT NULL,
+	balance    bigint NOT NULL,
+	visit_id   bigint REFERENCES visits (id),
+	note       varchar(255),
+	created_by varchar(255),
+	created_at timestamptz
+);
+CREATE INDEX idx_points_ledger_account_id ON points_ledger (account_id);
+CREATE INDEX idx_points_ledger_visit_id ON points_ledger (visit_id);
+CREATE INDEX idx_points_ledger_created_at ON points_ledger (created_at);
+
+CREATE TRIGGER points_ledger_append_only
+	BEFORE UPDATE OR DELETE ON points_ledger
+	FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+ALTER TABLE visits ADD COLUMN points_redeemed bigint NOT NULL DEFAULT 0;
+ALTER TABLE visits ADD COLUMN points_discount bigint NOT NULL DEFAULT 0;
+ALTER TABLE visits ADD COLUMN points_earned bigint NOT NULL DEFAULT 0;
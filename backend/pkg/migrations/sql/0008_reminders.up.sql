@@ -0,0 +1,16 @@
+-- 生日與回流提醒的發送紀錄，唯一索引確保同一期間只提醒一次
+-- 實際發送時間與結果記錄在通知佇列 (outbox_messages)，提醒只有 queued 與 skipped 兩種狀態
+
+CREATE TABLE reminders (
+	id            bigserial PRIMARY KEY,
+	customer_name varchar(255) NOT NULL,
+	kind          varchar(16) NOT NULL,
+	period_key    varchar(32) NOT NULL,
+	channel       varchar(16) NOT NULL,
+	status        varchar(16) NOT NULL CHECK (status IN ('queued', 'skipped')),
+	error         text,
+	created_at    timestamptz,
+	updated_at    timestamptz
+);
+CREATE UNIQUE INDEX idx_reminders_dedup ON reminders (customer_name, kind, period_key);
+CREATE INDEX idx_reminders_status ON reminders (status);
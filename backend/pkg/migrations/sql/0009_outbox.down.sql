@@ -0,0 +1,2 @@
+ALTER TABLE reminders DROP COLUMN IF EXISTS outbox_id;
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,24 @@
+-- 通知佇列 (transactional outbox)：通知與業務資料在同一交易內寫入，由背景工作發送並重試
+
+CREATE TABLE outbox_messages (
+	id              bigserial PRIMARY KEY,
+	kind            varchar(32) NOT NULL,
+	channel         varchar(16) NOT NULL,
+	recipient       jsonb NOT NULL,
+	subject         varchar(255),
+	body            text NOT NULL,
+	status          varchar(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+	attempts        integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamptz NOT NULL,
+	last_error      text,
+	sent_at         timestamptz,
+	created_at      timestamptz,
+	updated_at      timestamptz
+);
+CREATE INDEX idx_outbox_messages_channel ON outbox_messages (channel);
+-- 背景工作只掃描待發送的通知
+CREATE INDEX idx_outbox_messages_due ON outbox_messages (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_outbox_messages_dead ON outbox_messages (updated_at) WHERE status = 'dead';
+
+ALTER TABLE reminders ADD COLUMN outbox_id bigint REFERENCES outbox_messages (id);
+CREATE INDEX idx_reminders_outbox_id ON reminders (outbox_id);
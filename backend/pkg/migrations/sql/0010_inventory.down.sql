@@ -0,0 +1,4 @@
+ALTER TABLE visit_items DROP COLUMN IF EXISTS quantity;
+ALTER TABLE visit_items DROP COLUMN IF EXISTS product_id;
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS products;
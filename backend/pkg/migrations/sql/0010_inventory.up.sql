@@ -0,0 +1,43 @@
+-- 零售商品與庫存：庫存只透過異動紀錄變更，零售消費紀錄自動扣減
+
+CREATE TABLE products (
+	id                   bigserial PRIMARY KEY,
+	sku                  varchar(64) NOT NULL,
+	name                 varchar(255) NOT NULL,
+	category             varchar(64) NOT NULL DEFAULT '',
+	price                bigint NOT NULL DEFAULT 0,
+	cost                 bigint NOT NULL DEFAULT 0,
+	stock                integer NOT NULL DEFAULT 0,
+	reorder_level        integer NOT NULL DEFAULT 0,
+	active               boolean NOT NULL DEFAULT true,
+	low_stock_alerted_at timestamptz,
+	created_at           timestamptz,
+	updated_at           timestamptz,
+	CONSTRAINT chk_products_price CHECK (price >= 0 AND cost >= 0),
+	CONSTRAINT chk_products_stock CHECK (stock >= 0 AND reorder_level >= 0)
+);
+CREATE UNIQUE INDEX idx_products_sku ON products (sku);
+CREATE INDEX idx_products_category ON products (category);
+
+CREATE TABLE stock_movements (
+	id          bigserial PRIMARY KEY,
+	product_id  bigint NOT NULL REFERENCES products (id),
+	type        varchar(16) NOT NULL CHECK (type IN ('purchase', 'sale', 'adjustment')),
+	quantity    integer NOT NULL,
+	stock_after integer NOT NULL,
+	unit_cost   bigint NOT NULL DEFAULT 0,
+	visit_id    bigint REFERENCES visits (id),
+	note        varchar(255),
+	created_by  varchar(255),
+	created_at  timestamptz
+);
+CREATE INDEX idx_stock_movements_product_id ON stock_movements (product_id);
+CREATE INDEX idx_stock_movements_visit_id ON stock_movements (visit_id);
+
+CREATE TRIGGER stock_movements_append_only
+	BEFORE UPDATE OR DELETE ON stock_movements
+	FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+ALTER TABLE visit_items ADD COLUMN product_id bigint REFERENCES products (id);
+ALTER TABLE visit_items ADD COLUMN quantity integer NOT NULL DEFAULT 1;
+CREATE INDEX idx_visit_items_product_id ON visit_items (product_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS customer_forms;
+DROP TABLE IF EXISTS customer_tags;
+DROP TABLE IF EXISTS customer_notes;
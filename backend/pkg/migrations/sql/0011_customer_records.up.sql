@@ -0,0 +1,46 @@
+-- 客戶備註、標籤與健康問卷 / 療程同意書
+
+CREATE TABLE customer_notes (
+	id            bigserial PRIMARY KEY,
+	customer_name varchar(255) NOT NULL,
+	body          text NOT NULL,
+	author        varchar(255) NOT NULL,
+	created_at    timestamptz
+);
+CREATE INDEX idx_customer_notes_customer_name ON customer_notes (customer_name);
+
+CREATE TABLE customer_tags (
+	id            bigserial PRIMARY KEY,
+	customer_name varchar(255) NOT NULL,
+	tag           varchar(32) NOT NULL,
+	created_by    varchar(255),
+	created_at    timestamptz
+);
+CREATE UNIQUE INDEX idx_customer_tags_customer_tag ON customer_tags (customer_name, tag);
+CREATE INDEX idx_customer_tags_tag ON customer_tags (tag);
+
+CREATE TABLE customer_forms (
+	id            bigserial PRIMARY KEY,
+	customer_name varchar(255) NOT NULL,
+	kind          varchar(16) NOT NULL CHECK (kind IN ('health', 'consent')),
+	health        jsonb,
+	consent       jsonb,
+	signed_name   varchar(255) NOT NULL,
+	signed_at     timestamptz NOT NULL,
+	recorded_by   varchar(255),
+	created_at    timestamptz,
+	CONSTRAINT chk_customer_forms_answers CHECK (
+		(kind = 'health' AND health IS NOT NULL AND consent IS NULL) OR
+		(kind = 'consent' AND consent IS NOT NULL AND health IS NULL)
+	)
+);
+CREATE INDEX idx_customer_forms_customer_name ON customer_forms (customer_name);
+
+-- 備註與簽署的表單只能新增
+CREATE TRIGGER customer_notes_append_only
+	BEFORE UPDATE OR DELETE ON customer_notes
+	FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+CREATE TRIGGER customer_forms_append_only
+	BEFORE UPDATE OR DELETE ON customer_forms
+	FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// EmailNotifier 透過 SMTP 寄送電子郵件
+type EmailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewEmailNotifier 創建一個 SMTP 通知管道，username 為空時不進行驗證
+func NewEmailNotifier(host string, port int, username, password, from string) *EmailNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &EmailNotifier{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		auth: auth,
+		from: from,
+	}
+}
+
+// Channel 實作 Notifier
+func (n *EmailNotifier) Channel() string {
+	return ChannelEmail
+}
+
+// Send 實作 Notifier
+// net/smtp 不支援 context，逾時由 SMTP 伺服器連線決定
+func (n *EmailNotifier) Send(_ context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return ErrNoAddress
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", n.from)
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To.Email)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	body.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	body.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{msg.To.Email}, []byte(body.String())); err != nil {
+		return fmt.Errorf("寄送電子郵件失敗: %w", err)
+	}
+	return nil
+}
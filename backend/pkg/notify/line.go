@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// linePushEndpoint LINE Messaging API 的推播端點
+const linePushEndpoint = "https://api.line.me/v2/bot/message/push"
+
+// LINENotifier 透過 LINE Messaging API 推播文字訊息給已加入官方帳號好友的客戶
+type LINENotifier struct {
+	accessToken string
+	client      *http.Client
+}
+
+// NewLINENotifier 創建一個 LINE 通知管道，accessToken 為官方帳號的 channel access token
+func NewLINENotifier(accessToken string, client *http.Client) *LINENotifier {
+	return &LINENotifier{
+		accessToken: accessToken,
+		client:      client,
+	}
+}
+
+// Channel 實作 Notifier
+func (n *LINENotifier) Channel() string {
+	return ChannelLINE
+}
+
+// Send 實作 Notifier
+func (n *LINENotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To.LineUserID == "" {
+		return ErrNoAddress
+	}
+
+	type textMessage struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	payload, err := json.Marshal(struct {
+		To       string        `json:"to"`
+		Messages []textMessage `json:"messages"`
+	}{
+		To:       msg.To.LineUserID,
+		Messages: []textMessage{{Type: "text", Text: msg.Body}},
+	})
+	if err != nil {
+		return fmt.Errorf("序列化 LINE 訊息失敗: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, linePushEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("建立 LINE 請求失敗: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.accessToken)
+
+	if err := doRequest(n.client, req); err != nil {
+		return fmt.Errorf("發送 LINE 訊息失敗: %w", err)
+	}
+	return nil
+}
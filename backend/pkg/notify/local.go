@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// LogNotifier 只將通知寫入日誌，用於開發與測試環境
+// 內容可能包含客戶姓名，因此只記錄長度；需要檢查內容時使用 FileNotifier
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier 創建一個寫入日誌的通知管道
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+// Channel 實作 Notifier
+func (n *LogNotifier) Channel() string {
+	return ChannelLog
+}
+
+// Send 實作 Notifier
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	n.logger.InfoContext(ctx, "通知", "customer", msg.To.Name, "subject", msg.Subject, "length", utf8.RuneCountInString(msg.Body))
+	return nil
+}
+
+// FileNotifier 將通知以 JSON Lines 附加到本機檔案，用於測試與人工檢查
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNotifier 創建一個寫入檔案的通知管道
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{
+		path: path,
+	}
+}
+
+// Channel 實作 Notifier
+func (n *FileNotifier) Channel() string {
+	return ChannelFile
+}
+
+// Send 實作 Notifier
+func (n *FileNotifier) Send(_ context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time `json:"sent_at"`
+	}{msg, time.Now()})
+	if err != nil {
+		return fmt.Errorf("序列化通知失敗: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("開啟通知檔案失敗: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("寫入通知檔案失敗: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// 通知管道名稱
+const (
+	ChannelLog   = "log"
+	ChannelFile  = "file"
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelLINE  = "line"
+)
+
+// ErrNoAddress 收件人缺少該管道需要的聯絡方式 (電子郵件、電話或 LINE 使用者 ID)
+var ErrNoAddress = errors.New("收件人缺少聯絡方式")
+
+// Recipient 通知對象，各管道只使用自己需要的聯絡方式
+type Recipient struct {
+	Name       string `json:"name"`
+	Phone      string `json:"phone,omitempty"`
+	Email      string `json:"email,omitempty"`
+	LineUserID string `json:"line_user_id,omitempty"`
+}
+
+// HasAddress 判斷收件人是否有該管道需要的聯絡方式，log 與 file 管道不需要聯絡方式
+func (r Recipient) HasAddress(channel string) bool {
+	switch channel {
+	case ChannelEmail:
+		return r.Email != ""
+	case ChannelSMS:
+		return r.Phone != ""
+	case ChannelLINE:
+		return r.LineUserID != ""
+	default:
+		return true
+	}
+}
+
+// Message 一則通知，Subject 只用於電子郵件
+type Message struct {
+	To      Recipient `json:"to"`
+	Subject string    `json:"subject,omitempty"`
+	Body    string    `json:"body"`
+}
+
+// Notifier 一個通知管道
+type Notifier interface {
+	// Channel 返回管道名稱
+	Channel() string
+	// Send 送出通知，收件人缺少聯絡方式時返回 ErrNoAddress
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SMSNotifier 透過簡訊業者的 HTTP API 發送簡訊
+// 以表單送出 username、password、dstaddr (收件門號) 與 smbody (內容)，
+// 與三竹簡訊等國內業者的 HTTP 介面相容
+type SMSNotifier struct {
+	endpoint string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewSMSNotifier 創建一個簡訊通知管道
+func NewSMSNotifier(endpoint, username, password string, client *http.Client) *SMSNotifier {
+	return &SMSNotifier{
+		endpoint: endpoint,
+		username: username,
+		password: password,
+		client:   client,
+	}
+}
+
+// Channel 實作 Notifier
+func (n *SMSNotifier) Channel() string {
+	return ChannelSMS
+}
+
+// Send 實作 Notifier
+func (n *SMSNotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To.Phone == "" {
+		return ErrNoAddress
+	}
+
+	form := url.Values{
+		"username": {n.username},
+		"password": {n.password},
+		"dstaddr":  {msg.To.Phone},
+		"smbody":   {msg.Body},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("建立簡訊請求失敗: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := doRequest(n.client, req); err != nil {
+		return fmt.Errorf("發送簡訊失敗: %w", err)
+	}
+	return nil
+}
+
+// doRequest 送出請求，非 2xx 回應視為失敗並附上回應內容的開頭
+func doRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
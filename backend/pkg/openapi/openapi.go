@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// spec 後端所有路由的 OpenAPI 3 規格，新增或修改路由時須同步更新
+//
+//go:embed openapi.yaml
+var spec []byte
+
+// Load 解析並驗證內嵌的 API 規格
+func Load(ctx context.Context) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("解析 API 規格失敗: %w", err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("API 規格驗證失敗: %w", err)
+	}
+
+	return doc, nil
+}
+
+// JSON 將規格轉為 JSON，供 /api/openapi.json 使用
+func JSON(doc *openapi3.T) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("輸出 API 規格失敗: %w", err)
+	}
+	return data, nil
+}
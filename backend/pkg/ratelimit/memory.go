@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// bucket 單一對象的令牌桶狀態
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	lastSeen time.Time
+}
+
+// MemoryLimiter 以程序內記憶體保存令牌桶，適用於單一實例部署
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	idleTTL   time.Duration
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter 創建一個記憶體限流器，閒置超過 idleTTL 的令牌桶會被清除
+func NewMemoryLimiter(idleTTL time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:   make(map[string]*bucket),
+		idleTTL:   idleTTL,
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow 實作 Limiter
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	var result Result
+	b.tokens, result = take(b.tokens, now.Sub(b.updated), limit)
+	b.updated = now
+	b.lastSeen = now
+	return result, nil
+}
+
+// sweep 定期清除閒置的令牌桶，避免記憶體無限成長
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > l.idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
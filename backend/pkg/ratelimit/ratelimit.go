@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit 令牌桶參數：每秒補充 Rate 個令牌，最多累積 Burst 個
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute 以每分鐘請求數建立限制
+func PerMinute(requests, burst int) Limit {
+	return Limit{Rate: float64(requests) / 60, Burst: burst}
+}
+
+// Result 一次請求的限流結果
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Limiter 令牌桶限流器，key 代表被限制的對象 (IP、使用者或路由)
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take 依經過時間補充令牌並嘗試取出一個，返回新的令牌數與結果
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+
+	if tokens >= 1 {
+		tokens--
+		return tokens, Result{Allowed: true, Remaining: int(tokens)}
+	}
+
+	wait := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	return tokens, Result{Allowed: false, Remaining: 0, RetryAfter: wait}
+}
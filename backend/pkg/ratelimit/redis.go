@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript 在 Redis 中原子地補充並取出令牌
+// KEYS[1]: 令牌桶鍵；ARGV: 每秒速率、容量、目前時間 (毫秒)、鍵存活時間 (毫秒)
+// 返回 {是否允許, 剩餘令牌, 需等待毫秒}
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil then
+	tokens = burst
+	updated = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - updated) / 1000 * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated", now)
+redis.call("PEXPIRE", KEYS[1], ttl)
+return {allowed, math.floor(tokens), wait}
+`)
+
+// RedisLimiter 以 Redis 保存令牌桶，多個實例共用相同的限制
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter 創建一個 Redis 限流器，url 格式為 redis://[:password@]host:port/db
+func NewRedisLimiter(url, prefix string) (*RedisLimiter, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("無法解析 Redis 位址: %w", err)
+	}
+
+	return &RedisLimiter{
+		client: redis.NewClient(opts),
+		prefix: prefix,
+	}, nil
+}
+
+// Allow 實作 Limiter
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	// 令牌桶從空到滿所需的時間，之後鍵即可過期
+	ttl := time.Duration(math.Ceil(float64(limit.Burst)/limit.Rate)) * time.Second
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	values, err := tokenBucketScript.Run(ctx, l.client, []string{l.prefix + key},
+		limit.Rate, limit.Burst, time.Now().UnixMilli(), ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("Redis 限流失敗: %w", err)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// Ping 確認 Redis 連線正常，可作為就緒檢查項目
+func (l *RedisLimiter) Ping(ctx context.Context) error {
+	return l.client.Ping(ctx).Err()
+}
+
+// Close 關閉 Redis 連線
+func (l *RedisLimiter) Close(context.Context) error {
+	return l.client.Close()
+}
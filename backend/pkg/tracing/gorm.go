@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey 進行中的 span 在 gorm.Statement 中的鍵
+const gormSpanKey = "tracing:span"
+
+// GormPlugin 為每個 GORM 操作建立 span，SQL 只記錄參數佔位符不含實際值
+type GormPlugin struct{}
+
+// Name 實作 gorm.Plugin
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize 註冊 GORM 各操作前後的回呼
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	tracer := otel.Tracer("gorm.io/gorm")
+	cb := db.Callback()
+
+	register := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range register {
+		operation := r.name
+		if err := r.before("tracing:before_"+operation, func(tx *gorm.DB) {
+			_, span := tracer.Start(tx.Statement.Context, "gorm."+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(semconv.DBSystemPostgreSQL),
+			)
+			tx.InstanceSet(gormSpanKey, span)
+		}); err != nil {
+			return err
+		}
+		if err := r.after("tracing:after_"+operation, endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endGormSpan 記錄 SQL、影響筆數與錯誤並結束 span
+func endGormSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(tx.Statement.SQL.String()),
+		semconv.DBCollectionName(tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
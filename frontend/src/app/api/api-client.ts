@@ -0,0 +1,1196 @@
+// 此檔案由 backend/cmd/openapi-ts 依 backend/pkg/openapi/openapi.yaml 產生，請勿手動修改
+// 重新產生：cd backend && go run ./cmd/openapi-ts
+
+import { Injectable, InjectionToken, inject } from '@angular/core';
+import { HttpClient, HttpParams } from '@angular/common/http';
+import { Observable } from 'rxjs';
+
+/** API 伺服器位址，預設為同源 (由 nginx 轉送 /api) */
+export const API_BASE_URL = new InjectionToken<string>('API_BASE_URL', {
+  providedIn: 'root',
+  factory: () => '',
+});
+
+export interface Appointment {
+  created_at: string;
+  created_by: string;
+  customer_name: string;
+  customer_phone: string;
+  end_at: string;
+  id: number;
+  items: AppointmentItem[];
+  note: string;
+  staff?: Staff;
+  staff_id: number;
+  start_at: string;
+  status: AppointmentStatus;
+  updated_at: string;
+  /** 轉換後的消費紀錄 */
+  visit_id?: number;
+}
+
+export interface AppointmentConversionInput {
+  /** 以套票扣抵的預約項目，該項目金額為 0 */
+  packages?: {
+    /** 預約項目 ID */
+    item_id: number;
+    package_id: number;
+  }[];
+}
+
+export interface AppointmentInput {
+  customer_name: string;
+  customer_phone?: string;
+  /** 未提供時為開始時間加上項目服務時間總和 */
+  end_at?: string;
+  /** 名稱、金額與時間依服務項目目錄帶入 */
+  items?: {
+    service_id: number;
+  }[];
+  note?: string;
+  staff_id: number;
+  start_at: string;
+}
+
+export interface AppointmentItem {
+  duration_minutes: number;
+  id: number;
+  name: string;
+  /** 金額 (新台幣元) */
+  price: number;
+  /** 服務項目 ID，舊資料沒有此欄位 */
+  service_id?: number;
+}
+
+export interface AppointmentList {
+  data: Appointment[];
+}
+
+export type AppointmentStatus = 'scheduled' | 'confirmed' | 'completed' | 'cancelled' | 'no_show';
+
+export type AuditAction = 'login' | 'login_failed' | 'logout' | 'search' | 'view' | 'edit' | 'export' | 'role_change';
+
+export interface AuditLog {
+  action: AuditAction;
+  actor_email: string;
+  actor_id: string;
+  created_at: string;
+  detail: string;
+  id: number;
+  ip: string;
+  target_customer: string;
+  user_agent: string;
+}
+
+export interface AuditLogList {
+  data: AuditLog[];
+  total: number;
+}
+
+export interface Availability {
+  date: string;
+  /** 當天為店休日時的名稱 */
+  holiday?: string;
+  service: Service;
+  slots: Slot[];
+}
+
+/** 一天中的時間 (HH:MM)，24:00 表示一天結束 */
+export type ClockTime = string;
+
+export interface CommissionLine {
+  commission: number;
+  customer_name: string;
+  item_name: string;
+  kind: VisitItemKind;
+  /** 抽成計算的金額，以套票扣抵的項目為目錄售價 */
+  price: number;
+  rate_bp: number;
+  /** 適用的規則，未設定表示沒有適用規則 */
+  rule_id?: number;
+  visit_date: string;
+  visit_id: number;
+}
+
+export interface CommissionReport {
+  finalized_at?: string;
+  finalized_by?: string;
+  month: string;
+  staff: StaffCommission[];
+  status: 'draft' | 'finalized';
+  total_commission: number;
+  total_revenue: number;
+  /** 未指定員工的消費金額 */
+  unassigned_revenue: number;
+}
+
+export interface CommissionRule {
+  /** 固定抽成金額 (新台幣元) */
+  amount: number;
+  created_at: string;
+  id: number;
+  /** 抽成比例 (萬分比，1000 為 10%) */
+  rate_bp: number;
+  scope: CommissionScope;
+  /** 未設定時適用範圍內所有項目 */
+  service_id?: number;
+  /** 未設定時適用所有員工 */
+  staff_id?: number;
+  tiers: CommissionTier[] | null;
+  type: CommissionType;
+  updated_at: string;
+}
+
+export interface CommissionRuleInput {
+  amount?: number;
+  rate_bp?: number;
+  scope: CommissionScope;
+  service_id?: number | null;
+  staff_id?: number | null;
+  tiers?: CommissionTier[];
+  type: CommissionType;
+}
+
+export interface CommissionRuleList {
+  data: CommissionRule[];
+}
+
+/** service 包含服務項目與加項，retail 為零售商品 */
+export type CommissionScope = 'service' | 'retail';
+
+export interface CommissionTier {
+  rate_bp: number;
+  threshold: number;
+}
+
+/** percentage 依金額比例；fixed 每個項目固定金額；tiered 依當月業績達到的最高級距比例計算全部業績 */
+export type CommissionType = 'percentage' | 'fixed' | 'tiered';
+
+export interface ComponentStatus {
+  checked_at: string;
+  /** 失敗原因，詳細錯誤只記錄在伺服器日誌 */
+  error?: 'timeout' | 'unavailable';
+  latency_ms: number;
+  status: HealthStatus;
+}
+
+export interface ConsentAnswers {
+  agreed: boolean;
+  /** 同意拍攝療程前後照片 */
+  photo_consent?: boolean;
+  /** 已說明療程風險與注意事項 */
+  risks_explained?: boolean;
+  treatment: string;
+}
+
+export interface CustomerForm {
+  consent?: ConsentAnswers;
+  created_at: string;
+  customer_name: string;
+  health?: HealthAnswers;
+  id: number;
+  kind: CustomerFormKind;
+  recorded_by: string;
+  signed_at: string;
+  /** 簽署人姓名，代簽時可能與客戶名不同 */
+  signed_name: string;
+}
+
+/** kind 為 health 時填寫 health，為 consent 時填寫 consent */
+export interface CustomerFormInput {
+  consent?: ConsentAnswers;
+  customer_name: string;
+  health?: HealthAnswers;
+  kind: CustomerFormKind;
+  signed_at: string;
+  signed_name: string;
+}
+
+/** health 為健康問卷，consent 為療程同意書 */
+export type CustomerFormKind = 'health' | 'consent';
+
+export interface CustomerFormList {
+  data: CustomerForm[];
+}
+
+export interface CustomerNote {
+  /** 作者的電子郵件 */
+  author: string;
+  body: string;
+  created_at: string;
+  customer_name: string;
+  id: number;
+}
+
+export interface CustomerNoteInput {
+  body: string;
+  customer_name: string;
+}
+
+export interface CustomerNoteList {
+  data: CustomerNote[];
+}
+
+export interface CustomerSearchResponse {
+  /** 符合的資料列，欄位順序與試算表標題列相同，尾端空白儲存格會被省略 */
+  data: string[][];
+  loyalty?: LoyaltySummary;
+  /** 客戶的標籤，只有以客戶名搜尋時提供 */
+  tags?: string[];
+}
+
+export interface CustomerTags {
+  customer_name: string;
+  tags: string[];
+}
+
+export type ErrorCode = 'INVALID_REQUEST' | 'INVALID_CREDENTIAL' | 'UNAUTHENTICATED' | 'SESSION_EXPIRED' | 'FORBIDDEN' | 'NOT_FOUND' | 'CUSTOMER_NOT_FOUND' | 'CONFLICT' | 'RATE_LIMITED' | 'UPSTREAM_UNAVAILABLE' | 'INTERNAL';
+
+export interface ErrorResponse {
+  code: ErrorCode;
+  /** 依 Accept-Language 翻譯的錯誤訊息 */
+  error: string;
+  request_id: string;
+}
+
+export interface GoogleLoginRequest {
+  /** Google Identity Services 回傳的 ID Token */
+  credential: string;
+}
+
+export interface HealthAnswers {
+  allergies?: string;
+  medications?: string;
+  other?: string;
+  pregnant?: boolean;
+  /** 近期的醫美療程或手術 */
+  recent_procedures?: string;
+  skin_conditions?: string;
+}
+
+export interface HealthReport {
+  components: Record<string, ComponentStatus>;
+  status: HealthStatus;
+}
+
+export type HealthStatus = 'up' | 'down';
+
+export interface Holiday {
+  created_at: string;
+  /** 店休日期 (當天零時 UTC) */
+  date: string;
+  name: string;
+}
+
+export interface HolidayList {
+  data: Holiday[];
+}
+
+export interface InventoryValuation {
+  as_of: string;
+  items: ProductValuation[];
+  retail_value: number;
+  total_units: number;
+  total_value: number;
+}
+
+export type LedgerType = 'purchase' | 'top_up' | 'redeem' | 'expire';
+
+export interface Liveness {
+  status: HealthStatus;
+}
+
+export interface LoginResponse {
+  activeSessions: number;
+  email: string;
+  expire_session: string;
+  isLoggedIn: boolean;
+  name: string;
+  picture: string;
+}
+
+export interface LogoutResponse {
+  logout: boolean;
+}
+
+export interface LoyaltySummary {
+  annual_spend: number;
+  balance: number;
+  /** 點數目前可折抵的金額 */
+  balance_value: number;
+  customer_name: string;
+  next_tier?: string;
+  /** 升級到下一個等級還需要的消費金額 */
+  next_tier_spend?: number;
+  /** 依近 12 個月消費總額判定的會員等級，未達任何等級時為空字串 */
+  tier: string;
+}
+
+export interface NotificationRecipient {
+  email?: string;
+  line_user_id?: string;
+  name: string;
+  phone?: string;
+}
+
+export type OutboxKind = 'reminder' | 'appointment_confirmation' | 'low_stock';
+
+export interface OutboxMessage {
+  /** 已嘗試發送的次數 */
+  attempts: number;
+  body: string;
+  channel: 'log' | 'file' | 'email' | 'sms' | 'line';
+  created_at: string;
+  id: number;
+  kind: OutboxKind;
+  last_error?: string;
+  /** 下次發送的時間，只對 pending 有意義 */
+  next_attempt_at: string;
+  recipient: NotificationRecipient;
+  sent_at?: string;
+  status: OutboxStatus;
+  subject: string;
+  updated_at: string;
+}
+
+export interface OutboxMessageList {
+  data: OutboxMessage[];
+}
+
+/** pending 為等待發送或等待重試；dead 為超過重試次數或無法發送，需要人工處理 */
+export type OutboxStatus = 'pending' | 'sent' | 'dead';
+
+export interface Package {
+  created_at: string;
+  created_by: string;
+  customer_name: string;
+  expires_at?: string;
+  id: number;
+  name: string;
+  price: number;
+  purchased_at: string;
+  remaining_sessions: number;
+  /** 可扣抵的服務項目，未設定時可扣抵任何服務項目 */
+  service_id?: number;
+  status: PackageStatus;
+  total_sessions: number;
+  updated_at: string;
+}
+
+export interface PackageInput {
+  customer_name: string;
+  /** 最後可使用的日期 */
+  expires_at?: string;
+  name: string;
+  price: number;
+  service_id?: number;
+  total_sessions: number;
+}
+
+export interface PackageLedger {
+  data: PackageLedgerEntry[];
+  package: Package;
+}
+
+export interface PackageLedgerEntry {
+  created_at: string;
+  created_by: string;
+  id: number;
+  note: string;
+  package_id: number;
+  /** 異動後的剩餘堂數 */
+  remaining: number;
+  /** 堂數異動，扣抵與到期為負數 */
+  sessions: number;
+  type: LedgerType;
+  visit_id?: number;
+}
+
+export interface PackageList {
+  data: Package[];
+}
+
+export type PackageStatus = 'active' | 'exhausted' | 'expired';
+
+export interface PointsAdjustInput {
+  customer_name: string;
+  note?: string;
+  points: number;
+}
+
+export interface PointsLedger {
+  data: PointsLedgerEntry[];
+  summary: LoyaltySummary;
+}
+
+export interface PointsLedgerEntry {
+  account_id: number;
+  /** 異動後的餘額 */
+  balance: number;
+  created_at: string;
+  created_by: string;
+  id: number;
+  note: string;
+  /** 點數異動，減少為負數 */
+  points: number;
+  type: 'earn' | 'redeem' | 'expire' | 'adjust';
+  visit_id?: number;
+}
+
+export interface Product {
+  active: boolean;
+  category: string;
+  /** 單位成本，進貨時以移動平均更新 */
+  cost: number;
+  created_at: string;
+  id: number;
+  /** 已發送低庫存通知的時間，補貨後清除 */
+  low_stock_alerted_at?: string;
+  name: string;
+  /** 售價 (新台幣元) */
+  price: number;
+  /** 庫存低於或等於此數量時提醒補貨 */
+  reorder_level: number;
+  sku: string;
+  stock: number;
+  updated_at: string;
+}
+
+export interface ProductInput {
+  active?: boolean;
+  category?: string;
+  /** 修正單位成本，未提供時新增為 0、修改維持不變 */
+  cost?: number;
+  name: string;
+  price: number;
+  reorder_level?: number;
+  sku: string;
+}
+
+export interface ProductList {
+  data: Product[];
+}
+
+export interface ProductValuation {
+  category: string;
+  name: string;
+  product_id: number;
+  /** 庫存 × 售價 */
+  retail_value: number;
+  sku: string;
+  stock: number;
+  unit_cost: number;
+  /** 庫存 × 單位成本 */
+  value: number;
+}
+
+export interface Profile {
+  activeSessions: number;
+  email: string;
+  name: string;
+  picture: string;
+}
+
+export interface Reminder {
+  channel: 'log' | 'file' | 'email' | 'sms' | 'line';
+  created_at: string;
+  customer_name: string;
+  delivery?: OutboxMessage;
+  error?: string;
+  id: number;
+  kind: ReminderKind;
+  outbox_id?: number;
+  /** 生日提醒為年份，回流提醒為最後消費日期 */
+  period_key: string;
+  status: ReminderStatus;
+  updated_at: string;
+}
+
+export interface ReminderCandidate {
+  /** 生日 (MM-DD)，只有生日提醒有 */
+  birthday?: string;
+  customer_name: string;
+  /** 距離最後消費的天數 */
+  days_since: number;
+  /** 距離生日的天數 */
+  days_until: number;
+  kind: ReminderKind;
+  /** 最後消費日期，只有回流提醒有 */
+  last_visit?: string;
+  phone?: string;
+}
+
+export type ReminderKind = 'birthday' | 'reactivation';
+
+export interface ReminderList {
+  data: Reminder[];
+}
+
+export interface ReminderLists {
+  birthdays: ReminderCandidate[];
+  date: string;
+  reactivation: ReminderCandidate[];
+}
+
+export interface ReminderRunResult {
+  already_sent: number;
+  channel: string;
+  date: string;
+  /** 加入通知佇列的提醒數 */
+  queued: number;
+  /** 客戶缺少該管道聯絡方式而略過的提醒數 */
+  skipped: number;
+}
+
+/** queued 表示已加入通知佇列，發送結果見 delivery；skipped 表示客戶缺少該管道的聯絡方式。 */
+export type ReminderStatus = 'queued' | 'skipped';
+
+export interface Service {
+  active: boolean;
+  category: string;
+  created_at: string;
+  duration_minutes: number;
+  id: number;
+  name: string;
+  /** 目前售價 (新台幣元) */
+  price: number;
+  updated_at: string;
+}
+
+export interface ServiceInput {
+  active?: boolean;
+  category?: string;
+  duration_minutes: number;
+  name: string;
+  price: number;
+}
+
+export interface ServiceList {
+  data: Service[];
+}
+
+export interface ServicePrice {
+  changed_by: string;
+  effective_at: string;
+  id: number;
+  price: number;
+  service_id: number;
+}
+
+export interface ServicePriceList {
+  data: ServicePrice[];
+}
+
+export interface SheetCacheStatus {
+  fetched_at?: string;
+  loaded: boolean;
+  /** 試算表在 Drive 上的修改時間 */
+  revision?: string;
+  rows?: number;
+}
+
+export interface Slot {
+  end_at: string;
+  staff_id: number;
+  staff_name: string;
+  start_at: string;
+}
+
+export interface Staff {
+  active: boolean;
+  created_at: string;
+  id: number;
+  name: string;
+  updated_at: string;
+  /** 對應的系統使用者 */
+  user_id?: string;
+}
+
+export interface StaffCommission {
+  commission: number;
+  lines: CommissionLine[];
+  retail_revenue: number;
+  service_revenue: number;
+  staff_id: number;
+  staff_name: string;
+}
+
+export interface StaffInput {
+  active?: boolean;
+  name: string;
+  user_id?: string | null;
+}
+
+export interface StaffList {
+  data: Staff[];
+}
+
+export interface StockMovement {
+  created_at: string;
+  created_by: string;
+  id: number;
+  note: string;
+  product_id: number;
+  /** 庫存增加為正、減少為負 */
+  quantity: number;
+  stock_after: number;
+  type: StockMovementType;
+  /** 進貨時為進貨單價，其餘為異動當時的單位成本 */
+  unit_cost: number;
+  visit_id?: number;
+}
+
+export interface StockMovementInput {
+  /** 盤點調整必須說明原因 */
+  note?: string;
+  /** 進貨為正數；盤點調整增加為正、減少為負 */
+  quantity: number;
+  type: 'purchase' | 'adjustment';
+  /** 進貨單價 */
+  unit_cost?: number;
+}
+
+export interface StockMovementList {
+  data: StockMovement[];
+}
+
+/** sale 由消費紀錄自動產生 */
+export type StockMovementType = 'purchase' | 'sale' | 'adjustment';
+
+export interface TagCount {
+  customers: number;
+  tag: string;
+}
+
+export interface TagCountList {
+  data: TagCount[];
+}
+
+export interface TimeOff {
+  created_at: string;
+  /** 休假日期 (當天零時 UTC) */
+  date: string;
+  end?: ClockTime;
+  id: number;
+  reason: string;
+  staff_id: number;
+  start?: ClockTime;
+}
+
+export interface TimeOffInput {
+  date: string;
+  end?: ClockTime;
+  reason?: string;
+  start?: ClockTime;
+}
+
+export interface TimeOffList {
+  data: TimeOff[];
+}
+
+export interface TopUpInput {
+  amount: number;
+  customer_name: string;
+  note?: string;
+}
+
+export interface Visit {
+  appointment_id?: number;
+  created_at: string;
+  created_by: string;
+  customer_name: string;
+  id: number;
+  items: VisitItem[];
+  note: string;
+  /** 點數折抵的金額 */
+  points_discount: number;
+  /** 本次累積的點數 */
+  points_earned: number;
+  /** 折抵使用的點數 */
+  points_redeemed: number;
+  staff_id?: number;
+  staff_name: string;
+  /** 總額 (新台幣元) */
+  total: number;
+  /** 消費日期 (當天零時 UTC) */
+  visit_date: string;
+  /** 以儲值金支付的金額 */
+  wallet_paid: number;
+}
+
+export interface VisitInput {
+  customer_name: string;
+  items: {
+    kind?: VisitItemKind;
+    /** 以此套票扣抵一堂，項目金額為 0 */
+    package_id?: number;
+    /** 零售商品，寫入時自動扣減庫存；庫存不足時返回 409 */
+    product_id?: number;
+    /** 商品數量，未提供時為 1 */
+    quantity?: number;
+    service_id?: number;
+  }[];
+  note?: string;
+  /** 折抵使用的點數，折抵金額不可超過總額 */
+  points_redeemed?: number;
+  staff_id?: number;
+  /** 消費日期，未提供時為營業時區的今天 */
+  visit_date?: string;
+  /** 以儲值金支付的金額，與點數折抵金額合計不可超過總額 */
+  wallet_amount?: number;
+}
+
+export interface VisitItem {
+  id: number;
+  kind: VisitItemKind;
+  /** 以套票扣抵時的目錄售價，項目金額為 0 */
+  list_price?: number;
+  name: string;
+  /** 以套票扣抵時的套票 */
+  package_id?: number;
+  /** 項目金額 (數量 × 單價) */
+  price: number;
+  /** 零售項目的商品 */
+  product_id?: number;
+  quantity?: number;
+  /** 對應的服務項目，舊資料或預約轉入的項目可能沒有 */
+  service_id?: number;
+}
+
+/** 對應試算表的「服務項目」、「加項」與「零售」 */
+export type VisitItemKind = 'service' | 'extra' | 'retail';
+
+export interface VisitList {
+  data: Visit[];
+}
+
+export interface Wallet {
+  balance: number;
+  created_at: string;
+  customer_name: string;
+  expires_at?: string;
+  id: number;
+  updated_at: string;
+}
+
+export interface WalletLedger {
+  data: WalletLedgerEntry[];
+  wallet: Wallet;
+}
+
+export interface WalletLedgerEntry {
+  /** 金額異動，扣抵與到期為負數 */
+  amount: number;
+  /** 異動後的餘額 */
+  balance: number;
+  created_at: string;
+  created_by: string;
+  id: number;
+  note: string;
+  type: LedgerType;
+  visit_id?: number;
+  wallet_id: number;
+}
+
+export interface WeeklySchedule {
+  breaks: WeeklySpan[];
+  working_hours: WeeklySpan[];
+}
+
+export interface WeeklySpan {
+  end: ClockTime;
+  start: ClockTime;
+  /** 0 為星期日 */
+  weekday: number;
+}
+
+@Injectable({ providedIn: 'root' })
+export class ApiClient {
+  private http = inject(HttpClient);
+  private baseUrl = inject(API_BASE_URL);
+
+  /** 存活檢查 (舊路徑，同 /healthz) @deprecated */
+  getHealth(): Observable<Liveness> {
+    return this.http.get<Liveness>(`${this.baseUrl}/api/health`, { withCredentials: true });
+  }
+
+  /** 本 API 規格 (JSON) */
+  getOpenAPISpec(): Observable<Record<string, unknown>> {
+    return this.http.get<Record<string, unknown>>(`${this.baseUrl}/api/openapi.json`, { withCredentials: true });
+  }
+
+  /** 查詢稽核紀錄，format=csv 時匯出 CSV */
+  listAuditLogs(query: { actor_id?: string; actor_email?: string; action?: AuditAction; customer?: string; from?: string; to?: string; limit?: number; offset?: number; format?: 'json' | 'csv' } = {}): Observable<AuditLogList> {
+    return this.http.get<AuditLogList>(`${this.baseUrl}/api/v1/admin/audit`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 列出抽成規則 */
+  listCommissionRules(): Observable<CommissionRuleList> {
+    return this.http.get<CommissionRuleList>(`${this.baseUrl}/api/v1/admin/commission-rules`, { withCredentials: true });
+  }
+
+  /** 新增抽成規則 */
+  createCommissionRule(body: CommissionRuleInput): Observable<CommissionRule> {
+    return this.http.post<CommissionRule>(`${this.baseUrl}/api/v1/admin/commission-rules`, body, { withCredentials: true });
+  }
+
+  /** 刪除抽成規則 */
+  deleteCommissionRule(id: string): Observable<void> {
+    return this.http.delete<void>(`${this.baseUrl}/api/v1/admin/commission-rules/${encodeURIComponent(id)}`, { withCredentials: true });
+  }
+
+  /** 修改抽成規則，已結算的月份不受影響 */
+  updateCommissionRule(id: string, body: CommissionRuleInput): Observable<CommissionRule> {
+    return this.http.put<CommissionRule>(`${this.baseUrl}/api/v1/admin/commission-rules/${encodeURIComponent(id)}`, body, { withCredentials: true });
+  }
+
+  /** 月份抽成報表 */
+  getCommissionReport(month: string): Observable<CommissionReport> {
+    return this.http.get<CommissionReport>(`${this.baseUrl}/api/v1/admin/commissions/${encodeURIComponent(month)}`, { withCredentials: true });
+  }
+
+  /** 結算月份 */
+  closeCommissionMonth(month: string): Observable<CommissionReport> {
+    return this.http.post<CommissionReport>(`${this.baseUrl}/api/v1/admin/commissions/${encodeURIComponent(month)}/close`, null, { withCredentials: true });
+  }
+
+  /** 列出店休日 */
+  listHolidays(query: { from?: string; to?: string } = {}): Observable<HolidayList> {
+    return this.http.get<HolidayList>(`${this.baseUrl}/api/v1/admin/holidays`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 刪除店休日 */
+  deleteHoliday(date: string): Observable<void> {
+    return this.http.delete<void>(`${this.baseUrl}/api/v1/admin/holidays/${encodeURIComponent(date)}`, { withCredentials: true });
+  }
+
+  /** 設定店休日，已存在時更新名稱 */
+  saveHoliday(date: string, body: {
+    name: string;
+  }): Observable<Holiday> {
+    return this.http.put<Holiday>(`${this.baseUrl}/api/v1/admin/holidays/${encodeURIComponent(date)}`, body, { withCredentials: true });
+  }
+
+  /** 列出上架中且庫存已到補貨點的商品 */
+  listLowStockProducts(): Observable<ProductList> {
+    return this.http.get<ProductList>(`${this.baseUrl}/api/v1/admin/inventory/low-stock`, { withCredentials: true });
+  }
+
+  /** 庫存價值報表 */
+  getInventoryValuation(): Observable<InventoryValuation> {
+    return this.http.get<InventoryValuation>(`${this.baseUrl}/api/v1/admin/inventory/valuation`, { withCredentials: true });
+  }
+
+  /** 人工調整客戶點數 */
+  adjustPoints(body: PointsAdjustInput): Observable<LoyaltySummary> {
+    return this.http.post<LoyaltySummary>(`${this.baseUrl}/api/v1/admin/loyalty/adjust`, body, { withCredentials: true });
+  }
+
+  /** 查詢通知佇列，status=dead 為發送失敗需要處理的通知 */
+  listOutboxMessages(query: { status?: OutboxStatus; channel?: 'log' | 'file' | 'email' | 'sms' | 'line'; kind?: OutboxKind; limit?: number } = {}): Observable<OutboxMessageList> {
+    return this.http.get<OutboxMessageList>(`${this.baseUrl}/api/v1/admin/outbox`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 重新發送一則發送失敗的通知 */
+  retryOutboxMessage(id: string): Observable<OutboxMessage> {
+    return this.http.post<OutboxMessage>(`${this.baseUrl}/api/v1/admin/outbox/${encodeURIComponent(id)}/retry`, null, { withCredentials: true });
+  }
+
+  /** 列出所有零售商品 (包含下架) */
+  listAllProducts(query: { category?: string } = {}): Observable<ProductList> {
+    return this.http.get<ProductList>(`${this.baseUrl}/api/v1/admin/products`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 新增零售商品，初始庫存為 0 */
+  createProduct(body: ProductInput): Observable<Product> {
+    return this.http.post<Product>(`${this.baseUrl}/api/v1/admin/products`, body, { withCredentials: true });
+  }
+
+  /** 修改零售商品，庫存不會變動；下架以 active=false 表示 */
+  updateProduct(id: string, body: ProductInput): Observable<Product> {
+    return this.http.put<Product>(`${this.baseUrl}/api/v1/admin/products/${encodeURIComponent(id)}`, body, { withCredentials: true });
+  }
+
+  /** 列出商品的庫存異動 */
+  listStockMovements(id: string, query: { limit?: number } = {}): Observable<StockMovementList> {
+    return this.http.get<StockMovementList>(`${this.baseUrl}/api/v1/admin/products/${encodeURIComponent(id)}/movements`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 登錄進貨或盤點調整 */
+  recordStockMovement(id: string, body: StockMovementInput): Observable<StockMovement> {
+    return this.http.post<StockMovement>(`${this.baseUrl}/api/v1/admin/products/${encodeURIComponent(id)}/movements`, body, { withCredentials: true });
+  }
+
+  /** 查詢生日與回流提醒的紀錄 (包含通知的發送狀態) */
+  listReminders(query: { kind?: ReminderKind; status?: ReminderStatus; limit?: number } = {}): Observable<ReminderList> {
+    return this.http.get<ReminderList>(`${this.baseUrl}/api/v1/admin/reminders`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 產生今天的生日與回流提醒名單 (不發送) */
+  previewReminders(): Observable<ReminderLists> {
+    return this.http.get<ReminderLists>(`${this.baseUrl}/api/v1/admin/reminders/preview`, { withCredentials: true });
+  }
+
+  /** 立即產生今天的提醒並加入通知佇列 */
+  runReminders(): Observable<ReminderRunResult> {
+    return this.http.post<ReminderRunResult>(`${this.baseUrl}/api/v1/admin/reminders/run`, null, { withCredentials: true });
+  }
+
+  /** 列出所有服務項目 (包含下架) */
+  listAllServices(query: { category?: string } = {}): Observable<ServiceList> {
+    return this.http.get<ServiceList>(`${this.baseUrl}/api/v1/admin/services`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 新增服務項目 */
+  createService(body: ServiceInput): Observable<Service> {
+    return this.http.post<Service>(`${this.baseUrl}/api/v1/admin/services`, body, { withCredentials: true });
+  }
+
+  /** 修改服務項目，售價變動會留下定價紀錄；下架以 active=false 表示 */
+  updateService(id: string, body: ServiceInput): Observable<Service> {
+    return this.http.put<Service>(`${this.baseUrl}/api/v1/admin/services/${encodeURIComponent(id)}`, body, { withCredentials: true });
+  }
+
+  /** 列出服務項目的定價紀錄 */
+  listServicePriceHistory(id: string): Observable<ServicePriceList> {
+    return this.http.get<ServicePriceList>(`${this.baseUrl}/api/v1/admin/services/${encodeURIComponent(id)}/prices`, { withCredentials: true });
+  }
+
+  /** 查看客戶資料快取狀態 */
+  getSheetCacheStatus(): Observable<SheetCacheStatus> {
+    return this.http.get<SheetCacheStatus>(`${this.baseUrl}/api/v1/admin/sheets/cache`, { withCredentials: true });
+  }
+
+  /** 強制重新載入客戶資料試算表 */
+  refreshSheetCache(): Observable<SheetCacheStatus> {
+    return this.http.post<SheetCacheStatus>(`${this.baseUrl}/api/v1/admin/sheets/refresh`, null, { withCredentials: true });
+  }
+
+  /** 列出所有員工 (包含停用) */
+  listAllStaff(): Observable<StaffList> {
+    return this.http.get<StaffList>(`${this.baseUrl}/api/v1/admin/staff`, { withCredentials: true });
+  }
+
+  /** 新增員工 */
+  createStaff(body: StaffInput): Observable<Staff> {
+    return this.http.post<Staff>(`${this.baseUrl}/api/v1/admin/staff`, body, { withCredentials: true });
+  }
+
+  /** 修改員工，未提供 active 時維持原狀態 */
+  updateStaff(id: string, body: StaffInput): Observable<Staff> {
+    return this.http.put<Staff>(`${this.baseUrl}/api/v1/admin/staff/${encodeURIComponent(id)}`, body, { withCredentials: true });
+  }
+
+  /** 取得員工每週排班 */
+  getStaffSchedule(id: string): Observable<WeeklySchedule> {
+    return this.http.get<WeeklySchedule>(`${this.baseUrl}/api/v1/admin/staff/${encodeURIComponent(id)}/schedule`, { withCredentials: true });
+  }
+
+  /** 取代員工每週排班 */
+  replaceStaffSchedule(id: string, body: WeeklySchedule): Observable<WeeklySchedule> {
+    return this.http.put<WeeklySchedule>(`${this.baseUrl}/api/v1/admin/staff/${encodeURIComponent(id)}/schedule`, body, { withCredentials: true });
+  }
+
+  /** 列出員工休假 */
+  listStaffTimeOff(id: string, query: { from?: string; to?: string } = {}): Observable<TimeOffList> {
+    return this.http.get<TimeOffList>(`${this.baseUrl}/api/v1/admin/staff/${encodeURIComponent(id)}/time-off`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 新增員工休假，未提供起訖時間時為整天 */
+  createStaffTimeOff(id: string, body: TimeOffInput): Observable<TimeOff> {
+    return this.http.post<TimeOff>(`${this.baseUrl}/api/v1/admin/staff/${encodeURIComponent(id)}/time-off`, body, { withCredentials: true });
+  }
+
+  /** 刪除員工休假 */
+  deleteStaffTimeOff(id: string, timeOffID: string): Observable<void> {
+    return this.http.delete<void>(`${this.baseUrl}/api/v1/admin/staff/${encodeURIComponent(id)}/time-off/${encodeURIComponent(timeOffID)}`, { withCredentials: true });
+  }
+
+  /** 查詢預約 */
+  listAppointments(query: { staff_id?: number; customer?: string; status?: AppointmentStatus; from?: string; to?: string } = {}): Observable<AppointmentList> {
+    return this.http.get<AppointmentList>(`${this.baseUrl}/api/v1/appointments`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 新增預約 */
+  createAppointment(body: AppointmentInput): Observable<Appointment> {
+    return this.http.post<Appointment>(`${this.baseUrl}/api/v1/appointments`, body, { withCredentials: true });
+  }
+
+  /** 刪除尚未轉為消費紀錄的預約 */
+  deleteAppointment(id: string): Observable<void> {
+    return this.http.delete<void>(`${this.baseUrl}/api/v1/appointments/${encodeURIComponent(id)}`, { withCredentials: true });
+  }
+
+  /** 取得預約 */
+  getAppointment(id: string): Observable<Appointment> {
+    return this.http.get<Appointment>(`${this.baseUrl}/api/v1/appointments/${encodeURIComponent(id)}`, { withCredentials: true });
+  }
+
+  /** 修改尚未結束的預約 */
+  updateAppointment(id: string, body: AppointmentInput): Observable<Appointment> {
+    return this.http.put<Appointment>(`${this.baseUrl}/api/v1/appointments/${encodeURIComponent(id)}`, body, { withCredentials: true });
+  }
+
+  /** 變更預約狀態 */
+  updateAppointmentStatus(id: string, body: {
+    status: AppointmentStatus;
+  }): Observable<Appointment> {
+    return this.http.patch<Appointment>(`${this.baseUrl}/api/v1/appointments/${encodeURIComponent(id)}/status`, body, { withCredentials: true });
+  }
+
+  /** 將已完成的預約轉為消費紀錄 */
+  convertAppointmentToVisit(id: string, body: AppointmentConversionInput): Observable<Visit> {
+    return this.http.post<Visit>(`${this.baseUrl}/api/v1/appointments/${encodeURIComponent(id)}/visit`, body, { withCredentials: true });
+  }
+
+  /** 查詢某天某項服務的可預約時段 */
+  getAvailability(query: { service: number; date: string; staff_id?: number }): Observable<Availability> {
+    return this.http.get<Availability>(`${this.baseUrl}/api/v1/availability`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 列出客戶簽署的健康問卷與療程同意書 */
+  listCustomerForms(query: { customer: string; kind?: CustomerFormKind }): Observable<CustomerFormList> {
+    return this.http.get<CustomerFormList>(`${this.baseUrl}/api/v1/customer-forms`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 登錄一份客戶簽署的表單 */
+  createCustomerForm(body: CustomerFormInput): Observable<CustomerForm> {
+    return this.http.post<CustomerForm>(`${this.baseUrl}/api/v1/customer-forms`, body, { withCredentials: true });
+  }
+
+  /** 取得一份客戶表單 */
+  getCustomerForm(id: string): Observable<CustomerForm> {
+    return this.http.get<CustomerForm>(`${this.baseUrl}/api/v1/customer-forms/${encodeURIComponent(id)}`, { withCredentials: true });
+  }
+
+  /** 列出客戶的備註 */
+  listCustomerNotes(query: { customer: string }): Observable<CustomerNoteList> {
+    return this.http.get<CustomerNoteList>(`${this.baseUrl}/api/v1/customer-notes`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 新增客戶備註，作者為登入者 */
+  createCustomerNote(body: CustomerNoteInput): Observable<CustomerNote> {
+    return this.http.post<CustomerNote>(`${this.baseUrl}/api/v1/customer-notes`, body, { withCredentials: true });
+  }
+
+  /** 列出客戶的標籤 */
+  getCustomerTags(query: { customer: string }): Observable<CustomerTags> {
+    return this.http.get<CustomerTags>(`${this.baseUrl}/api/v1/customer-tags`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 設定客戶標籤，以請求中的標籤取代目前的標籤 */
+  setCustomerTags(body: CustomerTags): Observable<CustomerTags> {
+    return this.http.put<CustomerTags>(`${this.baseUrl}/api/v1/customer-tags`, body, { withCredentials: true });
+  }
+
+  /** 以 Google ID Token 登入 */
+  loginWithGoogle(body: GoogleLoginRequest): Observable<LoginResponse> {
+    return this.http.post<LoginResponse>(`${this.baseUrl}/api/v1/login/google`, body, { withCredentials: true });
+  }
+
+  /** 登出並清除會話 */
+  logout(): Observable<LogoutResponse> {
+    return this.http.get<LogoutResponse>(`${this.baseUrl}/api/v1/logout`, { withCredentials: true });
+  }
+
+  /** 取得客戶的點數餘額、會員等級與點數異動紀錄 */
+  getLoyaltyLedger(query: { customer: string }): Observable<PointsLedger> {
+    return this.http.get<PointsLedger>(`${this.baseUrl}/api/v1/loyalty`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 列出課程套票 */
+  listPackages(query: { customer?: string } = {}): Observable<PackageList> {
+    return this.http.get<PackageList>(`${this.baseUrl}/api/v1/packages`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 購買課程套票 */
+  createPackage(body: PackageInput): Observable<Package> {
+    return this.http.post<Package>(`${this.baseUrl}/api/v1/packages`, body, { withCredentials: true });
+  }
+
+  /** 取得套票與其堂數異動紀錄 */
+  getPackageLedger(id: string): Observable<PackageLedger> {
+    return this.http.get<PackageLedger>(`${this.baseUrl}/api/v1/packages/${encodeURIComponent(id)}/ledger`, { withCredentials: true });
+  }
+
+  /** 列出上架中的零售商品 */
+  listProducts(query: { category?: string } = {}): Observable<ProductList> {
+    return this.http.get<ProductList>(`${this.baseUrl}/api/v1/products`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 取得目前登入的使用者資料 */
+  getProfile(): Observable<Profile> {
+    return this.http.get<Profile>(`${this.baseUrl}/api/v1/profile`, { withCredentials: true });
+  }
+
+  /** 列出上架中的服務項目 */
+  listServices(query: { category?: string } = {}): Observable<ServiceList> {
+    return this.http.get<ServiceList>(`${this.baseUrl}/api/v1/services`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 以客戶名或標籤搜尋試算表中的消費紀錄 */
+  searchCustomer(query: { customer?: string; tag?: string[] } = {}): Observable<CustomerSearchResponse> {
+    return this.http.get<CustomerSearchResponse>(`${this.baseUrl}/api/v1/sheets`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 列出在職員工 */
+  listStaff(): Observable<StaffList> {
+    return this.http.get<StaffList>(`${this.baseUrl}/api/v1/staff`, { withCredentials: true });
+  }
+
+  /** 列出所有標籤與使用它的客戶數 */
+  listTags(): Observable<TagCountList> {
+    return this.http.get<TagCountList>(`${this.baseUrl}/api/v1/tags`, { withCredentials: true });
+  }
+
+  /** 查詢消費紀錄 */
+  listVisits(query: { customer?: string; staff_id?: number; from?: string; to?: string } = {}): Observable<VisitList> {
+    return this.http.get<VisitList>(`${this.baseUrl}/api/v1/visits`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 新增消費紀錄 */
+  createVisit(body: VisitInput): Observable<Visit> {
+    return this.http.post<Visit>(`${this.baseUrl}/api/v1/visits`, body, { withCredentials: true });
+  }
+
+  /** 取得消費紀錄 */
+  getVisit(id: string): Observable<Visit> {
+    return this.http.get<Visit>(`${this.baseUrl}/api/v1/visits/${encodeURIComponent(id)}`, { withCredentials: true });
+  }
+
+  /** 取得客戶的儲值金餘額 */
+  getWallet(query: { customer: string }): Observable<Wallet> {
+    return this.http.get<Wallet>(`${this.baseUrl}/api/v1/wallets`, { params: toParams(query), withCredentials: true });
+  }
+
+  /** 儲值 */
+  topUpWallet(body: TopUpInput): Observable<Wallet> {
+    return this.http.post<Wallet>(`${this.baseUrl}/api/v1/wallets/top-up`, body, { withCredentials: true });
+  }
+
+  /** 取得儲值金帳戶與其異動紀錄 */
+  getWalletLedger(id: string): Observable<WalletLedger> {
+    return this.http.get<WalletLedger>(`${this.baseUrl}/api/v1/wallets/${encodeURIComponent(id)}/ledger`, { withCredentials: true });
+  }
+
+  /** 存活檢查 */
+  getLiveness(): Observable<Liveness> {
+    return this.http.get<Liveness>(`${this.baseUrl}/healthz`, { withCredentials: true });
+  }
+
+  /** 就緒檢查，探測資料庫與客戶資料來源等相依元件 */
+  getReadiness(): Observable<HealthReport> {
+    return this.http.get<HealthReport>(`${this.baseUrl}/readyz`, { withCredentials: true });
+  }
+}
+
+/** 將查詢參數轉為 HttpParams，略過未設定的值 */
+function toParams(query: object): HttpParams {
+  let params = new HttpParams();
+  for (const [key, value] of Object.entries(query)) {
+    if (value !== undefined && value !== null) {
+      params = params.set(key, String(value));
+    }
+  }
+  return params;
+}
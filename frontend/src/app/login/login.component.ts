@@ -1,7 +1,6 @@
 import { Component, AfterViewInit, ElementRef, ViewChild, OnInit } from '@angular/core';
 import { Router } from '@angular/router';
 import { CommonModule } from '@angular/common';
-import { HttpClient } from '@angular/common/http';
 import { MatCard } from '@angular/material/card';
 import { AuthService } from '../services/auth.services';
 import { environment } from '../../environments/environment';
@@ -26,8 +25,7 @@ export class LoginComponent implements OnInit, AfterViewInit{
    isProduction = environment.production;
    apiUrlversion = environment.apiUrl
 
-  private apiUrl = environment.apiUrl;
-  constructor(private router: Router, private http: HttpClient, private authService: AuthService) {}
+  constructor(private router: Router, private authService: AuthService) {}
 
   
   ngOnInit(): void {
@@ -71,7 +69,7 @@ export class LoginComponent implements OnInit, AfterViewInit{
     console.log('Google 登入成功，credential:', response);
 
     // 發送 credential 到後端驗證
-    this.http.post<any>(`${this.apiUrl}${environment.authEndpoints.googleLogin}`, { credential }, { withCredentials: true })
+    this.authService.loginWithGoogle(credential)
     .subscribe({
       next: (res) => {
         console.log("登入回應:", res);
@@ -68,12 +68,18 @@ export class SearchBarComponent {
         const structured: CustomerRecord[] =  rawRows.map(this.transformSheetRow);        
         // console.log(data);
         this.resultsChange.emit(structured); 
-        if (response.length === 0) {
+        if (structured.length === 0) {
           this.error.set('No results found');
         }
       },
       error: (err) => {
-        this.error.set('Error: ' + err.message);
+        // 查無客戶時後端回應 404 CUSTOMER_NOT_FOUND
+        if (err.status === 404 && err.error?.code === 'CUSTOMER_NOT_FOUND') {
+          this.resultsChange.emit([]);
+          this.error.set('No results found');
+          return;
+        }
+        this.error.set('Error: ' + (err.error?.error ?? err.message));
         console.error('Search error:', err);
       }
     });
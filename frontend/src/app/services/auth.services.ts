@@ -1,8 +1,7 @@
 import { Injectable } from '@angular/core';
 import { BehaviorSubject, Observable } from 'rxjs';
-import { HttpClient } from '@angular/common/http';
 import { Router } from '@angular/router';
-import { environment } from '../../environments/environment';
+import { ApiClient, LoginResponse } from '../api/api-client';
 
 export interface UserInfo {
   name: string;
@@ -18,12 +17,11 @@ export interface UserInfo {
 export class AuthService {
   private isLoggedInSubject = new BehaviorSubject<boolean>(false);
   private userInfoSubject = new BehaviorSubject<UserInfo | null>(null);
-  private apiUrl = environment.apiUrl;
 
   public isLoggedIn$ = this.isLoggedInSubject.asObservable();
   public userInfo$ = this.userInfoSubject.asObservable();
 
-  constructor(private http: HttpClient, private router: Router) {
+  constructor(private api: ApiClient, private router: Router) {
     this.loadUserFromStorage();
 
     // Add this console log to help debug
@@ -49,8 +47,8 @@ export class AuthService {
     }
   }
 
-  loginWithGoogle(credential: string): Observable<any> {
-    return this.http.post<any>(`${this.apiUrl}${environment.authEndpoints.googleLogin}`, { credential }, { withCredentials: true });
+  loginWithGoogle(credential: string): Observable<LoginResponse> {
+    return this.api.loginWithGoogle({ credential });
   }
 
   setLoggedInUser(userInfo: UserInfo): void {
@@ -1,7 +1,6 @@
 import { Injectable, signal } from '@angular/core';
-import { HttpClient, HttpParams } from '@angular/common/http';
-import { environment } from '../../environments/environment.prod';
 import { Observable } from 'rxjs';
+import { ApiClient, CustomerSearchResponse } from '../api/api-client';
 
 export interface UserData{
   id?: string;
@@ -15,12 +14,10 @@ export interface UserData{
 
 export class SearchBarService {
   overlayOpen = signal(false);
-  private apiUrl = `${environment.apiUrl}/api/sheets`;
-  constructor(private http: HttpClient) {}
+  constructor(private api: ApiClient) {}
   
-  searchByUsername(username: string): Observable<any>{
-    const params = new HttpParams().set('customer', username);
-    return this.http.get<any>(this.apiUrl, {params, withCredentials: true});
+  searchByUsername(username: string): Observable<CustomerSearchResponse>{
+    return this.api.searchCustomer({ customer: username });
   }
   toggleOverlay(): void {
     this.overlayOpen.update(value => !value);
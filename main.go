@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/oauth2"
@@ -73,7 +74,23 @@ func main() {
 	r.Run(":8080")
 }
 
-var store = sessions.NewCookieStore([]byte("123456"))
+// 舊版入口不再使用寫死的金鑰，改由環境變數提供 (與 backend 相同的 SESSION_KEY / SESSION_ENCRYPTION_KEY)
+var store = sessions.NewCookieStore(sessionKeysFromEnv()...)
+
+// sessionKeysFromEnv 從環境變數讀取會話簽章與加密金鑰，未設定時拒絕啟動
+func sessionKeysFromEnv() [][]byte {
+	hashKey := os.Getenv("SESSION_KEY")
+	encryptionKey := os.Getenv("SESSION_ENCRYPTION_KEY")
+	if len(hashKey) < 32 {
+		log.Fatal("請設定至少 32 位元組的 SESSION_KEY 環境變數")
+	}
+	switch len(encryptionKey) {
+	case 16, 24, 32:
+	default:
+		log.Fatal("請設定 16、24 或 32 位元組的 SESSION_ENCRYPTION_KEY 環境變數")
+	}
+	return [][]byte{[]byte(hashKey), []byte(encryptionKey)}
+}
 // 處理 POST /api/login/google - 由 Google Sign-In 按鈕直接調用
 func handleGoogleSignInPost(c *gin.Context) {
         var req struct {
@@ -109,8 +126,7 @@ func handleGoogleSignInPost(c *gin.Context) {
 			return
 		}
 
-		log.Printf("payload詳細資訊: %s", payload)
-        log.Printf("用戶登錄成功: %s (%s)", name, email)
+        log.Printf("用戶登錄成功: %s", sub)
 		
 		session, _ := store.Get(c.Request, "user-session")
 